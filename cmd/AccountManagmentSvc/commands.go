@@ -0,0 +1,52 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/PereRohit/util/config"
+
+	svcCfg "github.com/vatsal278/AccountManagmentSvc/internal/config"
+	"github.com/vatsal278/AccountManagmentSvc/internal/logic"
+	"github.com/vatsal278/AccountManagmentSvc/internal/repo/datasource"
+)
+
+// commands are the subcommands run in place of the service, e.g. AccountManagmentSvc reconcile -repair.
+var commands = map[string]func(args []string){
+	"reconcile": reconcile,
+	"import":    importTransactions,
+	"interest":  interest,
+}
+
+// offlineLogic connects to the database of the config for a subcommand, the message broker and the cache are left out.
+// The config is prepared the same way as for the service.
+func offlineLogic(configPath string) (logic.AccountManagmentSvcLogicIer, *sql.DB) {
+	cfg := svcCfg.Config{}
+	err := config.LoadFromJson(configPath, &cfg)
+	if err != nil {
+		fail(err)
+	}
+	err = cfg.Init()
+	if err != nil {
+		fail(err)
+	}
+	db := svcCfg.Connect(cfg.DataBase, cfg.DataBase.TableName)
+	dataSource := datasource.NewSql(svcCfg.DbSvc{Db: db}, cfg.DataBase)
+	return logic.NewAccountManagmentSvcLogic(dataSource, nil, svcCfg.MsgQueue{}, cfg), db
+}
+
+func printJson(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(v)
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vatsal278/AccountManagmentSvc/internal/importer"
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+)
+
+// importTransactions posts the transactions of a csv file and writes the report of every row to stdout as json,
+// it exits with 2 when a row was invalid or could not be posted.
+//
+//	AccountManagmentSvc import -file corrections.csv [-dry-run] [-config ./configs/config.json]
+func importTransactions(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	configPath := flags.String("config", "./configs/config.json", "path of the service config")
+	path := flags.String("file", "", "csv of account_number, amount, type and reference")
+	dryRun := flags.Bool("dry-run", false, "only validate the rows")
+	_ = flags.Parse(args)
+
+	if *path == "" {
+		fail(errors.New("-file is required"))
+	}
+	file, err := os.Open(*path)
+	if err != nil {
+		fail(err)
+	}
+	rows, err := importer.Parse(file)
+	file.Close()
+	if err != nil {
+		fail(err)
+	}
+
+	svc, db := offlineLogic(*configPath)
+	resp := svc.ImportTransactions(rows, *dryRun)
+	db.Close()
+	report, ok := resp.Data.(model.ImportReport)
+	if !ok {
+		fail(fmt.Errorf("%s", resp.Message))
+	}
+	printJson(report)
+	if report.Failed > 0 {
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// interest recomputes the monthly interest of the accounts from their ledger so finance can check what was posted,
+// and posts the months not paid yet when run with -post. The report is written to stdout as json.
+//
+//	AccountManagmentSvc interest -from 2022-01 [-to 2022-03] [-account 7] [-post] [-config ./configs/config.json]
+func interest(args []string) {
+	flags := flag.NewFlagSet("interest", flag.ExitOnError)
+	configPath := flags.String("config", "./configs/config.json", "path of the service config")
+	fromStr := flags.String("from", "", "first month of the report as YYYY-MM")
+	toStr := flags.String("to", "", "last month of the report as YYYY-MM, the first month when omitted")
+	accountNumber := flags.Int("account", 0, "account to report on, every account earning interest when omitted")
+	post := flags.Bool("post", false, "post the interest of the months not paid yet")
+	_ = flags.Parse(args)
+
+	from, err := time.Parse("2006-01", *fromStr)
+	if err != nil {
+		fail(fmt.Errorf("invalid -from %q: %w", *fromStr, err))
+	}
+	to := from
+	if *toStr != "" {
+		to, err = time.Parse("2006-01", *toStr)
+		if err != nil {
+			fail(fmt.Errorf("invalid -to %q: %w", *toStr, err))
+		}
+	}
+
+	svc, db := offlineLogic(*configPath)
+	resp := svc.InterestReport(from, to, *accountNumber, *post)
+	db.Close()
+	if resp.Data == nil {
+		fail(fmt.Errorf("%s", resp.Message))
+	}
+	printJson(resp.Data)
+}
@@ -12,6 +12,13 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			command(os.Args[2:])
+			return
+		}
+	}
+
 	cfg := svcCfg.Config{}
 	err := config.LoadFromJson("./configs/config.json", &cfg)
 	if err != nil {
@@ -23,5 +30,9 @@ func main() {
 
 	r := router.Register(svcInitCfg)
 
+	jobs := router.Jobs(svcInitCfg)
+	jobs.Start()
+	defer jobs.Stop()
+
 	server.Run(r, svcInitCfg.SvrCfg)
 }
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+)
+
+// reconcile compares the income and spends of the accounts with their ledger and writes the mismatches to stdout
+// as json, it exits with 2 when mismatches were found and left unrepaired or the journal is out of balance.
+//
+//	AccountManagmentSvc reconcile [-account 7] [-repair] [-config ./configs/config.json]
+func reconcile(args []string) {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	configPath := flags.String("config", "./configs/config.json", "path of the service config")
+	accountNumber := flags.Int("account", 0, "account to reconcile, every account when omitted")
+	repair := flags.Bool("repair", false, "set the totals found out of line to the ledger totals")
+	_ = flags.Parse(args)
+
+	svc, db := offlineLogic(*configPath)
+	resp := svc.Reconcile(*accountNumber, *repair)
+	db.Close()
+	report, ok := resp.Data.(model.ReconciliationReport)
+	if !ok {
+		fail(fmt.Errorf("%s", resp.Message))
+	}
+	printJson(report)
+	unrepaired := false
+	for _, line := range report.Mismatches {
+		unrepaired = unrepaired || line.Status == model.ReconcileMismatch
+	}
+	if unrepaired || !report.Balanced {
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,81 @@
+// Package accountnumber allocates account numbers, they are random so they do not tell how many accounts exist and
+// end in a Luhn check digit so a mistyped number is rejected instead of reaching someone else's account.
+package accountnumber
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// MinLength keeps enough numbers to pick from, MaxLength keeps the numbers within the int columns they are stored in.
+const (
+	MinLength = 6
+	MaxLength = 9
+)
+
+var ErrInvalidLength = fmt.Errorf("account number length must be between %d and %d digits", MinLength, MaxLength)
+
+var errNotPositive = errors.New("account number payload must be positive")
+
+// Generate returns a random account number of the given number of digits, the last one being its check digit.
+// The first digit is never zero so every number has the full length.
+func Generate(length int) (int, error) {
+	if length < MinLength || length > MaxLength {
+		return 0, ErrInvalidLength
+	}
+	low := pow10(length - 2)
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(9*low)))
+	if err != nil {
+		return 0, err
+	}
+	payload := low + int(n.Int64())
+	digit, err := CheckDigit(payload)
+	if err != nil {
+		return 0, err
+	}
+	return payload*10 + digit, nil
+}
+
+// CheckDigit returns the Luhn check digit appended to the payload.
+func CheckDigit(payload int) (int, error) {
+	if payload <= 0 {
+		return 0, errNotPositive
+	}
+	sum := 0
+	// the check digit takes the rightmost position, so the doubling starts with the last digit of the payload
+	for double := true; payload > 0; double = !double {
+		d := payload % 10
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		payload /= 10
+	}
+	return (10 - sum%10) % 10, nil
+}
+
+// Valid reports whether the account number ends in the check digit of the digits before it, it catches any single
+// mistyped digit and most swaps of adjacent digits.
+func Valid(number int) bool {
+	digit, err := CheckDigit(number / 10)
+	return err == nil && number%10 == digit
+}
+
+// Sequential reports whether the number is shorter than the numbers generated with the given length, so it was numbered
+// sequentially before they were and has no check digit.
+func Sequential(number int, length int) bool {
+	return number < pow10(length-1)
+}
+
+func pow10(n int) int {
+	p := 1
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
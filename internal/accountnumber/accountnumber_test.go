@@ -0,0 +1,109 @@
+package accountnumber
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload int
+		want    int
+		wantErr error
+	}{
+		{name: "Success :: reference number", payload: 7992739871, want: 3},
+		{name: "Success :: doubled digit above nine", payload: 12345, want: 5},
+		{name: "Success :: check digit zero", payload: 19, want: 0},
+		{name: "Failure :: zero payload", payload: 0, wantErr: errNotPositive},
+		{name: "Failure :: negative payload", payload: -5, wantErr: errNotPositive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckDigit(tt.payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		number int
+		want   bool
+	}{
+		{name: "Success :: valid number", number: 79927398713, want: true},
+		{name: "Success :: single digit mistyped", number: 79927398813, want: false},
+		{name: "Success :: adjacent digits swapped", number: 79927389713, want: false},
+		{name: "Success :: wrong check digit", number: 79927398710, want: false},
+		{name: "Success :: single digit", number: 0, want: false},
+		{name: "Success :: negative number", number: -18, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Valid(tt.number)
+			if got != tt.want {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSequential(t *testing.T) {
+	tests := []struct {
+		name   string
+		number int
+		length int
+		want   bool
+	}{
+		{name: "Success :: shorter number", number: 42, length: 9, want: true},
+		{name: "Success :: one digit short", number: 99999999, length: 9, want: true},
+		{name: "Success :: number of the length", number: 100000000, length: 9, want: false},
+		{name: "Success :: longer number", number: 1000000000, length: 9, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sequential(tt.number, tt.length)
+			if got != tt.want {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int
+		wantErr error
+	}{
+		{name: "Success :: minimum length", length: MinLength},
+		{name: "Success :: maximum length", length: MaxLength},
+		{name: "Failure :: too short", length: MinLength - 1, wantErr: ErrInvalidLength},
+		{name: "Failure :: too long", length: MaxLength + 1, wantErr: ErrInvalidLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got, err := Generate(tt.length)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Want: %v, Got: %v", tt.wantErr, err)
+				}
+				if err != nil {
+					return
+				}
+				if got < pow10(tt.length-1) || got >= pow10(tt.length) {
+					t.Fatalf("Want: %d digits, Got: %d", tt.length, got)
+				}
+				if !Valid(got) {
+					t.Fatalf("Want: valid number, Got: %d", got)
+				}
+			}
+		})
+	}
+}
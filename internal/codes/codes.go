@@ -48,50 +48,178 @@ const (
 	ErrUpdatingTransaction
 	ErrUpdatingServices
 	ErrRedis
+	ErrInvalidQuery
+	ErrFetchingTransactions
+	ErrIdempotencyKey
+	ErrIdempotencyInProgress
+	ErrIdempotencyKeyReused
+	ErrTransferringFunds
+	ErrSameAccountTransfer
+	ErrInvalidAmount
+	ErrReversingTransaction
+	TransactionNotFound
+	ErrAlreadyReversed
+	ErrReversalExceedsAmount
+	ErrReversalOfReversal
+	ErrUnsupportedCurrency
+	ErrCurrencyMismatch
+	ErrInsufficientFunds
+	ErrUpdatingOverdraft
+	ErrCreatingStandingOrder
+	ErrListingStandingOrders
+	ErrCancelStandingOrder
+	ErrInvalidSchedule
+	StandingOrderNotFound
+	ErrFetchingAnalytics
+	ErrCreatingBudget
+	ErrFetchingBudgets
+	ErrUpdatingBudget
+	ErrDeletingBudget
+	ErrBudgetExists
+	BudgetNotFound
+	ErrPlacingHold
+	ErrCapturingHold
+	ErrReleasingHold
+	ErrFetchingHolds
+	HoldNotFound
+	ErrHoldNotPending
+	ErrHoldExpired
+	ErrCaptureExceedsHold
+	ErrInvalidHoldExpiry
+	ErrInterestReport
+	ErrDebitLimit
+	ErrDailyDebitLimit
+	ErrDailyDebitCount
+	ErrUpdatingSpendLimits
+	ErrInvalidSpendLimits
+	ErrReconciliation
+	ErrInvalidImport
+	ErrFetchingHistory
+	NoAccountHistory
+	ErrAccountNotActive
+	ErrAccountFrozen
+	ErrAccountClosed
+	ErrInvalidTransition
+	ErrUpdatingStatus
+	ErrBalanceNotSettled
+	ErrHoldsNotSettled
+	ErrClosingAccount
+	ErrUpdatingDefault
+	ErrInvalidAccountNumber
+	ErrRoleNotAllowed
+	ErrMemberExists
+	ErrMemberNotFound
+	ErrFetchingMembers
+	ErrUpdatingMembers
+	ErrAdminOnly
 )
 
 var errCodes = map[errCode]string{
-	ErrUnauthorized:        "UnAuthorized",
-	ErrTokenExpired:        "Token is expired",
-	ErrMatchingToken:       "Compared literals are not same",
-	ErrAssertClaims:        "unable to assert claims",
-	ErrAssertUserid:        "unable to assert userid",
-	ErrUnauthorizedAgent:   "UnAuthorized user agent",
-	ErrUnauthorizedUrl:     "UnAuthorized url",
-	ErrKeyNotFound:         "unable to find this Uuid",
-	ErrEncodingFile:        "unable to json encode the data",
-	ErrConvertingToPdf:     "unable to convert to pdf format",
-	ErrIdNeeded:            "id needed",
-	ErrDecodingData:        "unable to decode the data",
-	ErrCreatingAccount:     "Problem creating account",
-	ErrEmailExists:         "Email is already in use",
-	ErrCreatingSalt:        "Unable to generate salt",
-	ErrHashPassword:        "Unable to generate hashed password",
-	Success:                "SUCCESS",
-	AccActivationInProcess: "Account activation in progress",
-	ErrFetchingUser:        "Problem fetching your account",
-	AccNotFound:            "User account was not found",
-	PassDontMatch:          "Password doesnt match",
-	IncorrectPassword:      "Incorrect Password",
-	ErrGenerateJwt:         "Unable to generate jwt token",
-	ErrLogging:             "Problem logging into your account",
-	ErrReadingReqBody:      "Unable to read request body",
-	ErrUnmarshall:          "Unable to unmarshal request body",
-	ErrParseRegDate:        "Unable to parse registration date",
-	ErrValidate:            "Validation of fields failed",
-	InvalidCredentials:     "Invalid user credentials",
-	ErrDuration:            "Error parsing time duration",
-	AccActivationErr:       "Err activating account",
-	ErrPassRegex:           "failed to match password",
-	ErrPassLowerCase:       "password must contain 1 lower case character",
-	ErrPassUpperCase:       "password must contain 1 upper case character",
-	ErrPassNumeric:         "password must contain 1 numeric character",
-	ErrPassSpecial:         "password must contain 1 special character",
-	ErrExtractMsg:          "unable to extract msg",
-	ErrAccExists:           "account already exists",
-	ErrUpdatingTransaction: "error updating transaction details",
-	ErrUpdatingServices:    "error updating services",
-	ErrRedis:               "error saving cache in redis",
+	ErrUnauthorized:          "UnAuthorized",
+	ErrTokenExpired:          "Token is expired",
+	ErrMatchingToken:         "Compared literals are not same",
+	ErrAssertClaims:          "unable to assert claims",
+	ErrAssertUserid:          "unable to assert userid",
+	ErrUnauthorizedAgent:     "UnAuthorized user agent",
+	ErrUnauthorizedUrl:       "UnAuthorized url",
+	ErrKeyNotFound:           "unable to find this Uuid",
+	ErrEncodingFile:          "unable to json encode the data",
+	ErrConvertingToPdf:       "unable to convert to pdf format",
+	ErrIdNeeded:              "id needed",
+	ErrDecodingData:          "unable to decode the data",
+	ErrCreatingAccount:       "Problem creating account",
+	ErrEmailExists:           "Email is already in use",
+	ErrCreatingSalt:          "Unable to generate salt",
+	ErrHashPassword:          "Unable to generate hashed password",
+	Success:                  "SUCCESS",
+	AccActivationInProcess:   "Account activation in progress",
+	ErrFetchingUser:          "Problem fetching your account",
+	AccNotFound:              "User account was not found",
+	PassDontMatch:            "Password doesnt match",
+	IncorrectPassword:        "Incorrect Password",
+	ErrGenerateJwt:           "Unable to generate jwt token",
+	ErrLogging:               "Problem logging into your account",
+	ErrReadingReqBody:        "Unable to read request body",
+	ErrUnmarshall:            "Unable to unmarshal request body",
+	ErrParseRegDate:          "Unable to parse registration date",
+	ErrValidate:              "Validation of fields failed",
+	InvalidCredentials:       "Invalid user credentials",
+	ErrDuration:              "Error parsing time duration",
+	AccActivationErr:         "Err activating account",
+	ErrPassRegex:             "failed to match password",
+	ErrPassLowerCase:         "password must contain 1 lower case character",
+	ErrPassUpperCase:         "password must contain 1 upper case character",
+	ErrPassNumeric:           "password must contain 1 numeric character",
+	ErrPassSpecial:           "password must contain 1 special character",
+	ErrExtractMsg:            "unable to extract msg",
+	ErrAccExists:             "account already exists",
+	ErrUpdatingTransaction:   "error updating transaction details",
+	ErrUpdatingServices:      "error updating services",
+	ErrRedis:                 "error saving cache in redis",
+	ErrInvalidQuery:          "invalid query parameters",
+	ErrFetchingTransactions:  "error fetching transactions",
+	ErrIdempotencyKey:        "error processing idempotency key",
+	ErrIdempotencyInProgress: "a request with this idempotency key is still in progress",
+	ErrIdempotencyKeyReused:  "idempotency key already used for a different request",
+	ErrTransferringFunds:     "error transferring funds",
+	ErrSameAccountTransfer:   "cannot transfer funds to the same account",
+	ErrInvalidAmount:         "amount must be greater than zero",
+	ErrReversingTransaction:  "error reversing transaction",
+	TransactionNotFound:      "transaction not found",
+	ErrAlreadyReversed:       "transaction already fully reversed",
+	ErrReversalExceedsAmount: "reversal amount exceeds the amount left to reverse",
+	ErrReversalOfReversal:    "a reversal cannot be reversed",
+	ErrUnsupportedCurrency:   "no exchange rate configured for the currency",
+	ErrCurrencyMismatch:      "transaction currency does not match the account currency",
+	ErrInsufficientFunds:     "insufficient funds",
+	ErrUpdatingOverdraft:     "error updating overdraft limit",
+	ErrCreatingStandingOrder: "error creating standing order",
+	ErrListingStandingOrders: "error fetching standing orders",
+	ErrCancelStandingOrder:   "error cancelling standing order",
+	ErrInvalidSchedule:       "schedule must be a five field cron expression that runs before the end date",
+	StandingOrderNotFound:    "standing order not found or no longer active",
+	ErrFetchingAnalytics:     "error fetching analytics",
+	ErrCreatingBudget:        "error creating budget",
+	ErrFetchingBudgets:       "error fetching budgets",
+	ErrUpdatingBudget:        "error updating budget",
+	ErrDeletingBudget:        "error deleting budget",
+	ErrBudgetExists:          "a budget already exists for the category",
+	BudgetNotFound:           "budget not found",
+	ErrPlacingHold:           "error placing hold",
+	ErrCapturingHold:         "error capturing hold",
+	ErrReleasingHold:         "error releasing hold",
+	ErrFetchingHolds:         "error fetching holds",
+	HoldNotFound:             "hold not found",
+	ErrHoldNotPending:        "hold was already captured, released or expired",
+	ErrHoldExpired:           "hold expired",
+	ErrCaptureExceedsHold:    "capture amount exceeds the held amount",
+	ErrInvalidHoldExpiry:     "hold expiry must be in the future and within the maximum hold duration",
+	ErrInterestReport:        "error computing interest",
+	ErrDebitLimit:            "debit exceeds the single debit limit of the account",
+	ErrDailyDebitLimit:       "debit exceeds the daily debit limit of the account",
+	ErrDailyDebitCount:       "daily debit count limit of the account reached",
+	ErrUpdatingSpendLimits:   "error updating spend limits",
+	ErrInvalidSpendLimits:    "daily debit count limit cannot be negative",
+	ErrReconciliation:        "error reconciling the account totals with the ledger",
+	ErrInvalidImport:         "import is not a csv of account_number, amount, type and reference or has too many rows",
+	ErrFetchingHistory:       "error fetching account history",
+	NoAccountHistory:         "no account history at the given time",
+	ErrAccountNotActive:      "account is not active yet",
+	ErrAccountFrozen:         "account is frozen, it only takes credits",
+	ErrAccountClosed:         "account is closed",
+	ErrInvalidTransition:     "account status cannot change to the given status",
+	ErrUpdatingStatus:        "error updating account status",
+	ErrBalanceNotSettled:     "account balance must be zero to close the account",
+	ErrHoldsNotSettled:       "account has pending holds, they must be captured or released to close the account",
+	ErrClosingAccount:        "error closing account",
+	ErrUpdatingDefault:       "error updating default account",
+	ErrInvalidAccountNumber:  "invalid account number, its check digit does not match",
+	ErrRoleNotAllowed:        "your role on the account does not allow this operation",
+	ErrMemberExists:          "user is a member of the account already",
+	ErrMemberNotFound:        "user is not a co-owner or viewer of the account",
+	ErrFetchingMembers:       "error fetching account members",
+	ErrUpdatingMembers:       "error updating account members",
+	ErrAdminOnly:             "only administrators may use this endpoint",
 }
 
 func GetErr(code errCode) string {
@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"github.com/PereRohit/util/config"
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/vatsal278/AccountManagmentSvc/internal/accountnumber"
+	"github.com/vatsal278/AccountManagmentSvc/internal/interest"
 	"github.com/vatsal278/AccountManagmentSvc/internal/model"
 	"github.com/vatsal278/AccountManagmentSvc/internal/repo/authentication"
 	"github.com/vatsal278/go-redis-cache"
 	"github.com/vatsal278/msgbroker/pkg/crypt"
 	"github.com/vatsal278/msgbroker/pkg/sdk"
+	"math/big"
 	"time"
 )
 
@@ -18,11 +21,21 @@ type Config struct {
 	ServiceRouteVersion string              `json:"service_route_version"`
 	ServerConfig        config.ServerConfig `json:"server_config"`
 	// add custom config structs below for any internal services
-	DataBase     DbCfg        `json:"db_svc"`
-	MessageQueue MsgQueueCfg  `json:"msg_queue"`
-	SecretKey    string       `json:"secret_key"`
-	Cookie       CookieStruct `json:"cookie"`
-	Cache        CacheCfg     `json:"cache"`
+	DataBase       DbCfg             `json:"db_svc"`
+	MessageQueue   MsgQueueCfg       `json:"msg_queue"`
+	SecretKey      string            `json:"secret_key"`
+	Cookie         CookieStruct      `json:"cookie"`
+	Cache          CacheCfg          `json:"cache"`
+	Idempotency    IdempotencyCfg    `json:"idempotency"`
+	Currency       CurrencyCfg       `json:"currency"`
+	Scheduler      SchedulerCfg      `json:"scheduler"`
+	Interest       InterestCfg       `json:"interest"`
+	Fees           FeeCfg            `json:"fees"`
+	SpendLimits    SpendLimitCfg     `json:"spend_limits"`
+	Reconciliation ReconciliationCfg `json:"reconciliation"`
+	AccountNumbers AccountNumberCfg  `json:"account_numbers"`
+	// Admins are the user ids allowed to change the overdraft limit, the spend limits and the status of any account
+	Admins []string `json:"admins"`
 }
 
 type SvcConfig struct {
@@ -46,6 +59,28 @@ type DbCfg struct {
 	Pass      string `json:"dbPass"`
 	DbName    string `json:"dbName"`
 	TableName string `json:"tableName"`
+	// TransactionTableName holds the ledger with one row per credit/debit
+	TransactionTableName string `json:"transactionTableName"`
+	// IdempotencyTableName holds the outcome of requests sent with an Idempotency-Key header
+	IdempotencyTableName string `json:"idempotencyTableName"`
+	// StandingOrderTableName holds the recurring transactions posted by the scheduler
+	StandingOrderTableName string `json:"standingOrderTableName"`
+	// BudgetTableName holds the monthly spending limits set by the users
+	BudgetTableName string `json:"budgetTableName"`
+	// HoldTableName holds the amounts reserved on the accounts until they are captured, released or expire
+	HoldTableName string `json:"holdTableName"`
+	// InterestAccrualTableName holds the interest posted per account and month
+	InterestAccrualTableName string `json:"interestAccrualTableName"`
+	// SpendLimitTableName holds the limits overridden per account
+	SpendLimitTableName string `json:"spendLimitTableName"`
+	// JournalTableName holds the balanced legs every ledger entry is posted as
+	JournalTableName string `json:"journalTableName"`
+	// InternalAccountTableName holds the chart of the internal accounts the legs are balanced against
+	InternalAccountTableName string `json:"internalAccountTableName"`
+	// AccountHistoryTableName holds a copy of the account row after every change to it
+	AccountHistoryTableName string `json:"accountHistoryTableName"`
+	// AccountMemberTableName holds the users every account is shared with and their roles
+	AccountMemberTableName string `json:"accountMemberTableName"`
 }
 type JWTSvc struct {
 	JwtSvc authentication.JWTService
@@ -58,12 +93,27 @@ type MsgQueueCfg struct {
 	NewAccountChannel       string   `json:"new_account_channel"`
 	ActivatedAccountChannel string   `json:"account_activation_channel"`
 	Key                     string   `json:"private_key"`
+	// BudgetAlertChannel receives the budget alerts, no alert is published when it is left empty
+	BudgetAlertChannel string `json:"budget_alert_channel"`
+	// AccountClosureChannel sends the accounts of deleted users to close, nothing is subscribed to when it is left empty
+	AccountClosureChannel string `json:"account_closure_channel"`
+	// ClosureResultChannel receives whether each closure was confirmed or rejected, nothing is published when it is left empty
+	ClosureResultChannel string `json:"closure_result_channel"`
 }
+
+//go:generate mockgen --build_flags=--mod=mod --destination=./../../pkg/mock/mock_msgbroker.go --package=mock github.com/vatsal278/msgbroker/pkg/sdk MsgBrokerSvcI
+
 type MsgQueue struct {
 	MsgBroker  sdk.MsgBrokerSvcI
 	PubId      string
 	Channel    string
 	PrivateKey rsa.PrivateKey
+	// BudgetAlertPubId is empty when budget alerts are disabled
+	BudgetAlertPubId   string
+	BudgetAlertChannel string
+	// ClosurePubId is empty when no closure results are published
+	ClosurePubId   string
+	ClosureChannel string
 }
 type CookieStruct struct {
 	Name      string        `json:"name"`
@@ -77,10 +127,275 @@ type CacheCfg struct {
 	Duration string `json:"duration"`
 	Time     time.Duration
 }
+type IdempotencyCfg struct {
+	Retention string `json:"retention"`
+	// PurgeInterval is how often the scheduler deletes the keys older than the retention window
+	PurgeInterval string `json:"purge_interval"`
+	Time          time.Duration
+	PurgeTime     time.Duration
+}
+type CurrencyCfg struct {
+	// Default is the ISO 4217 code given to accounts created without a currency
+	Default string `json:"default"`
+	// Rates holds the value of one unit of a currency in other currencies, e.g. {"EUR": {"USD": "1.08"}}
+	Rates map[string]map[string]string `json:"rates"`
+}
+type SchedulerCfg struct {
+	// Interval is how often the scheduler looks for standing orders that are due
+	Interval string `json:"interval"`
+	Time     time.Duration
+}
+type InterestCfg struct {
+	// Rates holds the yearly interest rate in percent per account type, e.g. {"savings": "2.5"}, other types earn no interest
+	Rates map[string]string `json:"rates"`
+	// DayCount spreads the yearly rate over the days, one of act/365 (default), act/360 and act/act
+	DayCount string `json:"day_count"`
+	// Interval is how often the scheduler looks for months the interest was not posted for yet
+	Interval string `json:"interval"`
+	Time     time.Duration
+}
+type FeeCfg struct {
+	// Rules are matched against every transaction and service change, each matching rule charges its own fee
+	Rules []FeeRule `json:"rules"`
+}
+type FeeRule struct {
+	// Name explains the fee, it is the reference of the ledger entries charging it
+	Name string `json:"name"`
+	// TransactionType charges the fee on the debits or credits posted through the transaction endpoint
+	TransactionType string `json:"transaction_type"`
+	// ServiceId restricts a transaction fee to the accounts with the service active, without a transaction type
+	// the fee is charged once when the service is added
+	ServiceId string `json:"service_id"`
+	// Type is flat or percentage
+	Type string `json:"type"`
+	// Amount is the fee of a flat rule or the share of the transaction amount in percent of a percentage rule
+	Amount string `json:"amount"`
+	// Currency is the ISO 4217 code of a flat fee, the fee is charged in the account currency when omitted
+	Currency string `json:"currency"`
+}
+type SpendLimitCfg struct {
+	// Tiers holds the default spend limits per account type, e.g. {"current": {"max_debit": "1000.00"}},
+	// accounts of other types have no limits unless they are overridden
+	Tiers map[string]model.SpendLimits `json:"tiers"`
+}
+type ReconciliationCfg struct {
+	// Interval is how often the scheduler compares the account totals with the ledger
+	Interval string `json:"interval"`
+	// Repair sets the totals found out of line to the ledger totals, they are only reported otherwise
+	Repair bool `json:"repair"`
+	Time   time.Duration
+}
+type AccountNumberCfg struct {
+	// Length is the number of digits of the random account numbers including their check digit, 9 when left out
+	Length int `json:"length"`
+}
 type CacherSvc struct {
 	Cacher redis.Cacher
 }
 
+const (
+	defaultIdempotencyRetention = 24 * time.Hour
+	defaultIdempotencyPurge     = time.Hour
+	defaultCurrency             = "USD"
+	defaultSchedulerInterval    = time.Minute
+	defaultInterestInterval     = time.Hour
+	defaultReconcileInterval    = 24 * time.Hour
+	defaultAccountNumberLength  = 9
+)
+
+const (
+	FeeFlat       = "flat"
+	FeePercentage = "percentage"
+)
+
+// Convert converts the amount between currencies using the local rate table, rounding half away from zero to the cent.
+// When only the opposite rate is configured its inverse is used, it reports false when no rate is known.
+func (c CurrencyCfg) Convert(amount model.Money, from string, to string) (model.Money, bool) {
+	if from == to {
+		return amount, true
+	}
+	rate, ok := c.rate(from, to)
+	if !ok {
+		return 0, false
+	}
+	return round(new(big.Rat).Mul(new(big.Rat).SetInt64(int64(amount)), rate)), true
+}
+
+func (c CurrencyCfg) rate(from string, to string) (*big.Rat, bool) {
+	if rate, ok := parseRate(c.Rates[from][to]); ok {
+		return rate, true
+	}
+	if rate, ok := parseRate(c.Rates[to][from]); ok {
+		return rate.Inv(rate), true
+	}
+	return nil, false
+}
+
+// Rate returns the yearly interest rate in percent of the account type, it reports false when the type earns no interest.
+func (c InterestCfg) Rate(accountType string) (*big.Rat, bool) {
+	return parseRate(c.Rates[accountType])
+}
+
+// Init applies the defaults and checks the rates and the day count convention.
+func (c *InterestCfg) Init() error {
+	if c.DayCount == "" {
+		c.DayCount = interest.Actual365
+	}
+	if _, err := interest.DaysInYear(c.DayCount, 2000); err != nil {
+		return err
+	}
+	for accountType, rate := range c.Rates {
+		if _, ok := parseRate(rate); !ok {
+			return fmt.Errorf("invalid interest rate %q for %s accounts", rate, accountType)
+		}
+	}
+	c.Time = defaultInterestInterval
+	if c.Interval != "" {
+		var err error
+		c.Time, err = time.ParseDuration(c.Interval)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Init checks the fee rules, a rule without a transaction type charges a flat fee when its service is added.
+func (c FeeCfg) Init() error {
+	names := make(map[string]bool, len(c.Rules))
+	for _, r := range c.Rules {
+		if r.Name == "" || names[r.Name] {
+			return fmt.Errorf("fee rules need a unique name, got %q", r.Name)
+		}
+		names[r.Name] = true
+		switch r.TransactionType {
+		case "debit", "credit":
+		case "":
+			if r.ServiceId == "" || r.Type != FeeFlat {
+				return fmt.Errorf("fee %s needs a transaction type, or a service id and a flat amount", r.Name)
+			}
+		default:
+			return fmt.Errorf("fee %s has an invalid transaction type %q", r.Name, r.TransactionType)
+		}
+		if r.Type != FeeFlat && r.Type != FeePercentage {
+			return fmt.Errorf("fee %s has an invalid type %q", r.Name, r.Type)
+		}
+		if _, ok := parseRate(r.Amount); !ok {
+			return fmt.Errorf("fee %s has an invalid amount %q", r.Name, r.Amount)
+		}
+		if r.Type == FeeFlat {
+			if _, err := model.ParseMoney(r.Amount); err != nil {
+				return fmt.Errorf("fee %s has an invalid amount %q", r.Name, r.Amount)
+			}
+		}
+		if r.Type == FeePercentage && r.Currency != "" {
+			return fmt.Errorf("fee %s is a percentage and cannot have a currency", r.Name)
+		}
+	}
+	return nil
+}
+
+// TransactionFees returns the rules charging a fee on a transaction of the type posted on an account with the active services.
+func (c FeeCfg) TransactionFees(transactionType string, activeServices *model.Svc) []FeeRule {
+	var rules []FeeRule
+	for _, r := range c.Rules {
+		if r.TransactionType == "" || r.TransactionType != transactionType {
+			continue
+		}
+		if r.ServiceId != "" {
+			if activeServices == nil {
+				continue
+			}
+			if _, ok := (*activeServices)[r.ServiceId]; !ok {
+				continue
+			}
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// ChargesTransactions reports whether any rule may charge a fee on a transaction of the type.
+func (c FeeCfg) ChargesTransactions(transactionType string) bool {
+	for _, r := range c.Rules {
+		if r.TransactionType != "" && r.TransactionType == transactionType {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceFees returns the rules charging a fee when the service is added to an account.
+func (c FeeCfg) ServiceFees(serviceId string) []FeeRule {
+	var rules []FeeRule
+	for _, r := range c.Rules {
+		if r.TransactionType == "" && r.ServiceId == serviceId {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// Charge returns the fee of the rule on the amount, a percentage is rounded half away from zero to the cent.
+// The fee of a flat rule is in the currency of the rule.
+func (r FeeRule) Charge(amount model.Money) model.Money {
+	if r.Type == FeeFlat {
+		fee, _ := model.ParseMoney(r.Amount)
+		return fee
+	}
+	percent, _ := parseRate(r.Amount)
+	return round(new(big.Rat).Mul(new(big.Rat).SetInt64(int64(amount)), percent.Quo(percent, big.NewRat(100, 1))))
+}
+
+// Tier returns the default spend limits of the account type.
+func (c SpendLimitCfg) Tier(accountType string) model.SpendLimits {
+	return c.Tiers[accountType]
+}
+
+// Init checks the limits of the tiers, the amounts cannot be negative as they are parsed.
+func (c SpendLimitCfg) Init() error {
+	for tier, limits := range c.Tiers {
+		if limits.MaxDailyCount < 0 {
+			return fmt.Errorf("invalid daily transaction count %d for %s accounts", limits.MaxDailyCount, tier)
+		}
+	}
+	return nil
+}
+
+// Init checks the length of the account numbers, 9 digits are drawn when it is left out.
+func (c *AccountNumberCfg) Init() error {
+	if c.Length == 0 {
+		c.Length = defaultAccountNumberLength
+	}
+	if c.Length < accountnumber.MinLength || c.Length > accountnumber.MaxLength {
+		return fmt.Errorf("%w, got %d", accountnumber.ErrInvalidLength, c.Length)
+	}
+	return nil
+}
+
+// Valid reports whether the account number may have been allocated, any number may be while they are sequential.
+// Once they are random only the numbers of their length are checked, the shorter ones were numbered sequentially before.
+func (c AccountNumberCfg) Valid(accountNumber int) bool {
+	return c.Length == 0 || accountnumber.Sequential(accountNumber, c.Length) || accountnumber.Valid(accountNumber)
+}
+
+// round rounds an amount in cents half away from zero.
+func round(r *big.Rat) model.Money {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(r.Sign())))
+	}
+	return model.Money(quo.Int64())
+}
+
+func parseRate(s string) (*big.Rat, bool) {
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 {
+		return nil, false
+	}
+	return rate, true
+}
+
 func Connect(cfg DbCfg, tableName string) *sql.DB {
 	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%s)/?charset=utf8mb4&parseTime=True", cfg.User, cfg.Pass, cfg.Host, cfg.Port)
 	db, err := sql.Open(cfg.Driver, connectionString)
@@ -107,9 +422,313 @@ func Connect(cfg DbCfg, tableName string) *sql.DB {
 	if err != nil {
 		panic(err.Error())
 	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.TransactionTableName)
+	_, err = db.Exec(x + model.TransactionSchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.IdempotencyTableName)
+	_, err = db.Exec(x + model.IdempotencySchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.StandingOrderTableName)
+	_, err = db.Exec(x + model.StandingOrderSchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.BudgetTableName)
+	_, err = db.Exec(x + model.BudgetSchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.HoldTableName)
+	_, err = db.Exec(x + model.HoldSchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.InterestAccrualTableName)
+	_, err = db.Exec(x + model.InterestAccrualSchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.SpendLimitTableName)
+	_, err = db.Exec(x + model.SpendLimitSchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.JournalTableName)
+	_, err = db.Exec(x + model.JournalSchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.InternalAccountTableName)
+	_, err = db.Exec(x + model.InternalAccountSchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("INSERT IGNORE INTO %s(code, name, account_type) VALUES(?,?,?)", cfg.InternalAccountTableName)
+	for _, account := range model.ChartOfAccounts {
+		_, err = db.Exec(x, account.Code, account.Name, account.Type)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.AccountHistoryTableName)
+	_, err = db.Exec(x + model.AccountHistorySchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	err = migrate(db, cfg, tableName)
+	if err != nil {
+		panic(err.Error())
+	}
+	// accounts opened before the history existed start it with their row as of their last update
+	x = fmt.Sprintf("INSERT INTO %s(account_number, income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status, changed_on) "+
+		"SELECT a.account_number, a.income, a.spends, a.currency, a.account_type, a.overdraft_limit, a.held, a.active_services, a.inactive_services, a.status, a.updated_on FROM %s a "+
+		"WHERE NOT EXISTS (SELECT 1 FROM %s h WHERE h.account_number = a.account_number);", cfg.AccountHistoryTableName, tableName, cfg.AccountHistoryTableName)
+	_, err = db.Exec(x)
+	if err != nil {
+		panic(err.Error())
+	}
+	x = fmt.Sprintf("create table if not exists %s", cfg.AccountMemberTableName)
+	_, err = db.Exec(x + model.MemberSchema)
+	if err != nil {
+		panic(err.Error())
+	}
+	// accounts opened before they could be shared are owned by the user who opened them
+	x = fmt.Sprintf("INSERT IGNORE INTO %s(account_number, user_id, role) SELECT account_number, user_id, ? FROM %s;", cfg.AccountMemberTableName, tableName)
+	_, err = db.Exec(x, model.RoleOwner)
+	if err != nil {
+		panic(err.Error())
+	}
 	return db
 }
 
+// migrate adds the columns of later releases to tables created by earlier ones, which create table if not exists leaves
+// as they are, drops the unique index on the user of the accounts tables holding a single account per user and posts
+// the totals of the accounts opened before the ledger as opening entries. It is run on every start and only alters
+// the tables that are not up to date.
+func migrate(db *sql.DB, cfg DbCfg, tableName string) error {
+	tables := []struct {
+		name    string
+		columns []model.Column
+	}{
+		{name: tableName, columns: model.AccountColumns},
+		{name: cfg.TransactionTableName, columns: model.TransactionColumns},
+		{name: cfg.AccountHistoryTableName, columns: model.AccountHistoryColumns},
+	}
+	for _, table := range tables {
+		for _, column := range table.columns {
+			err := addColumn(db, table.name, column)
+			if err != nil {
+				return fmt.Errorf("add column %s to %s: %w", column.Name, table.name, err)
+			}
+		}
+	}
+	err := dropUniqueIndexes(db, tableName, "user_id")
+	if err != nil {
+		return fmt.Errorf("drop unique index on user_id of %s: %w", tableName, err)
+	}
+	err = backfillOpeningBalances(db, cfg, tableName)
+	if err != nil {
+		return fmt.Errorf("post opening balances of %s: %w", tableName, err)
+	}
+	return nil
+}
+
+// backfillOpeningBalances posts the income and spends of every account without a ledger entry as an opening credit
+// and an opening debit, balanced against the opening balances account. The entries are dated at the last update of
+// the account, the totals are only known as of then, so statements and interest of earlier months do not count
+// postings made later. The balance of those accounts is summed from the ledger like any other afterwards.
+func backfillOpeningBalances(db *sql.DB, cfg DbCfg, tableName string) error {
+	q := fmt.Sprintf("SELECT a.account_number, a.income, a.spends, a.currency, a.updated_on FROM %s a "+
+		"WHERE (a.income <> 0 OR a.spends <> 0) AND NOT EXISTS (SELECT 1 FROM %s t WHERE t.account_number = a.account_number);", tableName, cfg.TransactionTableName)
+	rows, err := db.Query(q)
+	if err != nil {
+		return err
+	}
+	var accounts []model.Account
+	for rows.Next() {
+		var account model.Account
+		err = rows.Scan(&account.AccountNumber, &account.Income, &account.Spends, &account.Currency, &account.UpdatedOn)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		accounts = append(accounts, account)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		err = postOpeningBalance(db, cfg, tableName, account)
+		if err != nil {
+			return fmt.Errorf("account %d: %w", account.AccountNumber, err)
+		}
+	}
+	return nil
+}
+
+// postOpeningBalance posts the opening entries of the account under its row lock, which every posting takes first,
+// so an instance starting at the same time or a posting in flight cannot leave it with a second opening.
+func postOpeningBalance(db *sql.DB, cfg DbCfg, tableName string, account model.Account) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := fmt.Sprintf("SELECT income, spends FROM %s WHERE account_number = ? FOR UPDATE;", tableName)
+	err = tx.QueryRow(q, account.AccountNumber).Scan(&account.Income, &account.Spends)
+	if err != nil {
+		return err
+	}
+	var count int
+	q = fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE account_number = ?;", cfg.TransactionTableName)
+	err = tx.QueryRow(q, account.AccountNumber).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	openings := []model.Transaction{
+		{AccountNumber: account.AccountNumber, Amount: account.Income, Currency: account.Currency, TransactionType: "credit"},
+		{AccountNumber: account.AccountNumber, Amount: account.Spends, Currency: account.Currency, TransactionType: "debit"},
+	}
+	for _, opening := range openings {
+		if opening.Amount == 0 {
+			continue
+		}
+		q = fmt.Sprintf("INSERT INTO %s(account_number, amount, currency, transaction_type, reference, created_on) VALUES(?,?,?,?,?,?);", cfg.TransactionTableName)
+		result, err := tx.Exec(q, opening.AccountNumber, opening.Amount, opening.Currency, opening.TransactionType, model.OpeningBalanceReference, account.UpdatedOn)
+		if err != nil {
+			return err
+		}
+		opening.Id, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		opening.Contra = model.LedgerOpening
+		var args []interface{}
+		for _, leg := range opening.Legs() {
+			args = append(args, leg.TransactionId, leg.AccountNumber, leg.InternalAccount, leg.Direction, leg.Amount, leg.Currency, account.UpdatedOn)
+		}
+		q = fmt.Sprintf("INSERT INTO %s(transaction_id, account_number, internal_account, direction, amount, currency, created_on) VALUES(?,?,?,?,?,?,?),(?,?,?,?,?,?,?);", cfg.JournalTableName)
+		_, err = tx.Exec(q, args...)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// dropUniqueIndexes drops the unique indexes of the table on the column, the primary key is kept.
+func dropUniqueIndexes(db *sql.DB, table string, column string) error {
+	rows, err := db.Query("SELECT DISTINCT INDEX_NAME FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ? AND NON_UNIQUE = 0 AND INDEX_NAME != 'PRIMARY';", table, column)
+	if err != nil {
+		return err
+	}
+	var indexes []string
+	for rows.Next() {
+		var index string
+		err = rows.Scan(&index)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		indexes = append(indexes, index)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s DROP INDEX `%s`;", table, index))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumn adds the column to the table unless it has it already and then backfills the existing rows.
+func addColumn(db *sql.DB, table string, column model.Column) error {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?;", table, column.Name).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, column.Name, column.Definition))
+	if err != nil {
+		return err
+	}
+	if column.Backfill == "" {
+		return nil
+	}
+	_, err = db.Exec(fmt.Sprintf(column.Backfill, table))
+	return err
+}
+
+// Init parses the intervals of the config and prepares the fees, limits, interest and account number settings, the
+// defaults are set for whatever is left out. The service and the subcommands run with the same settings this way.
+func (cfg *Config) Init() error {
+	var err error
+	cfg.Idempotency.Time = defaultIdempotencyRetention
+	if cfg.Idempotency.Retention != "" {
+		cfg.Idempotency.Time, err = time.ParseDuration(cfg.Idempotency.Retention)
+		if err != nil {
+			return err
+		}
+	}
+	cfg.Idempotency.PurgeTime = defaultIdempotencyPurge
+	if cfg.Idempotency.PurgeInterval != "" {
+		cfg.Idempotency.PurgeTime, err = time.ParseDuration(cfg.Idempotency.PurgeInterval)
+		if err != nil {
+			return err
+		}
+	}
+	cfg.Scheduler.Time = defaultSchedulerInterval
+	if cfg.Scheduler.Interval != "" {
+		cfg.Scheduler.Time, err = time.ParseDuration(cfg.Scheduler.Interval)
+		if err != nil {
+			return err
+		}
+	}
+	err = cfg.Interest.Init()
+	if err != nil {
+		return err
+	}
+	err = cfg.Fees.Init()
+	if err != nil {
+		return err
+	}
+	err = cfg.SpendLimits.Init()
+	if err != nil {
+		return err
+	}
+	err = cfg.AccountNumbers.Init()
+	if err != nil {
+		return err
+	}
+	cfg.Reconciliation.Time = defaultReconcileInterval
+	if cfg.Reconciliation.Interval != "" {
+		cfg.Reconciliation.Time, err = time.ParseDuration(cfg.Reconciliation.Interval)
+		if err != nil {
+			return err
+		}
+	}
+	if cfg.Currency.Default == "" {
+		cfg.Currency.Default = defaultCurrency
+	}
+	for from, rates := range cfg.Currency.Rates {
+		for to, rate := range rates {
+			if _, ok := parseRate(rate); !ok {
+				return fmt.Errorf("invalid exchange rate %q from %s to %s", rate, from, to)
+			}
+		}
+	}
+	return nil
+}
+
 func InitSvcConfig(cfg Config) *SvcConfig {
 	// init required services and assign to the service struct fields
 	dataBase := Connect(cfg.DataBase, cfg.DataBase.TableName)
@@ -119,6 +738,20 @@ func InitSvcConfig(cfg Config) *SvcConfig {
 	if err != nil {
 		panic(err.Error())
 	}
+	budgetAlertId := ""
+	if cfg.MessageQueue.BudgetAlertChannel != "" {
+		budgetAlertId, err = msgBrokerSvc.RegisterPub(cfg.MessageQueue.BudgetAlertChannel)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+	closureId := ""
+	if cfg.MessageQueue.ClosureResultChannel != "" {
+		closureId, err = msgBrokerSvc.RegisterPub(cfg.MessageQueue.ClosureResultChannel)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
 	var privateKey *rsa.PrivateKey
 	pubKey := ""
 	if cfg.MessageQueue.Key != "" {
@@ -142,18 +775,28 @@ func InitSvcConfig(cfg Config) *SvcConfig {
 	if err != nil {
 		panic(err.Error())
 	}
+	if cfg.MessageQueue.AccountClosureChannel != "" {
+		err = msgBrokerSvc.RegisterSub("POST", url+"/close", pubKey, cfg.MessageQueue.AccountClosureChannel)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
 	cacher := redis.NewCacher(redis.Config{Addr: cfg.Cache.Host + ":" + cfg.Cache.Port})
 	cfg.Cache.Time, err = time.ParseDuration(cfg.Cache.Duration)
 	if err != nil {
 		panic(err.Error())
 	}
+	err = cfg.Init()
+	if err != nil {
+		panic(err.Error())
+	}
 	return &SvcConfig{
 		Cfg:                 &cfg,
 		ServiceRouteVersion: cfg.ServiceRouteVersion,
 		SvrCfg:              cfg.ServerConfig,
 		DbSvc:               DbSvc{Db: dataBase},
 		JwtSvc:              JWTSvc{JwtSvc: jwtSvc},
-		MsgBrokerSvc:        MsgQueue{MsgBroker: msgBrokerSvc, PubId: id, Channel: cfg.MessageQueue.ActivatedAccountChannel, PrivateKey: *privateKey},
+		MsgBrokerSvc:        MsgQueue{MsgBroker: msgBrokerSvc, PubId: id, Channel: cfg.MessageQueue.ActivatedAccountChannel, PrivateKey: *privateKey, BudgetAlertPubId: budgetAlertId, BudgetAlertChannel: cfg.MessageQueue.BudgetAlertChannel, ClosurePubId: closureId, ClosureChannel: cfg.MessageQueue.ClosureResultChannel},
 		Cacher:              CacherSvc{Cacher: cacher},
 	}
 }
@@ -1,21 +1,30 @@
 package handler
 
 import (
+	"fmt"
 	"github.com/PereRohit/util/log"
 	"github.com/PereRohit/util/request"
 	"github.com/PereRohit/util/response"
+	"github.com/gorilla/mux"
 	"github.com/vatsal278/AccountManagmentSvc/internal/codes"
 	"github.com/vatsal278/AccountManagmentSvc/internal/config"
+	"github.com/vatsal278/AccountManagmentSvc/internal/importer"
 	"github.com/vatsal278/AccountManagmentSvc/internal/logic"
 	"github.com/vatsal278/AccountManagmentSvc/internal/model"
 	jwtSvc "github.com/vatsal278/AccountManagmentSvc/internal/repo/authentication"
 	"github.com/vatsal278/AccountManagmentSvc/internal/repo/datasource"
 	"github.com/vatsal278/AccountManagmentSvc/pkg/session"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 const AccountManagmentSvcName = "accountManagmentSvc"
 
+// defaultAnalyticsMonths is the window of the analytics when the request leaves it open, the current month included.
+const defaultAnalyticsMonths = 6
+
 //go:generate mockgen --build_flags=--mod=mod --destination=./../../pkg/mock/mock_handler.go --package=mock github.com/vatsal278/AccountManagmentSvc/internal/handler AccountManagmentSvcHandler
 
 type AccountManagmentSvcHandler interface {
@@ -24,15 +33,40 @@ type AccountManagmentSvcHandler interface {
 	AccountSummary(w http.ResponseWriter, r *http.Request)
 	UpdateService(w http.ResponseWriter, r *http.Request)
 	UpdateTransaction(w http.ResponseWriter, r *http.Request)
+	TransactionHistory(w http.ResponseWriter, r *http.Request)
+	Transfer(w http.ResponseWriter, r *http.Request)
+	ReverseTransaction(w http.ResponseWriter, r *http.Request)
+	UpdateOverdraftLimit(w http.ResponseWriter, r *http.Request)
+	UpdateAccountStatus(w http.ResponseWriter, r *http.Request)
+	CloseAccount(w http.ResponseWriter, r *http.Request)
+	OpenAccount(w http.ResponseWriter, r *http.Request)
+	SetDefaultAccount(w http.ResponseWriter, r *http.Request)
+	UpdateSpendLimits(w http.ResponseWriter, r *http.Request)
+	Statement(w http.ResponseWriter, r *http.Request)
+	Analytics(w http.ResponseWriter, r *http.Request)
+	CreateStandingOrder(w http.ResponseWriter, r *http.Request)
+	StandingOrders(w http.ResponseWriter, r *http.Request)
+	CancelStandingOrder(w http.ResponseWriter, r *http.Request)
+	CreateBudget(w http.ResponseWriter, r *http.Request)
+	Budgets(w http.ResponseWriter, r *http.Request)
+	UpdateBudget(w http.ResponseWriter, r *http.Request)
+	DeleteBudget(w http.ResponseWriter, r *http.Request)
+	Members(w http.ResponseWriter, r *http.Request)
+	InviteMember(w http.ResponseWriter, r *http.Request)
+	RemoveMember(w http.ResponseWriter, r *http.Request)
+	PlaceHold(w http.ResponseWriter, r *http.Request)
+	CaptureHold(w http.ResponseWriter, r *http.Request)
+	ReleaseHold(w http.ResponseWriter, r *http.Request)
+	ImportTransactions(w http.ResponseWriter, r *http.Request)
 }
 
 type accountManagmentSvc struct {
 	logic logic.AccountManagmentSvcLogicIer
 }
 
-func NewAccountManagmentSvc(ds datasource.DataSourceI, jwtService jwtSvc.JWTService, msgQueue config.MsgQueue, cookie config.CookieStruct) AccountManagmentSvcHandler {
+func NewAccountManagmentSvc(ds datasource.DataSourceI, jwtService jwtSvc.JWTService, msgQueue config.MsgQueue, cfg config.Config) AccountManagmentSvcHandler {
 	svc := &accountManagmentSvc{
-		logic: logic.NewAccountManagmentSvcLogic(ds, jwtService, msgQueue, cookie),
+		logic: logic.NewAccountManagmentSvcLogic(ds, jwtService, msgQueue, cfg),
 	}
 	AddHealthChecker(svc)
 	return svc
@@ -64,6 +98,54 @@ func (svc accountManagmentSvc) CreateAccount(w http.ResponseWriter, r *http.Requ
 	response.ToJson(w, resp.Status, resp.Message, resp.Data)
 }
 
+func (svc accountManagmentSvc) CloseAccount(w http.ResponseWriter, r *http.Request) {
+	var closure model.AccountClosure
+	status, err := request.FromJson(r, &closure)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.CloseAccount(closure)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+
+func (svc accountManagmentSvc) OpenAccount(w http.ResponseWriter, r *http.Request) {
+	var data model.OpenAccount
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	resp := svc.logic.OpenAccount(idStr, data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+
+func (svc accountManagmentSvc) SetDefaultAccount(w http.ResponseWriter, r *http.Request) {
+	var data model.DefaultAccount
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	resp := svc.logic.SetDefaultAccount(idStr, data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+
 func (svc accountManagmentSvc) AccountSummary(w http.ResponseWriter, r *http.Request) {
 	id := session.GetSession(r.Context())
 	idStr, ok := id.(string)
@@ -71,7 +153,23 @@ func (svc accountManagmentSvc) AccountSummary(w http.ResponseWriter, r *http.Req
 		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
 		return
 	}
-	resp := svc.logic.AccountDetails(idStr)
+	var asOf time.Time
+	if v := r.URL.Query().Get("as_of"); v != "" {
+		var err error
+		asOf, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			log.Error(err)
+			response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+			return
+		}
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.AccountDetails(idStr, accountNumber, asOf)
 	response.ToJson(w, resp.Status, resp.Message, resp.Data)
 }
 func (svc accountManagmentSvc) UpdateService(w http.ResponseWriter, r *http.Request) {
@@ -102,3 +200,474 @@ func (svc accountManagmentSvc) UpdateTransaction(w http.ResponseWriter, r *http.
 	resp := svc.logic.UpdateTransaction(data)
 	response.ToJson(w, resp.Status, resp.Message, resp.Data)
 }
+func (svc accountManagmentSvc) Transfer(w http.ResponseWriter, r *http.Request) {
+	var data model.Transfer
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.Transfer(data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
+	var data model.Reversal
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.ReverseTransaction(data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	var data model.NewHold
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.PlaceHold(data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	var data model.CaptureHold
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.CaptureHold(data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	var data model.ReleaseHold
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.ReleaseHold(data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) UpdateOverdraftLimit(w http.ResponseWriter, r *http.Request) {
+	var data model.OverdraftLimit
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.UpdateOverdraftLimit(data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) UpdateAccountStatus(w http.ResponseWriter, r *http.Request) {
+	var data model.UpdateAccountStatus
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.UpdateAccountStatus(data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) UpdateSpendLimits(w http.ResponseWriter, r *http.Request) {
+	var data model.UpdateSpendLimits
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.UpdateSpendLimits(data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) CreateStandingOrder(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	var data model.NewStandingOrder
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.CreateStandingOrder(idStr, data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) StandingOrders(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.StandingOrders(idStr, accountNumber)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) CancelStandingOrder(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	orderId, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.CancelStandingOrder(idStr, accountNumber, orderId)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	var data model.NewBudget
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.CreateBudget(idStr, accountNumber, data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) Budgets(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.Budgets(idStr, accountNumber)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	budgetId, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	var data model.UpdateBudget
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.UpdateBudget(idStr, accountNumber, budgetId, data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	budgetId, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.DeleteBudget(idStr, accountNumber, budgetId)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) Members(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.Members(idStr, accountNumber)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) InviteMember(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	var data model.InviteMember
+	status, err := request.FromJson(r, &data)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, status, err.Error(), nil)
+		return
+	}
+	resp := svc.logic.InviteMember(idStr, accountNumber, data)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	accountNumber, err := accountNumberFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.RemoveMember(idStr, accountNumber, mux.Vars(r)["user_id"])
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+func (svc accountManagmentSvc) TransactionHistory(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	filter, err := transactionFilterFromQuery(r.URL.Query())
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.TransactionHistory(idStr, filter)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+
+// Statement downloads the monthly statement, month defaults to the previous calendar month and format to pdf.
+func (svc accountManagmentSvc) Statement(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	query := r.URL.Query()
+	month := time.Now().UTC().AddDate(0, -1, 0)
+	if v := query.Get("month"); v != "" {
+		var err error
+		month, err = time.Parse("2006-01", v)
+		if err != nil {
+			log.Error(err)
+			response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+			return
+		}
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = logic.StatementFormatPdf
+	}
+	accountNumber, err := accountNumberFromQuery(query)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.Statement(idStr, accountNumber, month, format)
+	file, ok := resp.Data.(model.StatementFile)
+	if !ok {
+		response.ToJson(w, resp.Status, resp.Message, resp.Data)
+		return
+	}
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	w.WriteHeader(resp.Status)
+	_, err = w.Write(file.Content)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// ImportTransactions posts the transactions of a csv request body, with dry_run=true the rows are only validated.
+func (svc accountManagmentSvc) ImportTransactions(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			log.Error(err)
+			response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+			return
+		}
+	}
+	defer r.Body.Close()
+	rows, err := importer.Parse(r.Body)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidImport), nil)
+		return
+	}
+	resp := svc.logic.ImportTransactions(rows, dryRun)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+
+// Analytics reports the spends per category and the income against the spends per month, from and to are months
+// (YYYY-MM) and default to the last few months.
+func (svc accountManagmentSvc) Analytics(w http.ResponseWriter, r *http.Request) {
+	id := session.GetSession(r.Context())
+	idStr, ok := id.(string)
+	if !ok {
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+		return
+	}
+	query := r.URL.Query()
+	to := time.Now().UTC()
+	var err error
+	if v := query.Get("to"); v != "" {
+		to, err = time.Parse("2006-01", v)
+		if err != nil {
+			log.Error(err)
+			response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+			return
+		}
+	}
+	from := time.Date(to.Year(), to.Month()-defaultAnalyticsMonths+1, 1, 0, 0, 0, 0, time.UTC)
+	if v := query.Get("from"); v != "" {
+		from, err = time.Parse("2006-01", v)
+		if err != nil {
+			log.Error(err)
+			response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+			return
+		}
+	}
+	accountNumber, err := accountNumberFromQuery(query)
+	if err != nil {
+		log.Error(err)
+		response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrInvalidQuery), nil)
+		return
+	}
+	resp := svc.logic.Analytics(idStr, accountNumber, from, to)
+	response.ToJson(w, resp.Status, resp.Message, resp.Data)
+}
+
+// accountNumberFromQuery reads the optional account_number query parameter, zero stands for the default account of the user.
+func accountNumberFromQuery(query url.Values) (int, error) {
+	v := query.Get("account_number")
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
+
+func transactionFilterFromQuery(query url.Values) (model.TransactionFilter, error) {
+	var filter model.TransactionFilter
+	var err error
+	filter.AccountNumber, err = accountNumberFromQuery(query)
+	if err != nil {
+		return filter, err
+	}
+	if v := query.Get("cursor"); v != "" {
+		filter.Cursor, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, err
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		filter.Limit, err = strconv.Atoi(v)
+		if err != nil {
+			return filter, err
+		}
+	}
+	if v := query.Get("from"); v != "" {
+		filter.From, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, err
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		filter.To, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, err
+		}
+	}
+	switch v := query.Get("type"); v {
+	case "", "debit", "credit":
+		filter.TransactionType = v
+	default:
+		return filter, fmt.Errorf("incorrect transaction type %s", v)
+	}
+	if v := query.Get("min_amount"); v != "" {
+		filter.MinAmount, err = model.ParseMoney(v)
+		if err != nil {
+			return filter, err
+		}
+	}
+	if v := query.Get("max_amount"); v != "" {
+		filter.MaxAmount, err = model.ParseMoney(v)
+		if err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
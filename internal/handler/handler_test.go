@@ -10,11 +10,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	respModel "github.com/PereRohit/util/model"
 	"github.com/PereRohit/util/testutil"
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 
 	"github.com/vatsal278/AccountManagmentSvc/internal/model"
 	"github.com/vatsal278/AccountManagmentSvc/pkg/mock"
@@ -210,7 +213,7 @@ func TestAccountManagmentSvc_AccountSummary(t *testing.T) {
 			name: "Success",
 			setup: func() (*accountManagmentSvc, *http.Request) {
 				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
-				mockLogic.EXPECT().AccountDetails("1234").Times(1).Return(&respModel.Response{
+				mockLogic.EXPECT().AccountDetails("1234", 0, time.Time{}).Times(1).Return(&respModel.Response{
 					Status:  http.StatusOK,
 					Message: codes.GetErr(codes.Success),
 					Data:    model.AccountSummary{},
@@ -246,7 +249,7 @@ func TestAccountManagmentSvc_AccountSummary(t *testing.T) {
 			name: "Failure:: logic :: internal server error",
 			setup: func() (*accountManagmentSvc, *http.Request) {
 				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
-				mockLogic.EXPECT().AccountDetails("1234").Return(&respModel.Response{
+				mockLogic.EXPECT().AccountDetails("1234", 0, time.Time{}).Return(&respModel.Response{
 					Status:  http.StatusInternalServerError,
 					Message: codes.GetErr(codes.ErrAssertUserid),
 					Data:    nil,
@@ -275,6 +278,95 @@ func TestAccountManagmentSvc_AccountSummary(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Success:: as of a past time",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().AccountDetails("1234", 0, time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    model.AccountSummary{},
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account?as_of=2022-03-01T12:00:00Z", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				if rec.Code != http.StatusOK {
+					t.Errorf("Want: %v, Got: %v", http.StatusOK, rec.Code)
+				}
+			},
+		},
+		{
+			name: "Success:: one account of the user",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().AccountDetails("1234", 2, time.Time{}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    []model.AccountSummary{},
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account?account_number=2", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				if rec.Code != http.StatusOK {
+					t.Errorf("Want: %v, Got: %v", http.StatusOK, rec.Code)
+				}
+			},
+		},
+		{
+			name: "Failure:: invalid account_number",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account?account_number=abc", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				if rec.Code != http.StatusBadRequest {
+					t.Errorf("Want: %v, Got: %v", http.StatusBadRequest, rec.Code)
+				}
+			},
+		},
+		{
+			name: "Failure:: invalid as_of",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account?as_of=2022-03-01", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidQuery),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
 		{
 			name: "Failure:: err asserting to string",
 			setup: func() (*accountManagmentSvc, *http.Request) {
@@ -488,7 +580,7 @@ func TestAccountManagementSvc_UpdateTransaction(t *testing.T) {
 			},
 			setup: func() (*accountManagmentSvc, *http.Request) {
 				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
-				mockLogic.EXPECT().UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 1000.00, TransactionType: "debit"}).Times(1).Return(&respModel.Response{
+				mockLogic.EXPECT().UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 100000, TransactionType: "debit"}).Times(1).Return(&respModel.Response{
 					Status:  http.StatusAccepted,
 					Message: codes.GetErr(codes.Success),
 					Data:    nil,
@@ -498,7 +590,7 @@ func TestAccountManagementSvc_UpdateTransaction(t *testing.T) {
 				}
 				by, err := json.Marshal(model.UpdateTransaction{
 					AccountNumber:   1,
-					Amount:          1000.00,
+					Amount:          100000,
 					TransactionType: "debit",
 				})
 				if err != nil {
@@ -552,6 +644,33 @@ func TestAccountManagementSvc_UpdateTransaction(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Failure :: UpdateTransaction:: sub-cent amount",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("PUT", "/account/update/transaction", bytes.NewBuffer([]byte(`{"account_number":1,"amount":10.005,"transaction_type":"debit"}`)))
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: "put data into data: amount has more than two decimal places",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
 		{
 			name: "Failure :: UpdateService:: json unmarshall failure",
 			setup: func() (*accountManagmentSvc, *http.Request) {
@@ -589,3 +708,1592 @@ func TestAccountManagementSvc_UpdateTransaction(t *testing.T) {
 		})
 	}
 }
+func TestAccountManagmentSvc_TransactionHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  func(recorder httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				from, _ := time.Parse(time.RFC3339, "2022-01-01T00:00:00Z")
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().TransactionHistory("1234", model.TransactionFilter{AccountNumber: 2, Cursor: 10, Limit: 5, From: from, TransactionType: "credit", MinAmount: 150, MaxAmount: 10000}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/transactions?account_number=2&cursor=10&limit=5&from=2022-01-01T00:00:00Z&type=credit&min_amount=1.5&max_amount=100", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+		{
+			name: "Failure :: invalid query",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/transactions?type=abc", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidQuery),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+		{
+			name: "Failure :: invalid date",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/transactions?from=yesterday", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				if rec.Code != http.StatusBadRequest {
+					t.Errorf("Want: %v, Got: %v", http.StatusBadRequest, rec.Code)
+				}
+			},
+		},
+		{
+			name: "Failure :: err assert user_id",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/transactions", nil)
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrAssertUserid),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			x.TransactionHistory(w, r)
+			tt.want(*w)
+		})
+	}
+}
+func TestAccountManagmentSvc_Transfer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  func(recorder httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().Transfer(model.Transfer{FromAccount: 1, ToAccount: 2, Amount: 10000}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				by, err := json.Marshal(model.Transfer{FromAccount: 1, ToAccount: 2, Amount: 10000})
+				if err != nil {
+					t.Fail()
+				}
+				r := httptest.NewRequest("PUT", "/account/update/transfer", bytes.NewBuffer(by))
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+		{
+			name: "Failure :: Transfer:: Read all failure",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("PUT", "/account/update/transfer", Reader(""))
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: "request body read : test error",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+		{
+			name: "Failure :: Transfer:: json unmarshall failure",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("PUT", "/account/update/transfer", bytes.NewBuffer([]byte("")))
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: "put data into data: unexpected end of JSON input",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			x.Transfer(w, r)
+			tt.want(*w)
+		})
+	}
+}
+func TestAccountManagmentSvc_ReverseTransaction(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  func(recorder httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().ReverseTransaction(model.Reversal{TransactionId: 5, Amount: 1000}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				by, err := json.Marshal(model.Reversal{TransactionId: 5, Amount: 1000})
+				if err != nil {
+					t.Fail()
+				}
+				r := httptest.NewRequest("PUT", "/account/update/reversal", bytes.NewBuffer(by))
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+		{
+			name: "Failure :: ReverseTransaction:: json unmarshall failure",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("PUT", "/account/update/reversal", bytes.NewBuffer([]byte("")))
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: "put data into data: unexpected end of JSON input",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			x.ReverseTransaction(w, r)
+			tt.want(*w)
+		})
+	}
+}
+func TestAccountManagmentSvc_UpdateOverdraftLimit(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  func(recorder httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().UpdateOverdraftLimit(model.OverdraftLimit{AccountNumber: 1, Limit: 10000}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				by, err := json.Marshal(model.OverdraftLimit{AccountNumber: 1, Limit: 10000})
+				if err != nil {
+					t.Fail()
+				}
+				r := httptest.NewRequest("PUT", "/account/update/overdraft", bytes.NewBuffer(by))
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+		{
+			name: "Failure :: UpdateOverdraftLimit:: json unmarshall failure",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("PUT", "/account/update/overdraft", bytes.NewBuffer([]byte("")))
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: "put data into data: unexpected end of JSON input",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			x.UpdateOverdraftLimit(w, r)
+			tt.want(*w)
+		})
+	}
+}
+func TestAccountManagmentSvc_UpdateSpendLimits(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	maxDebit := model.Money(50000)
+	tests := []struct {
+		name  string
+		body  string
+		setup func() *mock.MockAccountManagmentSvcLogicIer
+		want  *respModel.Response
+	}{
+		{
+			name: "Success",
+			body: `{"account_number": 1, "max_debit": "500.00"}`,
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().UpdateSpendLimits(model.UpdateSpendLimits{AccountNumber: 1, MaxDebit: &maxDebit}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				return mockLogic
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: codes.GetErr(codes.Success), Data: nil},
+		},
+		{
+			name: "Failure :: UpdateSpendLimits:: json unmarshall failure",
+			body: "",
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				return mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: "put data into data: unexpected end of JSON input", Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			svc := &accountManagmentSvc{logic: tt.setup()}
+			r := httptest.NewRequest("PUT", "/account/update/limits", bytes.NewBufferString(tt.body))
+			svc.UpdateSpendLimits(w, r)
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvc_UpdateAccountStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		body  string
+		setup func() *mock.MockAccountManagmentSvcLogicIer
+		want  *respModel.Response
+	}{
+		{
+			name: "Success",
+			body: `{"account_number": 1, "status": "frozen"}`,
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().UpdateAccountStatus(model.UpdateAccountStatus{AccountNumber: 1, Status: model.AccountFrozen}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				return mockLogic
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: codes.GetErr(codes.Success), Data: nil},
+		},
+		{
+			name: "Failure :: UpdateAccountStatus:: json unmarshall failure",
+			body: "",
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				return mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: "put data into data: unexpected end of JSON input", Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			svc := &accountManagmentSvc{logic: tt.setup()}
+			r := httptest.NewRequest("PUT", "/account/update/status", bytes.NewBufferString(tt.body))
+			svc.UpdateAccountStatus(w, r)
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvc_CloseAccount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		body  string
+		setup func() *mock.MockAccountManagmentSvcLogicIer
+		want  *respModel.Response
+	}{
+		{
+			name: "Success",
+			body: `{"user_id": "123"}`,
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().CloseAccount(model.AccountClosure{UserId: "123"}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				return mockLogic
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: codes.GetErr(codes.Success), Data: nil},
+		},
+		{
+			name: "Failure :: CloseAccount:: json unmarshall failure",
+			body: "",
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				return mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: "put data into data: unexpected end of JSON input", Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			svc := &accountManagmentSvc{logic: tt.setup()}
+			r := httptest.NewRequest("POST", "/account/close", bytes.NewBufferString(tt.body))
+			svc.CloseAccount(w, r)
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvc_OpenAccount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name    string
+		body    string
+		session interface{}
+		setup   func() *mock.MockAccountManagmentSvcLogicIer
+		want    *respModel.Response
+	}{
+		{
+			name:    "Success",
+			body:    `{"account_type": "wallet"}`,
+			session: "1234",
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().OpenAccount("1234", model.OpenAccount{AccountType: "wallet"}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusCreated,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				return mockLogic
+			},
+			want: &respModel.Response{Status: http.StatusCreated, Message: codes.GetErr(codes.Success), Data: nil},
+		},
+		{
+			name:    "Failure :: OpenAccount:: json unmarshall failure",
+			body:    "",
+			session: "1234",
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				return mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: "put data into data: unexpected end of JSON input", Data: nil},
+		},
+		{
+			name:    "Failure :: OpenAccount:: err asserting to string",
+			body:    `{"account_type": "wallet"}`,
+			session: 1.11,
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				return mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrAssertUserid), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			svc := &accountManagmentSvc{logic: tt.setup()}
+			r := httptest.NewRequest("POST", "/account/open", bytes.NewBufferString(tt.body))
+			svc.OpenAccount(w, r.WithContext(session.SetSession(r.Context(), tt.session)))
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvc_SetDefaultAccount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		body  string
+		setup func() *mock.MockAccountManagmentSvcLogicIer
+		want  *respModel.Response
+	}{
+		{
+			name: "Success",
+			body: `{"account_number": 2}`,
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().SetDefaultAccount("1234", model.DefaultAccount{AccountNumber: 2}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				return mockLogic
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: codes.GetErr(codes.Success), Data: nil},
+		},
+		{
+			name: "Failure :: SetDefaultAccount:: json unmarshall failure",
+			body: "",
+			setup: func() *mock.MockAccountManagmentSvcLogicIer {
+				return mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: "put data into data: unexpected end of JSON input", Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			svc := &accountManagmentSvc{logic: tt.setup()}
+			r := httptest.NewRequest("PUT", "/account/update/default", bytes.NewBufferString(tt.body))
+			svc.SetDefaultAccount(w, r.WithContext(session.SetSession(r.Context(), "1234")))
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvc_Statement(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  func(recorder httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().Statement("1234", 0, time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), "csv").Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data:    model.StatementFile{Name: "statement-1-2022-09.csv", ContentType: "text/csv", Content: []byte("date\n")},
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/statement?month=2022-09&format=csv", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				if rec.Code != http.StatusOK {
+					t.Errorf("Want: %v, Got: %v", http.StatusOK, rec.Code)
+				}
+				if got := rec.Header().Get("Content-Type"); got != "text/csv" {
+					t.Errorf("Want: %v, Got: %v", "text/csv", got)
+				}
+				if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="statement-1-2022-09.csv"` {
+					t.Errorf("Want: %v, Got: %v", `attachment; filename="statement-1-2022-09.csv"`, got)
+				}
+				if got := rec.Body.String(); got != "date\n" {
+					t.Errorf("Want: %v, Got: %v", "date\n", got)
+				}
+			},
+		},
+		{
+			name: "Success :: defaults to pdf",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().Statement("1234", 0, gomock.Any(), "pdf").Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data:    model.StatementFile{Name: "statement-1-2022-09.pdf", ContentType: "application/pdf", Content: []byte("%PDF-1.4")},
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/statement", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				if got := rec.Header().Get("Content-Type"); got != "application/pdf" {
+					t.Errorf("Want: %v, Got: %v", "application/pdf", got)
+				}
+			},
+		},
+		{
+			name: "Failure :: logic error",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().Statement("1234", 0, time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), "pdf").Times(1).Return(&respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrConvertingToPdf),
+					Data:    nil,
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/statement?month=2022-09&format=pdf", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrConvertingToPdf),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+		{
+			name: "Failure :: invalid month",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/statement?month=2022-13", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				b, err := ioutil.ReadAll(rec.Body)
+				if err != nil {
+					return
+				}
+				var response respModel.Response
+				err = json.Unmarshal(b, &response)
+				tempResp := &respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidQuery),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(&response, tempResp) {
+					t.Errorf("Want: %v, Got: %v", tempResp, &response)
+				}
+			},
+		},
+		{
+			name: "Failure :: err assert user_id",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/statement", nil)
+				return svc, r
+			},
+			want: func(rec httptest.ResponseRecorder) {
+				if rec.Code != http.StatusBadRequest {
+					t.Errorf("Want: %v, Got: %v", http.StatusBadRequest, rec.Code)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			x.Statement(w, r)
+			tt.want(*w)
+		})
+	}
+}
+func TestAccountManagmentSvc_Analytics(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  *respModel.Response
+	}{
+		{
+			name: "Success",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().Analytics("1234", 0, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data:    map[string]interface{}{"from": "2022-01", "to": "2022-03"},
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/analytics?from=2022-01&to=2022-03", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: &respModel.Response{
+				Status:  http.StatusOK,
+				Message: "SUCCESS",
+				Data:    map[string]interface{}{"from": "2022-01", "to": "2022-03"},
+			},
+		},
+		{
+			name: "Success :: defaults to the last months",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().Analytics("1234", 0, gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(id string, accountNumber int, from time.Time, to time.Time) *respModel.Response {
+					if from.Day() != 1 || from.AddDate(0, defaultAnalyticsMonths, 0).Before(to) || to.Sub(from) < 150*24*time.Hour {
+						t.Errorf("Want: %v months, Got: %v to %v", defaultAnalyticsMonths, from, to)
+					}
+					return &respModel.Response{Status: http.StatusOK, Message: "SUCCESS"}
+				})
+				svc := &accountManagmentSvc{
+					logic: mockLogic,
+				}
+				r := httptest.NewRequest("GET", "/account/analytics", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: &respModel.Response{
+				Status:  http.StatusOK,
+				Message: "SUCCESS",
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: invalid from",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				svc := &accountManagmentSvc{
+					logic: mock.NewMockAccountManagmentSvcLogicIer(mockCtrl),
+				}
+				r := httptest.NewRequest("GET", "/account/analytics?from=2022-1", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidQuery),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: invalid to",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				svc := &accountManagmentSvc{
+					logic: mock.NewMockAccountManagmentSvcLogicIer(mockCtrl),
+				}
+				r := httptest.NewRequest("GET", "/account/analytics?to=march", nil)
+				ctx := session.SetSession(r.Context(), "1234")
+				return svc, r.WithContext(ctx)
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidQuery),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: err assert user_id",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				svc := &accountManagmentSvc{
+					logic: mock.NewMockAccountManagmentSvcLogicIer(mockCtrl),
+				}
+				return svc, httptest.NewRequest("GET", "/account/analytics", nil)
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrAssertUserid),
+				Data:    nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			x.Analytics(w, r)
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvc_StandingOrders(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		call  func(svc *accountManagmentSvc, w http.ResponseWriter, r *http.Request)
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: create",
+			call: (*accountManagmentSvc).CreateStandingOrder,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				order := model.NewStandingOrder{AccountNumber: 1, Amount: 2500, TransactionType: "debit", Schedule: "0 9 1 * *"}
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().CreateStandingOrder("1234", order).Times(1).Return(&respModel.Response{
+					Status:  http.StatusCreated,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				by, err := json.Marshal(order)
+				if err != nil {
+					t.Fail()
+				}
+				r := httptest.NewRequest("POST", "/account/standing-orders", bytes.NewBuffer(by))
+				return &accountManagmentSvc{logic: mockLogic}, r.WithContext(session.SetSession(r.Context(), "1234"))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusCreated,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: create:: json unmarshall failure",
+			call: (*accountManagmentSvc).CreateStandingOrder,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				r := httptest.NewRequest("POST", "/account/standing-orders", bytes.NewBuffer([]byte("")))
+				return &accountManagmentSvc{logic: mockLogic}, r.WithContext(session.SetSession(r.Context(), "1234"))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: "put data into data: unexpected end of JSON input",
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: create:: err assert user_id",
+			call: (*accountManagmentSvc).CreateStandingOrder,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("POST", "/account/standing-orders", bytes.NewBuffer([]byte("{}")))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrAssertUserid),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: list",
+			call: (*accountManagmentSvc).StandingOrders,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().StandingOrders("1234", 1).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				r := httptest.NewRequest("GET", "/account/standing-orders?account_number=1", nil)
+				return &accountManagmentSvc{logic: mockLogic}, r.WithContext(session.SetSession(r.Context(), "1234"))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusOK,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: list:: invalid account number",
+			call: (*accountManagmentSvc).StandingOrders,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				r := httptest.NewRequest("GET", "/account/standing-orders?account_number=abc", nil)
+				return &accountManagmentSvc{logic: mockLogic}, r.WithContext(session.SetSession(r.Context(), "1234"))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidQuery),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: list:: err assert user_id",
+			call: (*accountManagmentSvc).StandingOrders,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("GET", "/account/standing-orders?account_number=1", nil)
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrAssertUserid),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: cancel",
+			call: (*accountManagmentSvc).CancelStandingOrder,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().CancelStandingOrder("1234", 1, int64(5)).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				r := httptest.NewRequest("DELETE", "/account/standing-orders/5?account_number=1", nil)
+				r = r.WithContext(session.SetSession(r.Context(), "1234"))
+				return &accountManagmentSvc{logic: mockLogic}, mux.SetURLVars(r, map[string]string{"id": "5"})
+			},
+			want: &respModel.Response{
+				Status:  http.StatusAccepted,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: cancel:: invalid id",
+			call: (*accountManagmentSvc).CancelStandingOrder,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				r := httptest.NewRequest("DELETE", "/account/standing-orders/abc", nil)
+				r = r.WithContext(session.SetSession(r.Context(), "1234"))
+				return &accountManagmentSvc{logic: mockLogic}, mux.SetURLVars(r, map[string]string{"id": "abc"})
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidQuery),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: cancel:: invalid account number",
+			call: (*accountManagmentSvc).CancelStandingOrder,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				r := httptest.NewRequest("DELETE", "/account/standing-orders/5?account_number=abc", nil)
+				r = r.WithContext(session.SetSession(r.Context(), "1234"))
+				return &accountManagmentSvc{logic: mockLogic}, mux.SetURLVars(r, map[string]string{"id": "5"})
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidQuery),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: cancel:: err assert user_id",
+			call: (*accountManagmentSvc).CancelStandingOrder,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				r := httptest.NewRequest("DELETE", "/account/standing-orders/5", nil)
+				return &accountManagmentSvc{logic: mockLogic}, mux.SetURLVars(r, map[string]string{"id": "5"})
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrAssertUserid),
+				Data:    nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			tt.call(x, w, r)
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvc_Budgets(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	withUser := func(r *http.Request) *http.Request {
+		return r.WithContext(session.SetSession(r.Context(), "1234"))
+	}
+	tests := []struct {
+		name  string
+		call  func(svc *accountManagmentSvc, w http.ResponseWriter, r *http.Request)
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: create",
+			call: (*accountManagmentSvc).CreateBudget,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				budget := model.NewBudget{Category: "groceries", Limit: 40000}
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().CreateBudget("1234", 0, budget).Times(1).Return(&respModel.Response{
+					Status:  http.StatusCreated,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				by, err := json.Marshal(budget)
+				if err != nil {
+					t.Fail()
+				}
+				return &accountManagmentSvc{logic: mockLogic}, withUser(httptest.NewRequest("POST", "/account/budgets", bytes.NewBuffer(by)))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusCreated,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: create:: json unmarshall failure",
+			call: (*accountManagmentSvc).CreateBudget,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, withUser(httptest.NewRequest("POST", "/account/budgets", bytes.NewBuffer([]byte(""))))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: "put data into data: unexpected end of JSON input",
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: create:: err assert user_id",
+			call: (*accountManagmentSvc).CreateBudget,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("POST", "/account/budgets", nil)
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrAssertUserid),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: list",
+			call: (*accountManagmentSvc).Budgets,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().Budgets("1234", 0).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				return &accountManagmentSvc{logic: mockLogic}, withUser(httptest.NewRequest("GET", "/account/budgets", nil))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusOK,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: update",
+			call: (*accountManagmentSvc).UpdateBudget,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().UpdateBudget("1234", 0, int64(3), model.UpdateBudget{Limit: 50000}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				r := withUser(httptest.NewRequest("PUT", "/account/budgets/3", bytes.NewBuffer([]byte(`{"limit":"500.00"}`))))
+				return &accountManagmentSvc{logic: mockLogic}, mux.SetURLVars(r, map[string]string{"id": "3"})
+			},
+			want: &respModel.Response{
+				Status:  http.StatusAccepted,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: update:: invalid id",
+			call: (*accountManagmentSvc).UpdateBudget,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				r := withUser(httptest.NewRequest("PUT", "/account/budgets/abc", bytes.NewBuffer([]byte(`{"limit":"500.00"}`))))
+				return &accountManagmentSvc{logic: mockLogic}, mux.SetURLVars(r, map[string]string{"id": "abc"})
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidQuery),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: delete",
+			call: (*accountManagmentSvc).DeleteBudget,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().DeleteBudget("1234", 0, int64(3)).Times(1).Return(&respModel.Response{
+					Status:  http.StatusNotFound,
+					Message: codes.GetErr(codes.BudgetNotFound),
+					Data:    nil,
+				})
+				r := withUser(httptest.NewRequest("DELETE", "/account/budgets/3", nil))
+				return &accountManagmentSvc{logic: mockLogic}, mux.SetURLVars(r, map[string]string{"id": "3"})
+			},
+			want: &respModel.Response{
+				Status:  http.StatusNotFound,
+				Message: codes.GetErr(codes.BudgetNotFound),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: delete:: err assert user_id",
+			call: (*accountManagmentSvc).DeleteBudget,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("DELETE", "/account/budgets/3", nil)
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrAssertUserid),
+				Data:    nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			tt.call(x, w, r)
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvc_Members(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	withUser := func(r *http.Request) *http.Request {
+		return r.WithContext(session.SetSession(r.Context(), "1234"))
+	}
+	tests := []struct {
+		name  string
+		call  func(svc *accountManagmentSvc, w http.ResponseWriter, r *http.Request)
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: list",
+			call: (*accountManagmentSvc).Members,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().Members("1234", 1).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				return &accountManagmentSvc{logic: mockLogic}, withUser(httptest.NewRequest("GET", "/account/members?account_number=1", nil))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusOK,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: list:: invalid account number",
+			call: (*accountManagmentSvc).Members,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, withUser(httptest.NewRequest("GET", "/account/members?account_number=abc", nil))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidQuery),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: invite",
+			call: (*accountManagmentSvc).InviteMember,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				invite := model.InviteMember{UserId: "5678", Role: model.RoleViewer}
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().InviteMember("1234", 0, invite).Times(1).Return(&respModel.Response{
+					Status:  http.StatusCreated,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				by, err := json.Marshal(invite)
+				if err != nil {
+					t.Fail()
+				}
+				return &accountManagmentSvc{logic: mockLogic}, withUser(httptest.NewRequest("POST", "/account/members", bytes.NewBuffer(by)))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusCreated,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: invite:: owner role rejected",
+			call: (*accountManagmentSvc).InviteMember,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				body := `{"user_id":"5678","role":"owner"}`
+				return &accountManagmentSvc{logic: mockLogic}, withUser(httptest.NewRequest("POST", "/account/members", bytes.NewBuffer([]byte(body))))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: "validation 1: field <Role> with value <owner> failed for <oneof> validation.\n",
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: invite:: err assert user_id",
+			call: (*accountManagmentSvc).InviteMember,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("POST", "/account/members", nil)
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrAssertUserid),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: remove",
+			call: (*accountManagmentSvc).RemoveMember,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().RemoveMember("1234", 0, "5678").Times(1).Return(&respModel.Response{
+					Status:  http.StatusForbidden,
+					Message: codes.GetErr(codes.ErrRoleNotAllowed),
+					Data:    nil,
+				})
+				r := withUser(httptest.NewRequest("DELETE", "/account/members/5678", nil))
+				return &accountManagmentSvc{logic: mockLogic}, mux.SetURLVars(r, map[string]string{"user_id": "5678"})
+			},
+			want: &respModel.Response{
+				Status:  http.StatusForbidden,
+				Message: codes.GetErr(codes.ErrRoleNotAllowed),
+				Data:    nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			tt.call(x, w, r)
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvc_Holds(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		call  func(svc *accountManagmentSvc, w http.ResponseWriter, r *http.Request)
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: place",
+			call: (*accountManagmentSvc).PlaceHold,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().PlaceHold(model.NewHold{AccountNumber: 1, Amount: 6000, Reference: "card auth"}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				body := `{"account_number":1,"amount":"60.00","reference":"card auth"}`
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("PUT", "/account/update/hold", bytes.NewBuffer([]byte(body)))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusAccepted,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: place:: json unmarshall failure",
+			call: (*accountManagmentSvc).PlaceHold,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("PUT", "/account/update/hold", bytes.NewBuffer([]byte("")))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: "put data into data: unexpected end of JSON input",
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: capture",
+			call: (*accountManagmentSvc).CaptureHold,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().CaptureHold(model.CaptureHold{HoldId: 5, Amount: 4500}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				body := `{"hold_id":5,"amount":"45.00"}`
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("PUT", "/account/update/hold/capture", bytes.NewBuffer([]byte(body)))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusAccepted,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: capture:: json unmarshall failure",
+			call: (*accountManagmentSvc).CaptureHold,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("PUT", "/account/update/hold/capture", bytes.NewBuffer([]byte("")))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: "put data into data: unexpected end of JSON input",
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: release",
+			call: (*accountManagmentSvc).ReleaseHold,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().ReleaseHold(model.ReleaseHold{HoldId: 5}).Times(1).Return(&respModel.Response{
+					Status:  http.StatusNotFound,
+					Message: codes.GetErr(codes.HoldNotFound),
+					Data:    nil,
+				})
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("PUT", "/account/update/hold/release", bytes.NewBuffer([]byte(`{"hold_id":5}`)))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusNotFound,
+				Message: codes.GetErr(codes.HoldNotFound),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: release:: json unmarshall failure",
+			call: (*accountManagmentSvc).ReleaseHold,
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("PUT", "/account/update/hold/release", bytes.NewBuffer([]byte("")))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: "put data into data: unexpected end of JSON input",
+				Data:    nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			tt.call(x, w, r)
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvc_ImportTransactions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	body := "account_number,amount,type,reference\n1,10.00,credit,refund\n"
+	rows := []model.ImportRow{{Line: 2, Transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 1000, TransactionType: "credit", Reference: "refund"}}}
+	tests := []struct {
+		name  string
+		setup func() (*accountManagmentSvc, *http.Request)
+		want  *respModel.Response
+	}{
+		{
+			name: "Success",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().ImportTransactions(rows, false).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("POST", "/account/transactions/import", strings.NewReader(body))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusOK,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Success :: dry run",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				mockLogic.EXPECT().ImportTransactions(rows, true).Times(1).Return(&respModel.Response{
+					Status:  http.StatusOK,
+					Message: codes.GetErr(codes.Success),
+					Data:    nil,
+				})
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("POST", "/account/transactions/import?dry_run=true", strings.NewReader(body))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusOK,
+				Message: codes.GetErr(codes.Success),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: invalid dry run",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("POST", "/account/transactions/import?dry_run=maybe", strings.NewReader(body))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidQuery),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: missing column",
+			setup: func() (*accountManagmentSvc, *http.Request) {
+				mockLogic := mock.NewMockAccountManagmentSvcLogicIer(mockCtrl)
+				return &accountManagmentSvc{logic: mockLogic}, httptest.NewRequest("POST", "/account/transactions/import", strings.NewReader("account_number,amount\n1,1\n"))
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidImport),
+				Data:    nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			x, r := tt.setup()
+			x.ImportTransactions(w, r)
+			var response respModel.Response
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			if err != nil || !reflect.DeepEqual(&response, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &response)
+			}
+		})
+	}
+}
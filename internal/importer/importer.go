@@ -0,0 +1,99 @@
+// Package importer reads bulk transaction imports, every row is checked against the same rules as a
+// transaction posted through the transaction endpoint.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxRows bounds the rows of a single import, the header not included.
+const MaxRows = 10000
+
+var (
+	ErrMissingColumn = errors.New("import header is missing a column")
+	ErrTooManyRows   = fmt.Errorf("import has more than %d rows", MaxRows)
+)
+
+// columns are the columns of an import in the order of the header, the reference may be left empty.
+var columns = []string{"account_number", "amount", "type", "reference"}
+
+var validate = validator.New()
+
+// Parse reads a csv with a header naming the columns account_number, amount, type and reference, in any order.
+// A row that cannot be posted is returned with the reason in Err, an error is only returned when the file
+// itself cannot be read.
+func Parse(r io.Reader) ([]model.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("import header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range columns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, name)
+		}
+	}
+	rows := []model.ImportRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, parseRow(line, record, index))
+	}
+}
+
+func parseRow(line int, record []string, index map[string]int) model.ImportRow {
+	row := model.ImportRow{Line: line}
+	field := func(name string) string {
+		if i := index[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	var err error
+	row.Transaction.AccountNumber, err = strconv.Atoi(field("account_number"))
+	if err != nil {
+		row.Err = fmt.Sprintf("invalid account_number %q", field("account_number"))
+		return row
+	}
+	row.Transaction.Amount, err = model.ParseMoney(field("amount"))
+	if err != nil {
+		row.Err = err.Error()
+		return row
+	}
+	row.Transaction.TransactionType = strings.ToLower(field("type"))
+	row.Transaction.Reference = field("reference")
+	err = validate.Struct(row.Transaction)
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) {
+		var failed []string
+		for _, e := range errs {
+			failed = append(failed, fmt.Sprintf("field <%s> failed for <%s> validation", e.Field(), e.Tag()))
+		}
+		row.Err = strings.Join(failed, ", ")
+	} else if err != nil {
+		row.Err = err.Error()
+	}
+	return row
+}
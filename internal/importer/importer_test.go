@@ -0,0 +1,95 @@
+package importer
+
+import (
+	"errors"
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    string
+		want    []model.ImportRow
+		wantErr error
+	}{
+		{
+			name: "Success :: valid rows",
+			give: "account_number,amount,type,reference\n" +
+				"1,10.50,credit,refund 42\n" +
+				"2, 3 ,DEBIT,\n",
+			want: []model.ImportRow{
+				{Line: 2, Transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 1050, TransactionType: "credit", Reference: "refund 42"}},
+				{Line: 3, Transaction: model.UpdateTransaction{AccountNumber: 2, Amount: 300, TransactionType: "debit"}},
+			},
+		},
+		{
+			name: "Success :: columns in any order",
+			give: "Reference,Type,Amount,Account_Number\n" +
+				"\"fee, waived\",credit,1,7\n",
+			want: []model.ImportRow{
+				{Line: 2, Transaction: model.UpdateTransaction{AccountNumber: 7, Amount: 100, TransactionType: "credit", Reference: "fee, waived"}},
+			},
+		},
+		{
+			name: "Success :: invalid rows",
+			give: "account_number,amount,type,reference\n" +
+				"x,1,credit,\n" +
+				"1,-1,credit,\n" +
+				"1,1.005,credit,\n" +
+				"1,1,refund,\n" +
+				"0,0,credit,\n" +
+				"1,1,credit," + strings.Repeat("r", 226) + "\n" +
+				"1\n",
+			want: []model.ImportRow{
+				{Line: 2, Err: `invalid account_number "x"`},
+				{Line: 3, Transaction: model.UpdateTransaction{AccountNumber: 1}, Err: model.ErrNegativeMoney.Error()},
+				{Line: 4, Transaction: model.UpdateTransaction{AccountNumber: 1}, Err: model.ErrMoneyPrecision.Error()},
+				{Line: 5, Transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 100, TransactionType: "refund"}, Err: "field <TransactionType> failed for <oneof> validation"},
+				{Line: 6, Transaction: model.UpdateTransaction{TransactionType: "credit"}, Err: "field <AccountNumber> failed for <required> validation, field <Amount> failed for <required> validation"},
+				{Line: 7, Transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 100, TransactionType: "credit", Reference: strings.Repeat("r", 226)}, Err: "field <Reference> failed for <max> validation"},
+				{Line: 8, Transaction: model.UpdateTransaction{AccountNumber: 1}, Err: `invalid amount: ""`},
+			},
+		},
+		{
+			name: "Success :: header only",
+			give: "account_number,amount,type,reference\n",
+			want: []model.ImportRow{},
+		},
+		{
+			name:    "Failure :: missing column",
+			give:    "account_number,amount,reference\n1,1,x\n",
+			wantErr: ErrMissingColumn,
+		},
+		{
+			name:    "Failure :: no header",
+			give:    "",
+			wantErr: io.EOF,
+		},
+		{
+			name:    "Failure :: too many rows",
+			give:    "account_number,amount,type,reference\n" + strings.Repeat("1,1,credit,\n", MaxRows+1),
+			wantErr: ErrTooManyRows,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.give))
+			if tt.want == nil {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
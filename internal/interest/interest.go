@@ -0,0 +1,84 @@
+// Package interest computes the interest accrued on account balances, posting it is left to the caller.
+package interest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+	"math/big"
+	"sort"
+	"time"
+)
+
+var (
+	ErrUnknownDayCount = errors.New("unknown day count convention")
+	ErrInvalidPeriod   = errors.New("accrual period must end after it starts")
+)
+
+// day count conventions, they set how many days a yearly rate is spread over
+const (
+	Actual365    = "act/365"
+	Actual360    = "act/360"
+	ActualActual = "act/act"
+)
+
+// DaysInYear returns the number of days the yearly rate is spread over for the days of the given year.
+func DaysInYear(dayCount string, year int) (int, error) {
+	switch dayCount {
+	case Actual365:
+		return 365, nil
+	case Actual360:
+		return 360, nil
+	case ActualActual:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 366, nil
+		}
+		return 365, nil
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownDayCount, dayCount)
+}
+
+// Accrue returns the interest earned over [from, to) by an account holding the opening balance at from and
+// receiving the transactions afterwards. Every UTC day earns rate percent a year on the balance at the end of
+// that day, days ending overdrawn earn nothing. The daily amounts are summed before the total is rounded
+// half away from zero to the cent, so the result does not depend on how the period is split into days.
+func Accrue(opening model.Money, transactions []model.Transaction, from time.Time, to time.Time, rate *big.Rat, dayCount string) (model.Money, error) {
+	from, to = from.UTC(), to.UTC()
+	if !from.Before(to) {
+		return 0, ErrInvalidPeriod
+	}
+	sorted := make([]model.Transaction, len(transactions))
+	copy(sorted, transactions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedOn.Before(sorted[j].CreatedOn)
+	})
+	total := new(big.Rat)
+	balance := opening
+	next := 0
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		for next < len(sorted) && sorted[next].CreatedOn.Before(end) {
+			balance += sorted[next].SignedAmount()
+			next++
+		}
+		if balance <= 0 {
+			continue
+		}
+		days, err := DaysInYear(dayCount, day.Year())
+		if err != nil {
+			return 0, err
+		}
+		daily := new(big.Rat).SetFrac64(int64(balance), int64(days)*100)
+		total.Add(total, daily.Mul(daily, rate))
+	}
+	return round(total), nil
+}
+
+// round rounds the amount of cents half away from zero.
+func round(cents *big.Rat) model.Money {
+	quo, rem := new(big.Int).QuoRem(cents.Num(), cents.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(cents.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(cents.Sign())))
+	}
+	return model.Money(quo.Int64())
+}
@@ -0,0 +1,148 @@
+package interest
+
+import (
+	"errors"
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestDaysInYear(t *testing.T) {
+	tests := []struct {
+		name     string
+		dayCount string
+		year     int
+		want     int
+		wantErr  error
+	}{
+		{name: "Success :: act/365 in a leap year", dayCount: Actual365, year: 2024, want: 365},
+		{name: "Success :: act/360", dayCount: Actual360, year: 2022, want: 360},
+		{name: "Success :: act/act in a leap year", dayCount: ActualActual, year: 2024, want: 366},
+		{name: "Success :: act/act in a century year", dayCount: ActualActual, year: 2100, want: 365},
+		{name: "Success :: act/act in a year divisible by 400", dayCount: ActualActual, year: 2000, want: 366},
+		{name: "Failure :: unknown convention", dayCount: "30/360", year: 2022, wantErr: ErrUnknownDayCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DaysInYear(tt.dayCount, tt.year)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccrue(t *testing.T) {
+	jan := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	leapFeb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	leapMar := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rate := func(s string) *big.Rat {
+		r, _ := new(big.Rat).SetString(s)
+		return r
+	}
+	tests := []struct {
+		name         string
+		opening      model.Money
+		transactions []model.Transaction
+		from         time.Time
+		to           time.Time
+		rate         *big.Rat
+		dayCount     string
+		want         model.Money
+		wantErr      error
+	}{
+		{
+			name:     "Success :: constant balance",
+			opening:  100000,
+			from:     jan,
+			to:       feb,
+			rate:     rate("3.65"),
+			dayCount: Actual365,
+			want:     310,
+		},
+		{
+			name:     "Success :: act/360 pays more",
+			opening:  100000,
+			from:     jan,
+			to:       feb,
+			rate:     rate("3.65"),
+			dayCount: Actual360,
+			want:     314,
+		},
+		{
+			name:     "Success :: act/act in a leap year",
+			opening:  100000,
+			from:     leapFeb,
+			to:       leapMar,
+			rate:     rate("3.66"),
+			dayCount: ActualActual,
+			want:     290,
+		},
+		{
+			name: "Success :: balance counted at the end of the day",
+			transactions: []model.Transaction{
+				{Amount: 100000, TransactionType: "credit", CreatedOn: jan.AddDate(0, 0, 10).Add(12 * time.Hour)},
+			},
+			from:     jan,
+			to:       feb,
+			rate:     rate("3.65"),
+			dayCount: Actual365,
+			want:     210,
+		},
+		{
+			name:    "Success :: overdrawn days earn nothing",
+			opening: -5000,
+			transactions: []model.Transaction{
+				{Amount: 5000, TransactionType: "debit", CreatedOn: jan.AddDate(0, 0, 25)},
+				{Amount: 105000, TransactionType: "credit", CreatedOn: jan.AddDate(0, 0, 20)},
+			},
+			from:     jan,
+			to:       feb,
+			rate:     rate("3.65"),
+			dayCount: Actual365,
+			want:     107,
+		},
+		{
+			name:     "Success :: rounds half away from zero",
+			opening:  50,
+			from:     jan,
+			to:       jan.AddDate(0, 0, 1),
+			rate:     rate("365"),
+			dayCount: Actual365,
+			want:     1,
+		},
+		{
+			name:     "Failure :: unknown convention",
+			opening:  100000,
+			from:     jan,
+			to:       feb,
+			rate:     rate("1"),
+			dayCount: "30/360",
+			wantErr:  ErrUnknownDayCount,
+		},
+		{
+			name:     "Failure :: invalid period",
+			from:     feb,
+			to:       jan,
+			rate:     rate("1"),
+			dayCount: Actual365,
+			wantErr:  ErrInvalidPeriod,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Accrue(tt.opening, tt.transactions, tt.from, tt.to, tt.rate, tt.dayCount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
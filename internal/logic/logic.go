@@ -1,16 +1,25 @@
 package logic
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/PereRohit/util/log"
 	respModel "github.com/PereRohit/util/model"
+	"github.com/vatsal278/AccountManagmentSvc/internal/accountnumber"
 	"github.com/vatsal278/AccountManagmentSvc/internal/codes"
 	"github.com/vatsal278/AccountManagmentSvc/internal/config"
+	"github.com/vatsal278/AccountManagmentSvc/internal/interest"
 	"github.com/vatsal278/AccountManagmentSvc/internal/model"
 	jwtSvc "github.com/vatsal278/AccountManagmentSvc/internal/repo/authentication"
 	"github.com/vatsal278/AccountManagmentSvc/internal/repo/datasource"
+	"github.com/vatsal278/AccountManagmentSvc/internal/scheduler"
+	"github.com/vatsal278/AccountManagmentSvc/internal/statement"
+	"math/big"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 )
 
 //go:generate mockgen --build_flags=--mod=mod --destination=./../../pkg/mock/mock_logic.go --package=mock github.com/vatsal278/AccountManagmentSvc/internal/logic AccountManagmentSvcLogicIer
@@ -18,24 +27,115 @@ import (
 type AccountManagmentSvcLogicIer interface {
 	HealthCheck() bool
 	CreateAccount(account model.NewAccount) *respModel.Response
-	AccountDetails(id string) *respModel.Response
+	AccountDetails(id string, accountNumber int, asOf time.Time) *respModel.Response
 	UpdateServices(id string, services model.UpdateServices) *respModel.Response
 	UpdateTransaction(transaction model.UpdateTransaction) *respModel.Response
+	TransactionHistory(id string, filter model.TransactionFilter) *respModel.Response
+	Transfer(transfer model.Transfer) *respModel.Response
+	ReverseTransaction(reversal model.Reversal) *respModel.Response
+	UpdateOverdraftLimit(limit model.OverdraftLimit) *respModel.Response
+	UpdateAccountStatus(update model.UpdateAccountStatus) *respModel.Response
+	CloseAccount(closure model.AccountClosure) *respModel.Response
+	OpenAccount(id string, account model.OpenAccount) *respModel.Response
+	SetDefaultAccount(id string, account model.DefaultAccount) *respModel.Response
+	Members(id string, accountNumber int) *respModel.Response
+	InviteMember(id string, accountNumber int, invite model.InviteMember) *respModel.Response
+	RemoveMember(id string, accountNumber int, userId string) *respModel.Response
+	UpdateSpendLimits(limits model.UpdateSpendLimits) *respModel.Response
+	Statement(id string, accountNumber int, month time.Time, format string) *respModel.Response
+	Analytics(id string, accountNumber int, from time.Time, to time.Time) *respModel.Response
+	CreateStandingOrder(id string, order model.NewStandingOrder) *respModel.Response
+	StandingOrders(id string, accountNumber int) *respModel.Response
+	CancelStandingOrder(id string, accountNumber int, orderId int64) *respModel.Response
+	RunStandingOrders(now time.Time)
+	CreateBudget(id string, accountNumber int, budget model.NewBudget) *respModel.Response
+	Budgets(id string, accountNumber int) *respModel.Response
+	UpdateBudget(id string, accountNumber int, budgetId int64, budget model.UpdateBudget) *respModel.Response
+	DeleteBudget(id string, accountNumber int, budgetId int64) *respModel.Response
+	PlaceHold(hold model.NewHold) *respModel.Response
+	CaptureHold(capture model.CaptureHold) *respModel.Response
+	ReleaseHold(release model.ReleaseHold) *respModel.Response
+	ExpireHolds(now time.Time)
+	PurgeIdempotencyKeys(before time.Time)
+	AccrueInterest(now time.Time)
+	InterestReport(from time.Time, to time.Time, accountNumber int, post bool) *respModel.Response
+	Reconcile(accountNumber int, repair bool) *respModel.Response
+	ImportTransactions(rows []model.ImportRow, dryRun bool) *respModel.Response
 }
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// standingOrderBatch is the number of due standing orders fetched at once by the scheduler.
+const standingOrderBatch = 100
+
+// maxAnalyticsMonths bounds the window of the analytics, the months are counted inclusively.
+const maxAnalyticsMonths = 24
+
+// defaultHoldExpiry applies to holds placed without an expiry, maxHoldExpiry bounds how long a hold may last.
+const (
+	defaultHoldExpiry = 7 * 24 * time.Hour
+	maxHoldExpiry     = 30 * 24 * time.Hour
+)
+
+// importBatchSize is the number of imported transactions posted in a single database transaction.
+const importBatchSize = 100
+
+// maxInterestReportMonths bounds the window of the interest report, the months are counted inclusively.
+const maxInterestReportMonths = 24
+
+// holdExpiryBatch is the number of expired holds fetched at once by the scheduler.
+const holdExpiryBatch = 100
+
+// maxAllocationAttempts bounds how many random account numbers are tried before opening an account fails.
+const maxAllocationAttempts = 5
+
+// uncategorized labels the spends of transactions posted without a category.
+const uncategorized = "uncategorized"
+
+// feeCategory is the category of the ledger entries charging a fee.
+const feeCategory = "fees"
+
+// budgetThresholds are the shares of a budget, in percent, announced to the user once reached, highest first.
+var budgetThresholds = []int{100, 80}
+
+const (
+	StatementFormatPdf = "pdf"
+	StatementFormatCsv = "csv"
+)
+
+var (
+	errUnsupportedCurrency = errors.New("no exchange rate configured")
+	errInvalidAmount       = errors.New("invalid amount")
+)
+
 type accountManagmentSvcLogic struct {
 	DsSvc      datasource.DataSourceI
 	jwtService jwtSvc.JWTService
 	msgQueue   config.MsgQueue
 	cookie     config.CookieStruct
+	currency   config.CurrencyCfg
+	interest   config.InterestCfg
+	fees       config.FeeCfg
+	limits     config.SpendLimitCfg
+	numbers    config.AccountNumberCfg
 }
 
-func NewAccountManagmentSvcLogic(ds datasource.DataSourceI, jwtService jwtSvc.JWTService, msgQueue config.MsgQueue, cookie config.CookieStruct) AccountManagmentSvcLogicIer {
+// NewAccountManagmentSvcLogic takes the cookie and the account settings from the config, which is expected to be
+// prepared by Config.Init.
+func NewAccountManagmentSvcLogic(ds datasource.DataSourceI, jwtService jwtSvc.JWTService, msgQueue config.MsgQueue, cfg config.Config) AccountManagmentSvcLogicIer {
 	return &accountManagmentSvcLogic{
 		DsSvc:      ds,
 		jwtService: jwtService,
 		msgQueue:   msgQueue,
-		cookie:     cookie,
+		cookie:     cfg.Cookie,
+		currency:   cfg.Currency,
+		interest:   cfg.Interest,
+		fees:       cfg.Fees,
+		limits:     cfg.SpendLimits,
+		numbers:    cfg.AccountNumbers,
 	}
 }
 
@@ -44,50 +144,265 @@ func (l accountManagmentSvcLogic) HealthCheck() bool {
 	return l.DsSvc.HealthCheck()
 }
 
+// CreateAccount opens an account for a new user and announces its activation, a user holds at most one account
+// of each type.
 func (l accountManagmentSvcLogic) CreateAccount(account model.NewAccount) *respModel.Response {
-	result, err := l.DsSvc.Get(map[string]interface{}{"user_id": account.UserId})
+	acc, resp := l.openAccount(account.UserId, account.Currency, account.AccountType)
+	if resp != nil {
+		return resp
+	}
+	if acc.Status != model.AccountActive {
+		return &respModel.Response{
+			Status:  http.StatusCreated,
+			Message: "SUCCESS",
+			Data:    nil,
+		}
+	}
+	go func(userId string, pubId string, channel string) {
+		userID := fmt.Sprintf(`{"user_id":"%s"}`, userId)
+		err := l.msgQueue.MsgBroker.PushMsg(userID, pubId, channel)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+	}(account.UserId, l.msgQueue.PubId, l.msgQueue.Channel)
+	return &respModel.Response{
+		Status:  http.StatusCreated,
+		Message: "SUCCESS",
+		Data:    nil,
+	}
+}
+
+// OpenAccount opens another account of the type for the user.
+func (l accountManagmentSvcLogic) OpenAccount(id string, account model.OpenAccount) *respModel.Response {
+	acc, resp := l.openAccount(id, account.Currency, account.AccountType)
+	if resp != nil {
+		return resp
+	}
+	return &respModel.Response{
+		Status:  http.StatusCreated,
+		Message: "SUCCESS",
+		Data:    accountSummary(acc),
+	}
+}
+
+// openAccount opens an account of the type for the user, the first account of the user becomes their default one.
+// The account is activated right away and stays pending when that fails. The response is set when no account was opened.
+func (l accountManagmentSvcLogic) openAccount(userId string, currency string, accountType string) (model.Account, *respModel.Response) {
+	if currency == "" {
+		currency = l.currency.Default
+	}
+	if accountType == "" {
+		accountType = model.AccountTypeCurrent
+	}
+	result, err := l.DsSvc.Get(map[string]interface{}{"user_id": userId})
 	if err != nil {
 		log.Error(err.Error())
-		return &respModel.Response{
+		return model.Account{}, &respModel.Response{
 			Status:  http.StatusInternalServerError,
 			Message: codes.GetErr(codes.ErrCreatingAccount),
 			Data:    nil,
 		}
 	}
-	if len(result) != 0 {
-		log.Error(codes.GetErr(codes.ErrAccExists))
-		return &respModel.Response{
-			Status:  http.StatusBadRequest,
-			Message: codes.GetErr(codes.ErrAccExists),
-			Data:    nil,
+	for _, acc := range result {
+		if acc.AccountType == accountType {
+			log.Error(codes.GetErr(codes.ErrAccExists))
+			return model.Account{}, &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrAccExists),
+				Data:    nil,
+			}
 		}
 	}
-	err = l.DsSvc.Insert(model.Account{Id: account.UserId})
+	acc := model.Account{Id: userId, Currency: currency, AccountType: accountType, Status: model.AccountPending, Default: len(result) == 0}
+	acc.AccountNumber, err = l.insertAccount(acc)
 	if err != nil {
-		log.Error(codes.GetErr(codes.ErrCreatingAccount))
-		return &respModel.Response{
+		log.Error(err)
+		return model.Account{}, &respModel.Response{
 			Status:  http.StatusBadRequest,
 			Message: codes.GetErr(codes.ErrCreatingAccount),
 			Data:    nil,
 		}
 	}
-	go func(userId string, pubId string, channel string) {
-		userID := fmt.Sprintf(`{"user_id":"%s"}`, userId)
-		err := l.msgQueue.MsgBroker.PushMsg(userID, pubId, channel)
+	// the account is activated before its activation is announced, it stays pending when that fails. The move goes
+	// through the lifecycle like any other, so it is recorded in the history of the account.
+	_, err = l.DsSvc.SetAccountStatus(acc.AccountNumber, model.AccountActive)
+	if err != nil {
+		log.Error(err)
+		return acc, nil
+	}
+	acc.Status = model.AccountActive
+	return acc, nil
+}
+
+// insertAccount inserts the account under a random account number, a new number is drawn while the drawn one is
+// taken. The database numbers the account when no length is configured.
+func (l accountManagmentSvcLogic) insertAccount(acc model.Account) (int, error) {
+	if l.numbers.Length == 0 {
+		return l.DsSvc.Insert(acc)
+	}
+	var err error
+	for attempt := 0; attempt < maxAllocationAttempts; attempt++ {
+		acc.AccountNumber, err = accountnumber.Generate(l.numbers.Length)
 		if err != nil {
-			log.Error(err)
-			return
+			return 0, err
 		}
-	}(account.UserId, l.msgQueue.PubId, l.msgQueue.Channel)
+		var accountNumber int
+		accountNumber, err = l.DsSvc.Insert(acc)
+		if !errors.Is(err, datasource.ErrAccountNumberTaken) {
+			return accountNumber, err
+		}
+	}
+	return 0, err
+}
+
+// checkAccountNumbers rejects the request when one of the account numbers it names cannot have been allocated, an
+// omitted account number is left to the caller.
+func (l accountManagmentSvcLogic) checkAccountNumbers(accountNumbers ...int) *respModel.Response {
+	for _, accountNumber := range accountNumbers {
+		if accountNumber != 0 && !l.numbers.Valid(accountNumber) {
+			log.Error(fmt.Errorf("invalid account number %d", accountNumber))
+			return &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrInvalidAccountNumber),
+				Data:    nil,
+			}
+		}
+	}
+	return nil
+}
+
+// SetDefaultAccount makes the account the one used when the user does not name one.
+func (l accountManagmentSvcLogic) SetDefaultAccount(id string, account model.DefaultAccount) *respModel.Response {
+	if resp := l.checkAccountNumbers(account.AccountNumber); resp != nil {
+		return resp
+	}
+	err := l.DsSvc.SetDefaultAccount(id, account.AccountNumber)
+	if err != nil {
+		log.Error(err)
+		switch {
+		case errors.Is(err, datasource.ErrAccountNotFound):
+			return &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.AccNotFound),
+				Data:    nil,
+			}
+		case errors.Is(err, datasource.ErrAccountClosed):
+			return &respModel.Response{
+				Status:  http.StatusConflict,
+				Message: codes.GetErr(codes.ErrAccountClosed),
+				Data:    nil,
+			}
+		}
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrUpdatingDefault),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    nil,
+	}
+}
+
+// Members lists the members of the account, any member may see who else the account is shared with.
+func (l accountManagmentSvcLogic) Members(id string, accountNumber int) *respModel.Response {
+	acc, resp := l.userAccount(id, accountNumber, model.RoleViewer)
+	if resp != nil {
+		return resp
+	}
+	members, err := l.DsSvc.GetMembers(acc.AccountNumber)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrFetchingMembers),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+		Data:    append([]model.Member{}, members...),
+	}
+}
+
+// InviteMember shares the account of the owner with another user in the given role, closed accounts are not shared.
+func (l accountManagmentSvcLogic) InviteMember(id string, accountNumber int, invite model.InviteMember) *respModel.Response {
+	acc, resp := l.userAccount(id, accountNumber, model.RoleOwner)
+	if resp != nil {
+		return resp
+	}
+	if acc.Status == model.AccountClosed {
+		return &respModel.Response{
+			Status:  http.StatusConflict,
+			Message: codes.GetErr(codes.ErrAccountClosed),
+			Data:    nil,
+		}
+	}
+	member := model.Member{AccountNumber: acc.AccountNumber, UserId: invite.UserId, Role: invite.Role}
+	err := l.DsSvc.InsertMember(member)
+	if errors.Is(err, datasource.ErrMemberExists) {
+		return &respModel.Response{
+			Status:  http.StatusConflict,
+			Message: codes.GetErr(codes.ErrMemberExists),
+			Data:    nil,
+		}
+	}
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrUpdatingMembers),
+			Data:    nil,
+		}
+	}
 	return &respModel.Response{
 		Status:  http.StatusCreated,
 		Message: "SUCCESS",
+		Data:    member,
+	}
+}
+
+// RemoveMember stops sharing the account of the owner with the user, the owner cannot remove themselves.
+func (l accountManagmentSvcLogic) RemoveMember(id string, accountNumber int, userId string) *respModel.Response {
+	acc, resp := l.userAccount(id, accountNumber, model.RoleOwner)
+	if resp != nil {
+		return resp
+	}
+	err := l.DsSvc.DeleteMember(acc.AccountNumber, userId)
+	if errors.Is(err, datasource.ErrMemberNotFound) {
+		return &respModel.Response{
+			Status:  http.StatusNotFound,
+			Message: codes.GetErr(codes.ErrMemberNotFound),
+			Data:    nil,
+		}
+	}
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrUpdatingMembers),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
 		Data:    nil,
 	}
 }
 
-func (l accountManagmentSvcLogic) AccountDetails(id string) *respModel.Response {
-	acc, err := l.DsSvc.Get(map[string]interface{}{"user_id": id})
+// AccountDetails lists the summaries of the accounts the user is a member of, or of the account only when an account
+// number is given, as they were at asOf unless asOf is zero. Past summaries are read from the account history and have no
+// pending holds, as the holds are not kept in it. Accounts opened after asOf are left out.
+func (l accountManagmentSvcLogic) AccountDetails(id string, accountNumber int, asOf time.Time) *respModel.Response {
+	if resp := l.checkAccountNumbers(accountNumber); resp != nil {
+		return resp
+	}
+	acc, err := l.DsSvc.GetMemberAccounts(id, accountNumber)
 	if err != nil {
 		log.Error(err)
 		return &respModel.Response{
@@ -103,21 +418,81 @@ func (l accountManagmentSvcLogic) AccountDetails(id string) *respModel.Response
 			Data:    nil,
 		}
 	}
-	resp := model.AccountSummary{
-		AccountNumber:    acc[0].AccountNumber,
-		Income:           acc[0].Income,
-		Spends:           acc[0].Spends,
-		ActiveServices:   acc[0].ActiveServices,
-		InactiveServices: acc[0].InactiveServices,
+	summaries := make([]model.AccountSummary, 0, len(acc))
+	for _, a := range acc {
+		var summary model.AccountSummary
+		if asOf.IsZero() {
+			holds, err := l.DsSvc.GetHolds(a.AccountNumber, model.HoldPending)
+			if err != nil {
+				log.Error(err)
+				return &respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingHolds),
+					Data:    nil,
+				}
+			}
+			if holds == nil {
+				holds = []model.Hold{}
+			}
+			summary = accountSummary(a.Account)
+			summary.PendingHolds = holds
+		} else {
+			past, err := l.DsSvc.GetAccountAsOf(a.AccountNumber, asOf)
+			if errors.Is(err, datasource.ErrNoHistory) {
+				continue
+			}
+			if err != nil {
+				log.Error(err)
+				return &respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingHistory),
+					Data:    nil,
+				}
+			}
+			// the history does not keep which account is the default one
+			past.Default = a.Default
+			summary = accountSummary(past)
+			summary.AsOf, summary.ChangedOn = &asOf, &past.UpdatedOn
+		}
+		summary.Role = a.Role
+		summaries = append(summaries, summary)
+	}
+	if len(summaries) == 0 {
+		return &respModel.Response{
+			Status:  http.StatusNotFound,
+			Message: codes.GetErr(codes.NoAccountHistory),
+			Data:    nil,
+		}
 	}
 	return &respModel.Response{
 		Status:  http.StatusOK,
 		Message: "SUCCESS",
-		Data:    resp,
+		Data:    summaries,
+	}
+}
+
+func accountSummary(acc model.Account) model.AccountSummary {
+	return model.AccountSummary{
+		AccountNumber:    acc.AccountNumber,
+		Income:           acc.Income,
+		Spends:           acc.Spends,
+		Currency:         acc.Currency,
+		AccountType:      acc.AccountType,
+		Balance:          acc.Balance(),
+		OverdraftLimit:   acc.OverdraftLimit,
+		AvailableBalance: acc.AvailableBalance(),
+		Held:             acc.Held,
+		ActiveServices:   acc.ActiveServices,
+		InactiveServices: acc.InactiveServices,
+		Status:           acc.Status,
+		Default:          acc.Default,
 	}
 }
 
 func (l accountManagmentSvcLogic) UpdateServices(id string, services model.UpdateServices) *respModel.Response {
+	if resp := l.checkAccountNumbers(services.AccountNumber); resp != nil {
+		return resp
+	}
 	var query map[string]interface{}
 	switch services.UpdateType {
 	case "add":
@@ -136,30 +511,75 @@ func (l accountManagmentSvcLogic) UpdateServices(id string, services model.Updat
 			Data:    nil,
 		}
 	}
-	err := l.DsSvc.Update(query, map[string]interface{}{"user_id": id, "account_number": services.AccountNumber})
+	acc, resp := l.userAccount(id, services.AccountNumber, model.RoleCoOwner)
+	if resp != nil {
+		return resp
+	}
+	// services are only taken up on active accounts, a frozen account may still drop them
+	if acc.Status != model.AccountActive && (acc.Status != model.AccountFrozen || services.UpdateType != "remove") {
+		return transactionErrResponse(serviceStatusErr(acc.Status), codes.GetErr(codes.ErrUpdatingServices))
+	}
+	fees, resp := l.serviceFees(acc, services)
+	if resp != nil {
+		return resp
+	}
+	feeIds, err := l.DsSvc.UpdateServices(acc.AccountNumber, query, fees...)
 	if err != nil {
 		log.Error(err)
-		return &respModel.Response{
-			Status:  http.StatusInternalServerError,
-			Message: codes.GetErr(codes.ErrUpdatingServices),
-			Data:    nil,
-		}
+		return transactionErrResponse(err, codes.GetErr(codes.ErrUpdatingServices))
+	}
+	var data interface{}
+	if len(feeIds) > 0 {
+		data = model.ServiceReceipt{FeeTransactionIds: feeIds}
 	}
 	return &respModel.Response{
 		Status:  http.StatusAccepted,
 		Message: "SUCCESS",
-		Data:    nil,
+		Data:    data,
+	}
+}
+
+// serviceFees returns the fees of adding the service, which are debited together with the change so an account that
+// cannot pay them does not get the service. Nothing is charged when the service is already active.
+func (l accountManagmentSvcLogic) serviceFees(acc model.Account, services model.UpdateServices) ([]model.Transaction, *respModel.Response) {
+	rules := l.fees.ServiceFees(services.ServiceId)
+	if services.UpdateType != "add" || len(rules) == 0 {
+		return nil, nil
+	}
+	if acc.ActiveServices != nil {
+		if _, ok := (*acc.ActiveServices)[services.ServiceId]; ok {
+			return nil, nil
+		}
+	}
+	var fees []model.Transaction
+	for _, rule := range rules {
+		fee, err := l.fee(rule, 0, acc)
+		if err != nil {
+			log.Error(err)
+			return nil, transactionErrResponse(err, codes.GetErr(codes.ErrUpdatingServices))
+		}
+		fees = append(fees, fee)
+	}
+	return fees, nil
+}
+
+// serviceStatusErr returns the error of changing the services of an account in the status.
+func serviceStatusErr(status string) error {
+	switch status {
+	case model.AccountFrozen:
+		return datasource.ErrAccountFrozen
+	case model.AccountClosed:
+		return datasource.ErrAccountClosed
 	}
+	return datasource.ErrAccountNotActive
 }
+
 func (l accountManagmentSvcLogic) UpdateTransaction(transaction model.UpdateTransaction) *respModel.Response {
-	var query map[string]interface{}
+	if resp := l.checkAccountNumbers(transaction.AccountNumber); resp != nil {
+		return resp
+	}
 	switch transaction.TransactionType {
-	case "debit":
-		spends := fmt.Sprintf("spends + %.2f", transaction.Amount)
-		query = map[string]interface{}{"spends": model.ColumnUpdate{UpdateSet: spends}}
-	case "credit":
-		spends := fmt.Sprintf("income + %.2f", transaction.Amount)
-		query = map[string]interface{}{"income": model.ColumnUpdate{UpdateSet: spends}}
+	case "debit", "credit":
 	default:
 		log.Error(errors.New("incorrect transaction type "))
 		return &respModel.Response{
@@ -168,18 +588,1710 @@ func (l accountManagmentSvcLogic) UpdateTransaction(transaction model.UpdateTran
 			Data:    nil,
 		}
 	}
-	err := l.DsSvc.Update(query, map[string]interface{}{"account_number": transaction.AccountNumber})
+	posting := model.Transaction{
+		AccountNumber:   transaction.AccountNumber,
+		Amount:          transaction.Amount,
+		TransactionType: transaction.TransactionType,
+		Reference:       transaction.Reference,
+		Category:        normalizeCategory(transaction.Category),
+		Merchant:        strings.TrimSpace(transaction.Merchant),
+	}
+	var fees []model.Transaction
+	var limits model.SpendLimits
+	debit := posting.TransactionType == "debit"
+	// without a currency the transaction is taken to be in the currency of the account
+	if transaction.Currency != "" || l.fees.ChargesTransactions(transaction.TransactionType) || (debit && len(l.limits.Tiers) > 0) {
+		account, err := l.account(transaction.AccountNumber)
+		if err == nil && transaction.Currency != "" {
+			posting, err = l.convert(posting, transaction.Currency, account.Currency)
+		}
+		if err == nil {
+			fees, err = l.transactionFees(posting, account)
+		}
+		if err != nil {
+			log.Error(err)
+			return transactionErrResponse(err, codes.GetErr(codes.ErrUpdatingTransaction))
+		}
+		if debit {
+			limits = l.limits.Tier(account.AccountType)
+		}
+	}
+	if debit {
+		override, err := l.DsSvc.GetSpendLimits(transaction.AccountNumber)
+		if err != nil {
+			log.Error(err)
+			return transactionErrResponse(err, codes.GetErr(codes.ErrUpdatingTransaction))
+		}
+		if override != nil {
+			limits = override.Apply(limits)
+		}
+	}
+	receipt, err := l.postWithFees(posting, fees, limits, time.Now())
+	if err != nil {
+		log.Error(err)
+		return transactionErrResponse(err, codes.GetErr(codes.ErrUpdatingTransaction))
+	}
+	if posting.TransactionType == "debit" {
+		posting.Id = receipt.TransactionId
+		l.checkBudgets(posting, fees, time.Now())
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    receipt,
+	}
+}
+
+// transactionFees returns the fee entries the rules charge on the posting, in the currency of the account.
+// Fees rounding down to nothing are left out.
+func (l accountManagmentSvcLogic) transactionFees(posting model.Transaction, account model.Account) ([]model.Transaction, error) {
+	var fees []model.Transaction
+	for _, rule := range l.fees.TransactionFees(posting.TransactionType, account.ActiveServices) {
+		fee, err := l.fee(rule, posting.Amount, account)
+		if err != nil {
+			return nil, err
+		}
+		if fee.Amount > 0 {
+			fees = append(fees, fee)
+		}
+	}
+	return fees, nil
+}
+
+// fee builds the debit charging the rule on the account, flat fees in another currency are converted to the account currency.
+func (l accountManagmentSvcLogic) fee(rule config.FeeRule, amount model.Money, account model.Account) (model.Transaction, error) {
+	fee := model.Transaction{
+		AccountNumber:   account.AccountNumber,
+		Amount:          rule.Charge(amount),
+		Currency:        account.Currency,
+		TransactionType: "debit",
+		Reference:       rule.Name,
+		Category:        feeCategory,
+		Contra:          model.LedgerFees,
+	}
+	if rule.Type == config.FeeFlat && rule.Currency != "" {
+		return l.convert(fee, rule.Currency, account.Currency)
+	}
+	return fee, nil
+}
+
+// postWithFees posts the transaction together with its fees, the receipt lists the fee entries when there are any.
+// A debit with spend limits is checked against the debits posted since the start of the day (UTC).
+func (l accountManagmentSvcLogic) postWithFees(posting model.Transaction, fees []model.Transaction, limits model.SpendLimits, now time.Time) (model.TransactionReceipt, error) {
+	var ids []int64
+	var err error
+	switch {
+	case !limits.IsZero():
+		ids, err = l.DsSvc.InsertDebit(limits, startOfDay(now), posting, fees...)
+	case len(fees) == 0:
+		id, err := l.DsSvc.InsertTransaction(posting)
+		return model.TransactionReceipt{TransactionId: id}, err
+	default:
+		ids, err = l.DsSvc.InsertTransactionWithFees(posting, fees...)
+	}
+	if err != nil {
+		return model.TransactionReceipt{}, err
+	}
+	receipt := model.TransactionReceipt{TransactionId: ids[0]}
+	if len(fees) > 0 {
+		receipt.FeeTransactionIds = ids[1:]
+	}
+	return receipt, nil
+}
+
+func (l accountManagmentSvcLogic) TransactionHistory(id string, filter model.TransactionFilter) *respModel.Response {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	if (filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount) || (!filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To)) {
+		log.Error(errors.New("incorrect filter range"))
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidQuery),
+			Data:    nil,
+		}
+	}
+	acc, resp := l.userAccount(id, filter.AccountNumber, model.RoleViewer)
+	if resp != nil {
+		return resp
+	}
+	filter.AccountNumber = acc.AccountNumber
+	// fetch one extra row to know whether another page exists
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	transactions, err := l.DsSvc.GetTransactions(filter)
 	if err != nil {
 		log.Error(err)
 		return &respModel.Response{
 			Status:  http.StatusInternalServerError,
-			Message: codes.GetErr(codes.ErrUpdatingTransaction),
+			Message: codes.GetErr(codes.ErrFetchingTransactions),
+			Data:    nil,
+		}
+	}
+	history := model.TransactionHistory{Transactions: []model.Transaction{}}
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		history.NextCursor = transactions[limit-1].Id
+	}
+	history.Transactions = append(history.Transactions, transactions...)
+	return &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+		Data:    history,
+	}
+}
+
+// Transfer debits the source account and credits the destination account in a single database transaction.
+func (l accountManagmentSvcLogic) Transfer(transfer model.Transfer) *respModel.Response {
+	if resp := l.checkAccountNumbers(transfer.FromAccount, transfer.ToAccount); resp != nil {
+		return resp
+	}
+	if transfer.FromAccount == transfer.ToAccount {
+		log.Error(codes.GetErr(codes.ErrSameAccountTransfer))
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrSameAccountTransfer),
+			Data:    nil,
+		}
+	}
+	if transfer.Amount <= 0 {
+		log.Error(codes.GetErr(codes.ErrInvalidAmount))
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidAmount),
 			Data:    nil,
 		}
 	}
+	// the amount is in the currency of the source account, the credit is converted when the destination differs
+	fromCurrency, err := l.accountCurrency(transfer.FromAccount)
+	if err != nil {
+		log.Error(err)
+		return transactionErrResponse(err, codes.GetErr(codes.ErrTransferringFunds))
+	}
+	toCurrency, err := l.accountCurrency(transfer.ToAccount)
+	if err != nil {
+		log.Error(err)
+		return transactionErrResponse(err, codes.GetErr(codes.ErrTransferringFunds))
+	}
+	credit, err := l.convert(model.Transaction{
+		AccountNumber:   transfer.ToAccount,
+		Amount:          transfer.Amount,
+		TransactionType: "credit",
+		Reference:       transfer.Reference,
+	}, fromCurrency, toCurrency)
+	if err != nil {
+		log.Error(err)
+		return transactionErrResponse(err, codes.GetErr(codes.ErrTransferringFunds))
+	}
+	limits, err := l.spendLimits(transfer.FromAccount)
+	if err != nil {
+		log.Error(err)
+		return transactionErrResponse(err, codes.GetErr(codes.ErrTransferringFunds))
+	}
+	ids, err := l.DsSvc.InsertTransfer(limits, startOfDay(time.Now()), model.Transaction{
+		AccountNumber:   transfer.FromAccount,
+		Amount:          transfer.Amount,
+		Currency:        fromCurrency,
+		TransactionType: "debit",
+		Reference:       transfer.Reference,
+	}, credit)
+	if err != nil {
+		log.Error(err)
+		return transactionErrResponse(err, codes.GetErr(codes.ErrTransferringFunds))
+	}
 	return &respModel.Response{
 		Status:  http.StatusAccepted,
 		Message: "SUCCESS",
-		Data:    nil,
+		Data:    model.TransferReceipt{DebitTransactionId: ids[0], CreditTransactionId: ids[1]},
+	}
+}
+
+// ReverseTransaction refunds the whole or part of a recorded transaction with a compensating ledger entry linked to it.
+func (l accountManagmentSvcLogic) ReverseTransaction(reversal model.Reversal) *respModel.Response {
+	id, err := l.DsSvc.ReverseTransaction(reversal.TransactionId, reversal.Amount, reversal.Reference)
+	if err != nil {
+		log.Error(err)
+		return reversalErrResponse(err)
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    model.TransactionReceipt{TransactionId: id},
+	}
+}
+
+// UpdateOverdraftLimit sets how far below zero debits may take the balance of the account.
+func (l accountManagmentSvcLogic) UpdateOverdraftLimit(limit model.OverdraftLimit) *respModel.Response {
+	if resp := l.checkAccountNumbers(limit.AccountNumber); resp != nil {
+		return resp
+	}
+	acc, err := l.DsSvc.Get(map[string]interface{}{"account_number": limit.AccountNumber})
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrUpdatingOverdraft),
+			Data:    nil,
+		}
+	}
+	if len(acc) == 0 {
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.AccNotFound),
+			Data:    nil,
+		}
+	}
+	err = l.DsSvc.Update(map[string]interface{}{"overdraft_limit": limit.Limit}, map[string]interface{}{"account_number": limit.AccountNumber})
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrUpdatingOverdraft),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    nil,
+	}
+}
+
+// UpdateAccountStatus moves the account to the requested status when its lifecycle allows it.
+func (l accountManagmentSvcLogic) UpdateAccountStatus(update model.UpdateAccountStatus) *respModel.Response {
+	if resp := l.checkAccountNumbers(update.AccountNumber); resp != nil {
+		return resp
+	}
+	previous, err := l.DsSvc.SetAccountStatus(update.AccountNumber, update.Status)
+	if err != nil {
+		log.Error(err)
+		switch {
+		case errors.Is(err, datasource.ErrAccountNotFound):
+			return &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.AccNotFound),
+				Data:    nil,
+			}
+		case errors.Is(err, datasource.ErrInvalidTransition):
+			return &respModel.Response{
+				Status:  http.StatusConflict,
+				Message: codes.GetErr(codes.ErrInvalidTransition),
+				Data:    model.AccountStatusChange{AccountNumber: update.AccountNumber, Status: previous},
+			}
+		case errors.Is(err, datasource.ErrBalanceNotSettled):
+			return &respModel.Response{
+				Status:  http.StatusConflict,
+				Message: codes.GetErr(codes.ErrBalanceNotSettled),
+				Data:    model.AccountStatusChange{AccountNumber: update.AccountNumber, Status: previous},
+			}
+		case errors.Is(err, datasource.ErrHoldsNotSettled):
+			return &respModel.Response{
+				Status:  http.StatusConflict,
+				Message: codes.GetErr(codes.ErrHoldsNotSettled),
+				Data:    model.AccountStatusChange{AccountNumber: update.AccountNumber, Status: previous},
+			}
+		}
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrUpdatingStatus),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    model.AccountStatusChange{AccountNumber: update.AccountNumber, Status: update.Status, PreviousStatus: previous},
+	}
+}
+
+// CloseAccount closes the accounts of a deleted user once their balance and holds are settled and publishes whether
+// the closure of each account was confirmed or rejected. The memberships of the user are removed as well, except the
+// ownership of the accounts not closed yet. A failure reading or writing an account stops the closure
+// without publishing for that account, so the closure can be sent again and the accounts closed so far are confirmed again.
+func (l accountManagmentSvcLogic) CloseAccount(closure model.AccountClosure) *respModel.Response {
+	acc, err := l.DsSvc.Get(map[string]interface{}{"user_id": closure.UserId})
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrClosingAccount),
+			Data:    nil,
+		}
+	}
+	if len(acc) == 0 {
+		// the user may still be a member of accounts of other users
+		err = l.DsSvc.DeleteUserMembers(closure.UserId)
+		if err != nil {
+			log.Error(err)
+			return &respModel.Response{
+				Status:  http.StatusInternalServerError,
+				Message: codes.GetErr(codes.ErrClosingAccount),
+				Data:    nil,
+			}
+		}
+		l.publishClosure(model.ClosureEvent{Event: model.ClosureRejected, UserId: closure.UserId, Reason: codes.GetErr(codes.AccNotFound)})
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.AccNotFound),
+			Data:    nil,
+		}
+	}
+	resp := &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+	}
+	events := make([]model.ClosureEvent, 0, len(acc))
+	for _, a := range acc {
+		event := model.ClosureEvent{Event: model.ClosureConfirmed, UserId: closure.UserId, AccountNumber: a.AccountNumber}
+		var err error
+		// an account already closed was closed by an earlier delivery of the closure, it is confirmed again
+		if a.Status != model.AccountClosed {
+			_, err = l.DsSvc.SetAccountStatus(a.AccountNumber, model.AccountClosed)
+		}
+		if err != nil {
+			log.Error(err)
+			switch {
+			case errors.Is(err, datasource.ErrBalanceNotSettled):
+				event.Reason = codes.GetErr(codes.ErrBalanceNotSettled)
+			case errors.Is(err, datasource.ErrHoldsNotSettled):
+				event.Reason = codes.GetErr(codes.ErrHoldsNotSettled)
+			case errors.Is(err, datasource.ErrInvalidTransition):
+				event.Reason = codes.GetErr(codes.ErrInvalidTransition)
+			default:
+				return &respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrClosingAccount),
+					Data:    nil,
+				}
+			}
+			event.Event = model.ClosureRejected
+			resp.Status, resp.Message = http.StatusConflict, event.Reason
+		}
+		l.publishClosure(event)
+		events = append(events, event)
+	}
+	err = l.DsSvc.DeleteUserMembers(closure.UserId)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrClosingAccount),
+			Data:    nil,
+		}
+	}
+	resp.Data = events
+	return resp
+}
+
+// publishClosure publishes the closure result when a result channel is configured, failures are only logged.
+func (l accountManagmentSvcLogic) publishClosure(event model.ClosureEvent) {
+	if l.msgQueue.ClosurePubId == "" {
+		return
+	}
+	msg, err := json.Marshal(event)
+	if err == nil {
+		err = l.msgQueue.MsgBroker.PushMsg(string(msg), l.msgQueue.ClosurePubId, l.msgQueue.ClosureChannel)
+	}
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// UpdateSpendLimits overrides the spend limits of the account tier for the account, the limits left out fall back
+// to the tier defaults again. The limits now in force are returned.
+func (l accountManagmentSvcLogic) UpdateSpendLimits(limits model.UpdateSpendLimits) *respModel.Response {
+	if resp := l.checkAccountNumbers(limits.AccountNumber); resp != nil {
+		return resp
+	}
+	if limits.MaxDailyCount != nil && *limits.MaxDailyCount < 0 {
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidSpendLimits),
+			Data:    nil,
+		}
+	}
+	acc, err := l.DsSvc.Get(map[string]interface{}{"account_number": limits.AccountNumber})
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrUpdatingSpendLimits),
+			Data:    nil,
+		}
+	}
+	if len(acc) == 0 {
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.AccNotFound),
+			Data:    nil,
+		}
+	}
+	override := model.SpendLimitOverride{
+		AccountNumber: limits.AccountNumber,
+		MaxDebit:      limits.MaxDebit,
+		MaxDailyDebit: limits.MaxDailyDebit,
+		MaxDailyCount: limits.MaxDailyCount,
+	}
+	err = l.DsSvc.UpsertSpendLimits(override)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrUpdatingSpendLimits),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    override.Apply(l.limits.Tier(acc[0].AccountType)),
+	}
+}
+
+// Statement renders the opening balance, the transactions and the closing balance of the calendar month (UTC)
+// containing month in the requested format.
+func (l accountManagmentSvcLogic) Statement(id string, accountNumber int, month time.Time, format string) *respModel.Response {
+	var render func(model.Statement) ([]byte, error)
+	var contentType, renderErr string
+	switch format {
+	case StatementFormatPdf:
+		render, contentType, renderErr = statement.PDF, "application/pdf", codes.GetErr(codes.ErrConvertingToPdf)
+	case StatementFormatCsv:
+		render, contentType, renderErr = statement.CSV, "text/csv", codes.GetErr(codes.ErrEncodingFile)
+	default:
+		log.Error(fmt.Errorf("incorrect statement format %s", format))
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidQuery),
+			Data:    nil,
+		}
+	}
+	acc, resp := l.userAccount(id, accountNumber, model.RoleViewer)
+	if resp != nil {
+		return resp
+	}
+	month = month.UTC()
+	s := model.Statement{
+		AccountNumber: acc.AccountNumber,
+		Currency:      acc.Currency,
+		From:          time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC),
+	}
+	s.To = s.From.AddDate(0, 1, 0)
+	var err error
+	s.OpeningBalance, err = l.DsSvc.GetBalance(s.AccountNumber, s.From)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrFetchingTransactions),
+			Data:    nil,
+		}
+	}
+	// created_on is stored with second precision, so the last second of the month closes the period
+	transactions, err := l.DsSvc.GetTransactions(model.TransactionFilter{AccountNumber: s.AccountNumber, From: s.From, To: s.To.Add(-time.Second)})
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrFetchingTransactions),
+			Data:    nil,
+		}
+	}
+	s.ClosingBalance = s.OpeningBalance
+	s.Transactions = make([]model.Transaction, 0, len(transactions))
+	for i := len(transactions) - 1; i >= 0; i-- {
+		s.Transactions = append(s.Transactions, transactions[i])
+		s.ClosingBalance += transactions[i].SignedAmount()
+	}
+	content, err := render(s)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: renderErr,
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+		Data: model.StatementFile{
+			Name:        fmt.Sprintf("statement-%d-%s.%s", s.AccountNumber, s.From.Format("2006-01"), format),
+			ContentType: contentType,
+			Content:     content,
+		},
+	}
+}
+
+// Analytics reports the spends per category and the income against the spends of every calendar month (UTC)
+// from the month of from to the month of to, both included. Months without transactions are reported as zero.
+func (l accountManagmentSvcLogic) Analytics(id string, accountNumber int, from time.Time, to time.Time) *respModel.Response {
+	from, to = from.UTC(), to.UTC()
+	first := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	months := (last.Year()-first.Year())*12 + int(last.Month()) - int(first.Month()) + 1
+	if months < 1 || months > maxAnalyticsMonths {
+		log.Error(fmt.Errorf("incorrect analytics window of %d months", months))
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidQuery),
+			Data:    nil,
+		}
+	}
+	acc, resp := l.userAccount(id, accountNumber, model.RoleViewer)
+	if resp != nil {
+		return resp
+	}
+	totals, err := l.DsSvc.GetTransactionTotals(acc.AccountNumber, first, last.AddDate(0, 1, 0))
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrFetchingAnalytics),
+			Data:    nil,
+		}
+	}
+	analytics := model.Analytics{
+		AccountNumber:   acc.AccountNumber,
+		Currency:        acc.Currency,
+		From:            first.Format("2006-01"),
+		To:              last.Format("2006-01"),
+		SpendByCategory: []model.CategorySpend{},
+		Monthly:         make([]model.MonthlySummary, months),
+	}
+	monthIndex := make(map[string]int, months)
+	for i := range analytics.Monthly {
+		analytics.Monthly[i].Month = first.AddDate(0, i, 0).Format("2006-01")
+		monthIndex[analytics.Monthly[i].Month] = i
+	}
+	categoryIndex := map[string]int{}
+	for _, t := range totals {
+		if i, ok := monthIndex[t.Month]; ok {
+			analytics.Monthly[i].Income += t.Income
+			analytics.Monthly[i].Spends += t.Spends
+		}
+		if t.Spends == 0 {
+			continue
+		}
+		category := t.Category
+		if category == "" {
+			category = uncategorized
+		}
+		i, ok := categoryIndex[category]
+		if !ok {
+			i = len(analytics.SpendByCategory)
+			categoryIndex[category] = i
+			analytics.SpendByCategory = append(analytics.SpendByCategory, model.CategorySpend{Category: category})
+		}
+		analytics.SpendByCategory[i].Spends += t.Spends
+	}
+	for i := range analytics.Monthly {
+		analytics.Monthly[i].Net = analytics.Monthly[i].Income - analytics.Monthly[i].Spends
+	}
+	// largest spends first
+	sort.SliceStable(analytics.SpendByCategory, func(i, j int) bool {
+		if analytics.SpendByCategory[i].Spends != analytics.SpendByCategory[j].Spends {
+			return analytics.SpendByCategory[i].Spends > analytics.SpendByCategory[j].Spends
+		}
+		return analytics.SpendByCategory[i].Category < analytics.SpendByCategory[j].Category
+	})
+	return &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+		Data:    analytics,
+	}
+}
+
+// CreateStandingOrder registers a transaction posted every time the schedule fires until the end date, on an account
+// the user is at least a co-owner of.
+func (l accountManagmentSvcLogic) CreateStandingOrder(id string, order model.NewStandingOrder) *respModel.Response {
+	if order.Amount <= 0 {
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidAmount),
+			Data:    nil,
+		}
+	}
+	schedule, err := scheduler.ParseSchedule(order.Schedule)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidSchedule),
+			Data:    nil,
+		}
+	}
+	nextRun := schedule.Next(time.Now())
+	if nextRun.IsZero() || (order.EndDate != nil && nextRun.After(*order.EndDate)) {
+		log.Error(fmt.Errorf("schedule %q never runs", order.Schedule))
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidSchedule),
+			Data:    nil,
+		}
+	}
+	acc, resp := l.userAccount(id, order.AccountNumber, model.RoleCoOwner)
+	if resp != nil {
+		return resp
+	}
+	if order.Currency != "" {
+		// the amount is converted on every run, make sure now that a rate is configured
+		_, err = l.convert(model.Transaction{Amount: order.Amount}, order.Currency, acc.Currency)
+		if err != nil {
+			log.Error(err)
+			return transactionErrResponse(err, codes.GetErr(codes.ErrCreatingStandingOrder))
+		}
+	}
+	standingOrder := model.StandingOrder{
+		AccountNumber:   acc.AccountNumber,
+		Amount:          order.Amount,
+		Currency:        order.Currency,
+		TransactionType: order.TransactionType,
+		Reference:       order.Reference,
+		Schedule:        order.Schedule,
+		EndDate:         order.EndDate,
+		NextRun:         nextRun,
+		Status:          model.StandingOrderActive,
+	}
+	standingOrder.Id, err = l.DsSvc.InsertStandingOrder(standingOrder)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrCreatingStandingOrder),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusCreated,
+		Message: "SUCCESS",
+		Data:    standingOrder,
+	}
+}
+
+// StandingOrders lists the standing orders of an account the user is a member of, their default account unless an
+// account number is given.
+func (l accountManagmentSvcLogic) StandingOrders(id string, accountNumber int) *respModel.Response {
+	acc, resp := l.userAccount(id, accountNumber, model.RoleViewer)
+	if resp != nil {
+		return resp
+	}
+	orders, err := l.DsSvc.GetStandingOrders(acc.AccountNumber)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrListingStandingOrders),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+		Data:    append([]model.StandingOrder{}, orders...),
+	}
+}
+
+func (l accountManagmentSvcLogic) CancelStandingOrder(id string, accountNumber int, orderId int64) *respModel.Response {
+	acc, resp := l.userAccount(id, accountNumber, model.RoleCoOwner)
+	if resp != nil {
+		return resp
+	}
+	err := l.DsSvc.CancelStandingOrder(orderId, acc.AccountNumber)
+	if errors.Is(err, datasource.ErrStandingOrderNotFound) {
+		return &respModel.Response{
+			Status:  http.StatusNotFound,
+			Message: codes.GetErr(codes.StandingOrderNotFound),
+			Data:    nil,
+		}
+	}
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrCancelStandingOrder),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    nil,
+	}
+}
+
+// RunStandingOrders posts every standing order due at now through UpdateTransaction. Runs missed while the
+// service was down are posted once, the order then moves on to its first run after now.
+func (l accountManagmentSvcLogic) RunStandingOrders(now time.Time) {
+	for {
+		due, err := l.DsSvc.GetDueStandingOrders(now, standingOrderBatch)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		for _, order := range due {
+			if !l.runStandingOrder(order, now) {
+				return
+			}
+		}
+		if len(due) < standingOrderBatch {
+			return
+		}
+	}
+}
+
+// runStandingOrder reports false when the database could not be reached, the remaining orders are then left
+// for the next tick.
+func (l accountManagmentSvcLogic) runStandingOrder(order model.StandingOrder, now time.Time) bool {
+	status := model.StandingOrderActive
+	schedule, parseErr := scheduler.ParseSchedule(order.Schedule)
+	nextRun := order.NextRun
+	if parseErr == nil {
+		nextRun = schedule.Next(now)
+	}
+	if parseErr != nil || nextRun.IsZero() || (order.EndDate != nil && nextRun.After(*order.EndDate)) {
+		status = model.StandingOrderCompleted
+	}
+	advanced, err := l.DsSvc.AdvanceStandingOrder(order, nextRun, status)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if !advanced {
+		// another instance already posted this run or the order was cancelled
+		return true
+	}
+	var transactionId int64
+	runErr := ""
+	if parseErr != nil {
+		runErr = codes.GetErr(codes.ErrInvalidSchedule)
+		log.Error(parseErr)
+	} else {
+		reference := order.Reference
+		if reference == "" {
+			reference = fmt.Sprintf("standing order %d", order.Id)
+		}
+		resp := l.UpdateTransaction(model.UpdateTransaction{
+			AccountNumber:   order.AccountNumber,
+			Amount:          order.Amount,
+			TransactionType: order.TransactionType,
+			Currency:        order.Currency,
+			Reference:       reference,
+		})
+		if receipt, ok := resp.Data.(model.TransactionReceipt); ok {
+			transactionId = receipt.TransactionId
+		} else {
+			runErr = resp.Message
+			log.Error(fmt.Sprintf("standing order %d failed: %s", order.Id, resp.Message))
+		}
+	}
+	err = l.DsSvc.RecordStandingOrderRun(order.Id, now, transactionId, runErr)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	return true
+}
+
+// CreateBudget sets a monthly limit on the spends of a category, or on all spends when no category is given.
+func (l accountManagmentSvcLogic) CreateBudget(id string, accountNumber int, budget model.NewBudget) *respModel.Response {
+	if budget.Limit <= 0 {
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidAmount),
+			Data:    nil,
+		}
+	}
+	acc, resp := l.userAccount(id, accountNumber, model.RoleCoOwner)
+	if resp != nil {
+		return resp
+	}
+	b := model.Budget{AccountNumber: acc.AccountNumber, Category: normalizeCategory(budget.Category), Limit: budget.Limit}
+	var err error
+	b.Id, err = l.DsSvc.InsertBudget(b)
+	if errors.Is(err, datasource.ErrBudgetExists) {
+		return &respModel.Response{
+			Status:  http.StatusConflict,
+			Message: codes.GetErr(codes.ErrBudgetExists),
+			Data:    nil,
+		}
+	}
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrCreatingBudget),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusCreated,
+		Message: "SUCCESS",
+		Data:    b,
+	}
+}
+
+// Budgets lists the budgets of the account of the user with what they spent against each of them in the current month.
+func (l accountManagmentSvcLogic) Budgets(id string, accountNumber int) *respModel.Response {
+	acc, resp := l.userAccount(id, accountNumber, model.RoleViewer)
+	if resp != nil {
+		return resp
+	}
+	budgets, err := l.DsSvc.GetBudgets(acc.AccountNumber)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrFetchingBudgets),
+			Data:    nil,
+		}
+	}
+	usage := make([]model.BudgetUsage, 0, len(budgets))
+	if len(budgets) == 0 {
+		return &respModel.Response{
+			Status:  http.StatusOK,
+			Message: "SUCCESS",
+			Data:    usage,
+		}
+	}
+	month := startOfMonth(time.Now())
+	spends, overall, err := l.monthSpends(acc.AccountNumber, month)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrFetchingBudgets),
+			Data:    nil,
+		}
+	}
+	for _, b := range budgets {
+		spent := overall
+		if b.Category != "" {
+			spent = spends[b.Category]
+		}
+		usage = append(usage, model.BudgetUsage{Budget: b, Month: month.Format("2006-01"), Spent: spent})
+	}
+	return &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+		Data:    usage,
+	}
+}
+
+func (l accountManagmentSvcLogic) UpdateBudget(id string, accountNumber int, budgetId int64, budget model.UpdateBudget) *respModel.Response {
+	if budget.Limit <= 0 {
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidAmount),
+			Data:    nil,
+		}
+	}
+	acc, resp := l.userAccount(id, accountNumber, model.RoleCoOwner)
+	if resp != nil {
+		return resp
+	}
+	budgets, err := l.DsSvc.GetBudgets(acc.AccountNumber)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrUpdatingBudget),
+			Data:    nil,
+		}
+	}
+	// only the budgets of the account of the user can be changed
+	for _, b := range budgets {
+		if b.Id != budgetId {
+			continue
+		}
+		b.Limit = budget.Limit
+		err = l.DsSvc.UpdateBudget(b)
+		if err != nil {
+			log.Error(err)
+			return &respModel.Response{
+				Status:  http.StatusInternalServerError,
+				Message: codes.GetErr(codes.ErrUpdatingBudget),
+				Data:    nil,
+			}
+		}
+		return &respModel.Response{
+			Status:  http.StatusAccepted,
+			Message: "SUCCESS",
+			Data:    b,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusNotFound,
+		Message: codes.GetErr(codes.BudgetNotFound),
+		Data:    nil,
+	}
+}
+
+func (l accountManagmentSvcLogic) DeleteBudget(id string, accountNumber int, budgetId int64) *respModel.Response {
+	acc, resp := l.userAccount(id, accountNumber, model.RoleCoOwner)
+	if resp != nil {
+		return resp
+	}
+	err := l.DsSvc.DeleteBudget(budgetId, acc.AccountNumber)
+	if errors.Is(err, datasource.ErrBudgetNotFound) {
+		return &respModel.Response{
+			Status:  http.StatusNotFound,
+			Message: codes.GetErr(codes.BudgetNotFound),
+			Data:    nil,
+		}
+	}
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrDeletingBudget),
+			Data:    nil,
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    nil,
+	}
+}
+
+// checkBudgets publishes a budget alert for every budget the debit and the fees posted with it took to one of the
+// thresholds in the current month. The debit is already posted, so failures are only logged.
+func (l accountManagmentSvcLogic) checkBudgets(debit model.Transaction, fees []model.Transaction, now time.Time) {
+	if l.msgQueue.BudgetAlertPubId == "" {
+		return
+	}
+	budgets, err := l.DsSvc.GetBudgets(debit.AccountNumber)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if len(budgets) == 0 {
+		return
+	}
+	acc, err := l.DsSvc.Get(map[string]interface{}{"account_number": debit.AccountNumber})
+	if err != nil || len(acc) == 0 {
+		log.Error(fmt.Errorf("fetching account %d for budget alerts: %v", debit.AccountNumber, err))
+		return
+	}
+	month := startOfMonth(now)
+	spends, overall, err := l.monthSpends(debit.AccountNumber, month)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, b := range budgets {
+		spent := overall
+		if b.Category != "" {
+			if b.Category != debit.Category {
+				continue
+			}
+			spent = spends[b.Category]
+		}
+		posted := debit.Amount
+		for _, fee := range fees {
+			if b.Category == "" || b.Category == fee.Category {
+				posted += fee.Amount
+			}
+		}
+		threshold := crossedThreshold(b.Limit, spent-posted, spent)
+		if threshold == 0 {
+			continue
+		}
+		alert, err := json.Marshal(model.BudgetAlert{
+			UserId:        acc[0].Id,
+			AccountNumber: debit.AccountNumber,
+			BudgetId:      b.Id,
+			Category:      b.Category,
+			Month:         month.Format("2006-01"),
+			Threshold:     threshold,
+			Limit:         b.Limit,
+			Spent:         spent,
+			Currency:      acc[0].Currency,
+			TransactionId: debit.Id,
+		})
+		if err == nil {
+			err = l.msgQueue.MsgBroker.PushMsg(string(alert), l.msgQueue.BudgetAlertPubId, l.msgQueue.BudgetAlertChannel)
+		}
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// monthSpends returns the spends of the calendar month starting at month per category, along with their total.
+func (l accountManagmentSvcLogic) monthSpends(accountNumber int, month time.Time) (map[string]model.Money, model.Money, error) {
+	totals, err := l.DsSvc.GetTransactionTotals(accountNumber, month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, 0, err
+	}
+	spends := make(map[string]model.Money, len(totals))
+	var overall model.Money
+	for _, t := range totals {
+		spends[t.Category] += t.Spends
+		overall += t.Spends
+	}
+	return spends, overall, nil
+}
+
+// PlaceHold reserves an amount on the account, in the account currency, until it is captured, released or expires.
+// The amount is taken off the available balance straight away but the balance itself only moves on capture.
+func (l accountManagmentSvcLogic) PlaceHold(hold model.NewHold) *respModel.Response {
+	if resp := l.checkAccountNumbers(hold.AccountNumber); resp != nil {
+		return resp
+	}
+	if hold.Amount <= 0 {
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidAmount),
+			Data:    nil,
+		}
+	}
+	now := time.Now().UTC()
+	expiresOn := now.Add(defaultHoldExpiry)
+	if hold.ExpiresOn != nil {
+		expiresOn = hold.ExpiresOn.UTC()
+	}
+	if !expiresOn.After(now) || expiresOn.After(now.Add(maxHoldExpiry)) {
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidHoldExpiry),
+			Data:    nil,
+		}
+	}
+	limits, err := l.spendLimits(hold.AccountNumber)
+	if err != nil {
+		log.Error(err)
+		return transactionErrResponse(err, codes.GetErr(codes.ErrPlacingHold))
+	}
+	id, err := l.DsSvc.InsertHold(limits, startOfDay(now), model.Hold{
+		AccountNumber: hold.AccountNumber,
+		Amount:        hold.Amount,
+		Reference:     hold.Reference,
+		Category:      normalizeCategory(hold.Category),
+		Merchant:      strings.TrimSpace(hold.Merchant),
+		ExpiresOn:     expiresOn,
+	})
+	if err != nil {
+		log.Error(err)
+		return transactionErrResponse(err, codes.GetErr(codes.ErrPlacingHold))
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    model.HoldReceipt{HoldId: id, ExpiresOn: expiresOn},
+	}
+}
+
+// CaptureHold settles a pending hold into a debit, a zero amount captures the whole hold.
+func (l accountManagmentSvcLogic) CaptureHold(capture model.CaptureHold) *respModel.Response {
+	if capture.Amount < 0 {
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidAmount),
+			Data:    nil,
+		}
+	}
+	hold, err := l.DsSvc.GetHold(capture.HoldId)
+	if err != nil {
+		log.Error(err)
+		return holdErrResponse(err, codes.GetErr(codes.ErrCapturingHold))
+	}
+	limits, err := l.spendLimits(hold.AccountNumber)
+	if err != nil {
+		log.Error(err)
+		return holdErrResponse(err, codes.GetErr(codes.ErrCapturingHold))
+	}
+	now := time.Now()
+	debit, err := l.DsSvc.CaptureHold(limits, startOfDay(now), capture.HoldId, capture.Amount, capture.Reference, now)
+	if err != nil {
+		log.Error(err)
+		return holdErrResponse(err, codes.GetErr(codes.ErrCapturingHold))
+	}
+	l.checkBudgets(debit, nil, now)
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    model.TransactionReceipt{TransactionId: debit.Id},
+	}
+}
+
+// ReleaseHold cancels a pending hold and gives the amount back to the available balance.
+func (l accountManagmentSvcLogic) ReleaseHold(release model.ReleaseHold) *respModel.Response {
+	err := l.DsSvc.ReleaseHold(release.HoldId, model.HoldReleased)
+	if err != nil {
+		log.Error(err)
+		return holdErrResponse(err, codes.GetErr(codes.ErrReleasingHold))
+	}
+	return &respModel.Response{
+		Status:  http.StatusAccepted,
+		Message: "SUCCESS",
+		Data:    nil,
+	}
+}
+
+// ExpireHolds releases every pending hold which expired by now. A hold captured or released after it was
+// fetched is skipped.
+func (l accountManagmentSvcLogic) ExpireHolds(now time.Time) {
+	for {
+		expired, err := l.DsSvc.GetExpiredHolds(now, holdExpiryBatch)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		for _, hold := range expired {
+			err = l.DsSvc.ReleaseHold(hold.Id, model.HoldExpired)
+			if err != nil && !errors.Is(err, datasource.ErrHoldNotPending) {
+				log.Error(err)
+				return
+			}
+		}
+		if len(expired) < holdExpiryBatch {
+			return
+		}
+	}
+}
+
+// PurgeIdempotencyKeys deletes the idempotency keys reserved before the given time, their retention window is over.
+func (l accountManagmentSvcLogic) PurgeIdempotencyKeys(before time.Time) {
+	count, err := l.DsSvc.DeleteExpiredIdempotencyKeys(before)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if count > 0 {
+		log.Info(fmt.Sprintf("purged %d idempotency keys reserved before %s", count, before.Format(time.RFC3339)))
+	}
+}
+
+// AccrueInterest posts the interest of the last complete calendar month (UTC) to every account earning interest
+// which was not paid for that month yet, running it again for the same month posts nothing.
+func (l accountManagmentSvcLogic) AccrueInterest(now time.Time) {
+	month := startOfMonth(now).AddDate(0, -1, 0)
+	posted, err := l.postedInterest(month)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	accounts, err := l.interestAccounts()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, account := range accounts {
+		// pending and closed accounts take no interest, frozen ones still do
+		if _, ok := posted[account.AccountNumber]; ok || !account.CreatedOn.Before(month.AddDate(0, 1, 0)) || account.Status == model.AccountPending || !account.Accepts("credit") {
+			continue
+		}
+		accrual, err := l.accrueInterest(account, month, l.interest.Rates[account.AccountType], l.interest.DayCount)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		_, err = l.DsSvc.PostInterest(accrual)
+		if err != nil && !errors.Is(err, datasource.ErrInterestPosted) {
+			log.Error(err)
+			return
+		}
+	}
+}
+
+// InterestReport recomputes the interest of every month from from to to (inclusive) for one account, or for every
+// account earning interest when accountNumber is 0. A month already posted is recomputed with the rate and day
+// count it was posted with and compared, a month not posted yet is posted when post is set.
+func (l accountManagmentSvcLogic) InterestReport(from time.Time, to time.Time, accountNumber int, post bool) *respModel.Response {
+	if resp := l.checkAccountNumbers(accountNumber); resp != nil {
+		return resp
+	}
+	from, to = startOfMonth(from), startOfMonth(to)
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	if months < 1 || months > maxInterestReportMonths || !to.Before(startOfMonth(time.Now())) {
+		log.Error(fmt.Errorf("incorrect interest report window of %d months ending %s", months, to.Format("2006-01")))
+		return &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.ErrInvalidQuery),
+			Data:    nil,
+		}
+	}
+	var accounts []model.Account
+	var err error
+	if accountNumber != 0 {
+		accounts, err = l.DsSvc.Get(map[string]interface{}{"account_number": accountNumber})
+		if err == nil && len(accounts) == 0 {
+			err = datasource.ErrAccountNotFound
+		}
+	} else {
+		accounts, err = l.interestAccounts()
+	}
+	if err != nil {
+		log.Error(err)
+		return transactionErrResponse(err, codes.GetErr(codes.ErrInterestReport))
+	}
+	lines := []model.InterestReportLine{}
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		posted, err := l.postedInterest(month)
+		if err != nil {
+			log.Error(err)
+			return transactionErrResponse(err, codes.GetErr(codes.ErrInterestReport))
+		}
+		for _, account := range accounts {
+			line, ok, err := l.interestReportLine(account, month, posted, post)
+			if err != nil {
+				log.Error(err)
+				return transactionErrResponse(err, codes.GetErr(codes.ErrInterestReport))
+			}
+			if ok {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+		Data:    lines,
+	}
+}
+
+// interestReportLine reports false for accounts which did not exist or earned no interest in the month.
+func (l accountManagmentSvcLogic) interestReportLine(account model.Account, month time.Time, posted map[int]model.InterestAccrual, post bool) (model.InterestReportLine, bool, error) {
+	if !account.CreatedOn.Before(month.AddDate(0, 1, 0)) {
+		return model.InterestReportLine{}, false, nil
+	}
+	if previous, ok := posted[account.AccountNumber]; ok {
+		accrual, err := l.accrueInterest(account, month, previous.Rate, previous.DayCount)
+		if err != nil {
+			return model.InterestReportLine{}, false, err
+		}
+		accrual.TransactionId = previous.TransactionId
+		status := model.InterestMatches
+		if accrual.Amount != previous.Amount {
+			status = model.InterestMismatch
+		}
+		return model.InterestReportLine{InterestAccrual: accrual, Status: status, PostedAmount: previous.Amount}, true, nil
+	}
+	if _, ok := l.interest.Rate(account.AccountType); !ok {
+		return model.InterestReportLine{}, false, nil
+	}
+	accrual, err := l.accrueInterest(account, month, l.interest.Rates[account.AccountType], l.interest.DayCount)
+	if err != nil {
+		return model.InterestReportLine{}, false, err
+	}
+	if !post {
+		return model.InterestReportLine{InterestAccrual: accrual, Status: model.InterestUnposted}, true, nil
+	}
+	accrual.TransactionId, err = l.DsSvc.PostInterest(accrual)
+	if err != nil {
+		return model.InterestReportLine{}, false, err
+	}
+	return model.InterestReportLine{InterestAccrual: accrual, Status: model.InterestPosted, PostedAmount: accrual.Amount}, true, nil
+}
+
+// accrueInterest computes the interest of the account for the calendar month starting at month from its ledger.
+func (l accountManagmentSvcLogic) accrueInterest(account model.Account, month time.Time, rate string, dayCount string) (model.InterestAccrual, error) {
+	yearly, ok := new(big.Rat).SetString(rate)
+	if !ok {
+		return model.InterestAccrual{}, fmt.Errorf("invalid interest rate %q", rate)
+	}
+	end := month.AddDate(0, 1, 0)
+	opening, err := l.DsSvc.GetBalance(account.AccountNumber, month)
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+	transactions, err := l.DsSvc.GetTransactions(model.TransactionFilter{AccountNumber: account.AccountNumber, From: month, To: end.Add(-time.Nanosecond)})
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+	amount, err := interest.Accrue(opening, transactions, month, end, yearly, dayCount)
+	if err != nil {
+		return model.InterestAccrual{}, err
+	}
+	return model.InterestAccrual{
+		AccountNumber: account.AccountNumber,
+		Period:        month.Format("2006-01"),
+		Currency:      account.Currency,
+		Rate:          rate,
+		DayCount:      dayCount,
+		Amount:        amount,
+	}, nil
+}
+
+// interestAccounts returns the accounts of every type with an interest rate, ordered by type.
+func (l accountManagmentSvcLogic) interestAccounts() ([]model.Account, error) {
+	var types []string
+	for accountType := range l.interest.Rates {
+		if _, ok := l.interest.Rate(accountType); ok {
+			types = append(types, accountType)
+		}
+	}
+	sort.Strings(types)
+	var accounts []model.Account
+	for _, accountType := range types {
+		acc, err := l.DsSvc.Get(map[string]interface{}{"account_type": accountType})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, acc...)
+	}
+	return accounts, nil
+}
+
+func (l accountManagmentSvcLogic) postedInterest(month time.Time) (map[int]model.InterestAccrual, error) {
+	accruals, err := l.DsSvc.GetInterestAccruals(month.Format("2006-01"))
+	if err != nil {
+		return nil, err
+	}
+	posted := make(map[int]model.InterestAccrual, len(accruals))
+	for _, accrual := range accruals {
+		posted[accrual.AccountNumber] = accrual
+	}
+	return posted, nil
+}
+
+// userAccount looks up the account of the user among the accounts they are a member of, their default account unless
+// an account number is given. The response is set when it could not be found, which is also the case for the accounts
+// they are not a member of, and when their role on it does not allow what the given role may do.
+func (l accountManagmentSvcLogic) userAccount(id string, accountNumber int, role string) (model.Account, *respModel.Response) {
+	if resp := l.checkAccountNumbers(accountNumber); resp != nil {
+		return model.Account{}, resp
+	}
+	acc, err := l.DsSvc.GetMemberAccounts(id, accountNumber)
+	if err != nil {
+		log.Error(err)
+		return model.Account{}, &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrFetchingUser),
+			Data:    nil,
+		}
+	}
+	if len(acc) == 0 {
+		return model.Account{}, &respModel.Response{
+			Status:  http.StatusBadRequest,
+			Message: codes.GetErr(codes.AccNotFound),
+			Data:    nil,
+		}
+	}
+	// users without a default account fall back to their oldest one
+	account := acc[0]
+	for _, a := range acc {
+		if a.Default {
+			account = a
+			break
+		}
+	}
+	if !model.RoleAllows(account.Role, role) {
+		log.Error(fmt.Errorf("user %s is %s of account %d, %s required", id, account.Role, account.AccountNumber, role))
+		return model.Account{}, &respModel.Response{
+			Status:  http.StatusForbidden,
+			Message: codes.GetErr(codes.ErrRoleNotAllowed),
+			Data:    nil,
+		}
+	}
+	return account.Account, nil
+}
+
+// spendLimits returns the limits the debits of the account are checked against, the limits of its tier unless an
+// administrator overrode them for the account.
+func (l accountManagmentSvcLogic) spendLimits(accountNumber int) (model.SpendLimits, error) {
+	var limits model.SpendLimits
+	if len(l.limits.Tiers) > 0 {
+		account, err := l.account(accountNumber)
+		if err != nil {
+			return limits, err
+		}
+		limits = l.limits.Tier(account.AccountType)
+	}
+	override, err := l.DsSvc.GetSpendLimits(accountNumber)
+	if err != nil {
+		return limits, err
+	}
+	if override != nil {
+		limits = override.Apply(limits)
+	}
+	return limits, nil
+}
+
+func (l accountManagmentSvcLogic) accountCurrency(accountNumber int) (string, error) {
+	acc, err := l.account(accountNumber)
+	return acc.Currency, err
+}
+
+func (l accountManagmentSvcLogic) account(accountNumber int) (model.Account, error) {
+	acc, err := l.DsSvc.Get(map[string]interface{}{"account_number": accountNumber})
+	if err != nil {
+		return model.Account{}, err
+	}
+	if len(acc) == 0 {
+		return model.Account{}, datasource.ErrAccountNotFound
+	}
+	return acc[0], nil
+}
+
+// convert expresses the transaction in the account currency, keeping the amount it was sent with when a conversion was needed.
+func (l accountManagmentSvcLogic) convert(transaction model.Transaction, from string, to string) (model.Transaction, error) {
+	transaction.Currency = to
+	if from == to {
+		return transaction, nil
+	}
+	amount, ok := l.currency.Convert(transaction.Amount, from, to)
+	if !ok {
+		return transaction, fmt.Errorf("%w from %s to %s", errUnsupportedCurrency, from, to)
+	}
+	if amount <= 0 {
+		return transaction, fmt.Errorf("%w: %s %s is worth nothing in %s", errInvalidAmount, transaction.Amount, from, to)
+	}
+	transaction.OriginalAmount, transaction.OriginalCurrency = transaction.Amount, from
+	transaction.Amount = amount
+	return transaction, nil
+}
+
+// transactionErrResponse maps the errors of posting to the ledger, anything unexpected is reported with the fallback code.
+func transactionErrResponse(err error, fallback string) *respModel.Response {
+	status, message := http.StatusInternalServerError, fallback
+	switch {
+	case errors.Is(err, datasource.ErrAccountNotFound):
+		status, message = http.StatusBadRequest, codes.GetErr(codes.AccNotFound)
+	case errors.Is(err, datasource.ErrCurrencyMismatch):
+		status, message = http.StatusBadRequest, codes.GetErr(codes.ErrCurrencyMismatch)
+	case errors.Is(err, datasource.ErrInsufficientFunds):
+		status, message = http.StatusUnprocessableEntity, codes.GetErr(codes.ErrInsufficientFunds)
+	case errors.Is(err, errUnsupportedCurrency):
+		status, message = http.StatusBadRequest, codes.GetErr(codes.ErrUnsupportedCurrency)
+	case errors.Is(err, errInvalidAmount):
+		status, message = http.StatusBadRequest, codes.GetErr(codes.ErrInvalidAmount)
+	case errors.Is(err, datasource.ErrDebitLimit):
+		status, message = http.StatusUnprocessableEntity, codes.GetErr(codes.ErrDebitLimit)
+	case errors.Is(err, datasource.ErrDailyDebitLimit):
+		status, message = http.StatusUnprocessableEntity, codes.GetErr(codes.ErrDailyDebitLimit)
+	case errors.Is(err, datasource.ErrDailyDebitCount):
+		status, message = http.StatusUnprocessableEntity, codes.GetErr(codes.ErrDailyDebitCount)
+	case errors.Is(err, datasource.ErrAccountNotActive):
+		status, message = http.StatusConflict, codes.GetErr(codes.ErrAccountNotActive)
+	case errors.Is(err, datasource.ErrAccountFrozen):
+		status, message = http.StatusConflict, codes.GetErr(codes.ErrAccountFrozen)
+	case errors.Is(err, datasource.ErrAccountClosed):
+		status, message = http.StatusConflict, codes.GetErr(codes.ErrAccountClosed)
+	}
+	return &respModel.Response{
+		Status:  status,
+		Message: message,
+		Data:    nil,
+	}
+}
+
+// reversalErrResponse maps the errors of reversing a transaction, the remaining ones are mapped like any other posting.
+func reversalErrResponse(err error) *respModel.Response {
+	resp := transactionErrResponse(err, codes.GetErr(codes.ErrReversingTransaction))
+	switch {
+	case errors.Is(err, datasource.ErrTransactionNotFound):
+		resp.Status, resp.Message = http.StatusNotFound, codes.GetErr(codes.TransactionNotFound)
+	case errors.Is(err, datasource.ErrAlreadyReversed):
+		resp.Status, resp.Message = http.StatusConflict, codes.GetErr(codes.ErrAlreadyReversed)
+	case errors.Is(err, datasource.ErrReversalExceedsAmount):
+		resp.Status, resp.Message = http.StatusBadRequest, codes.GetErr(codes.ErrReversalExceedsAmount)
+	case errors.Is(err, datasource.ErrReversalOfReversal):
+		resp.Status, resp.Message = http.StatusBadRequest, codes.GetErr(codes.ErrReversalOfReversal)
+	}
+	return resp
+}
+
+// holdErrResponse maps the errors of settling a hold, the remaining ones are mapped like any other posting.
+func holdErrResponse(err error, fallback string) *respModel.Response {
+	resp := transactionErrResponse(err, fallback)
+	switch {
+	case errors.Is(err, datasource.ErrHoldNotFound):
+		resp.Status, resp.Message = http.StatusNotFound, codes.GetErr(codes.HoldNotFound)
+	case errors.Is(err, datasource.ErrHoldNotPending):
+		resp.Status, resp.Message = http.StatusConflict, codes.GetErr(codes.ErrHoldNotPending)
+	case errors.Is(err, datasource.ErrHoldExpired):
+		resp.Status, resp.Message = http.StatusConflict, codes.GetErr(codes.ErrHoldExpired)
+	case errors.Is(err, datasource.ErrCaptureExceedsHold):
+		resp.Status, resp.Message = http.StatusBadRequest, codes.GetErr(codes.ErrCaptureExceedsHold)
+	}
+	return resp
+}
+
+// crossedThreshold returns the highest threshold of the limit reached when the spends went from before to after,
+// it returns 0 when none was reached.
+func crossedThreshold(limit model.Money, before model.Money, after model.Money) int {
+	for _, threshold := range budgetThresholds {
+		level := limit * model.Money(threshold) / 100
+		if before < level && after >= level {
+			return threshold
+		}
+	}
+	return 0
+}
+
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// startOfDay returns midnight UTC of the day of t, the daily spend limits count the debits since then.
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Reconcile compares the income and spends of every account, or of the given account when accountNumber is not 0,
+// with the totals of its ledger and sets them to the ledger totals when repair is set. Every mismatch is logged as
+// json, so the scheduled runs leave a report behind as well. The journal is checked on every run, its trial balance
+// and its customer legs against the balances of the accounts.
+func (l accountManagmentSvcLogic) Reconcile(accountNumber int, repair bool) *respModel.Response {
+	if resp := l.checkAccountNumbers(accountNumber); resp != nil {
+		return resp
+	}
+	report := model.ReconciliationReport{CheckedOn: time.Now().UTC(), Mismatches: []model.ReconciliationLine{}, JournalMismatches: []model.JournalLine{}}
+	lines, err := l.DsSvc.GetLedgerMismatches(accountNumber)
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrReconciliation),
+			Data:    nil,
+		}
+	}
+	for _, line := range lines {
+		if repair {
+			repaired, err := l.DsSvc.RepairTotals(line.AccountNumber)
+			switch {
+			case errors.Is(err, datasource.ErrNoLedgerEntries):
+				// the totals of the account were never posted, setting them to the ledger would wipe its balance
+				log.Warn(fmt.Sprintf("account %d not repaired: %s", line.AccountNumber, err))
+			case err != nil:
+				log.Error(err)
+				return &respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrReconciliation),
+					Data:    nil,
+				}
+			default:
+				line = repaired
+			}
+		}
+		b, _ := json.Marshal(line)
+		log.Warn(fmt.Sprintf("account totals out of line with the ledger: %s", b))
+		report.Mismatches = append(report.Mismatches, line)
+	}
+	report.TrialBalance, err = l.DsSvc.GetTrialBalance()
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrReconciliation),
+			Data:    nil,
+		}
+	}
+	net := make(map[string]model.Money)
+	for _, line := range report.TrialBalance {
+		net[line.Currency] += line.Debits - line.Credits
+	}
+	report.Balanced = true
+	for currency, amount := range net {
+		if amount != 0 {
+			report.Balanced = false
+			log.Error(fmt.Sprintf("journal out of balance by %s %s", amount, currency))
+		}
+	}
+	journal, err := l.DsSvc.GetJournalMismatches()
+	if err != nil {
+		log.Error(err)
+		return &respModel.Response{
+			Status:  http.StatusInternalServerError,
+			Message: codes.GetErr(codes.ErrReconciliation),
+			Data:    nil,
+		}
+	}
+	for _, line := range journal {
+		report.Balanced = false
+		log.Error(fmt.Sprintf("journal out of line with the accounts by %s %s", line.Journal-line.Accounts, line.Currency))
+		report.JournalMismatches = append(report.JournalMismatches, line)
+	}
+	return &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+		Data:    report,
+	}
+}
+
+// ImportTransactions posts the valid rows of a bulk import in batches, every batch in a single database transaction.
+// A batch that fails is posted again row by row, so each row reports its own outcome. Imports are corrections, no
+// fees are charged on them and the spend limits do not apply. A dry run only validates the rows.
+func (l accountManagmentSvcLogic) ImportTransactions(rows []model.ImportRow, dryRun bool) *respModel.Response {
+	report := model.ImportReport{DryRun: dryRun, Rows: len(rows), Results: make([]model.ImportRowResult, 0, len(rows))}
+	var batch []int
+	for _, row := range rows {
+		result := model.ImportRowResult{
+			Line:            row.Line,
+			AccountNumber:   row.Transaction.AccountNumber,
+			Amount:          row.Transaction.Amount,
+			TransactionType: row.Transaction.TransactionType,
+			Reference:       row.Transaction.Reference,
+			Status:          model.ImportValid,
+		}
+		if row.Err == "" && !l.numbers.Valid(row.Transaction.AccountNumber) {
+			row.Err = codes.GetErr(codes.ErrInvalidAccountNumber)
+		}
+		if row.Err != "" {
+			result.Status, result.Error = model.ImportInvalid, row.Err
+			report.Failed++
+		} else {
+			report.Valid++
+			batch = append(batch, len(report.Results))
+		}
+		report.Results = append(report.Results, result)
+		if !dryRun && len(batch) == importBatchSize {
+			l.importBatch(&report, batch)
+			batch = batch[:0]
+		}
+	}
+	if !dryRun && len(batch) > 0 {
+		l.importBatch(&report, batch)
+	}
+	return &respModel.Response{
+		Status:  http.StatusOK,
+		Message: "SUCCESS",
+		Data:    report,
+	}
+}
+
+// importBatch posts the rows of the report at the given indexes and records their outcome.
+func (l accountManagmentSvcLogic) importBatch(report *model.ImportReport, batch []int) {
+	transactions := make([]model.Transaction, 0, len(batch))
+	for _, i := range batch {
+		result := report.Results[i]
+		transactions = append(transactions, model.Transaction{
+			AccountNumber:   result.AccountNumber,
+			Amount:          result.Amount,
+			TransactionType: result.TransactionType,
+			Reference:       result.Reference,
+		})
+	}
+	ids, err := l.DsSvc.InsertTransactions(transactions...)
+	if err == nil {
+		for n, i := range batch {
+			report.Results[i].Status, report.Results[i].TransactionId = model.ImportApplied, ids[n]
+		}
+		report.Applied += len(batch)
+		return
+	}
+	log.Error(err)
+	for n, i := range batch {
+		id, err := l.DsSvc.InsertTransaction(transactions[n])
+		if err != nil {
+			log.Error(err)
+			report.Results[i].Status = model.ImportFailed
+			report.Results[i].Error = transactionErrResponse(err, codes.GetErr(codes.ErrUpdatingTransaction)).Message
+			report.Failed++
+			continue
+		}
+		report.Results[i].Status, report.Results[i].TransactionId = model.ImportApplied, id
+		report.Applied++
 	}
 }
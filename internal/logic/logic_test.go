@@ -1,23 +1,39 @@
 package logic
 
 import (
+	"encoding/json"
 	"errors"
 	respModel "github.com/PereRohit/util/model"
 	"github.com/PereRohit/util/testutil"
 	"github.com/golang/mock/gomock"
+	"github.com/vatsal278/AccountManagmentSvc/internal/accountnumber"
 	"github.com/vatsal278/AccountManagmentSvc/internal/codes"
 	"github.com/vatsal278/AccountManagmentSvc/internal/config"
 	"github.com/vatsal278/AccountManagmentSvc/internal/repo/authentication"
+	"github.com/vatsal278/AccountManagmentSvc/internal/statement"
 	"github.com/vatsal278/msgbroker/pkg/sdk"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/vatsal278/AccountManagmentSvc/internal/model"
 	"github.com/vatsal278/AccountManagmentSvc/internal/repo/datasource"
 	"github.com/vatsal278/AccountManagmentSvc/pkg/mock"
 )
 
+var testCurrency = config.CurrencyCfg{Default: "USD", Rates: map[string]map[string]string{"EUR": {"USD": "1.10"}}}
+
+// owned returns the accounts as seen by their owner.
+func owned(accounts ...model.Account) []model.MemberAccount {
+	members := make([]model.MemberAccount, 0, len(accounts))
+	for _, acc := range accounts {
+		members = append(members, model.MemberAccount{Account: acc, Role: model.RoleOwner})
+	}
+	return members
+}
+
 func TestAccountManagmentSvcLogic_HealthCheck(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -42,7 +58,8 @@ func TestAccountManagmentSvcLogic_HealthCheck(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rec := NewAccountManagmentSvcLogic(tt.setup())
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
 
 			got := rec.HealthCheck()
 
@@ -67,12 +84,15 @@ func TestAccountManagmentSvcLogic_CreateAccount(t *testing.T) {
 		{
 			name: "Success",
 			credentials: model.NewAccount{
-				UserId: "123",
+				UserId:      "123",
+				Currency:    "EUR",
+				AccountType: "savings",
 			},
 			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
 				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{}, nil)
-				mockDs.EXPECT().Insert(gomock.Any()).Times(1).Return(nil)
+				mockDs.EXPECT().Insert(model.Account{Id: "123", Currency: "EUR", AccountType: "savings", Status: model.AccountPending, Default: true}).Times(1).Return(1, nil)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountActive).Times(1).Return(model.AccountPending, nil)
 				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
 			},
 			want: func(resp *respModel.Response) {
@@ -86,6 +106,30 @@ func TestAccountManagmentSvcLogic_CreateAccount(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Success :: another type of account is not the default one",
+			credentials: model.NewAccount{
+				UserId:      "123",
+				AccountType: "wallet",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, AccountType: "current", Default: true}}, nil)
+				mockDs.EXPECT().Insert(model.Account{Id: "123", Currency: "USD", AccountType: "wallet", Status: model.AccountPending}).Times(1).Return(2, nil)
+				mockDs.EXPECT().SetAccountStatus(2, model.AccountActive).Times(1).Return(model.AccountPending, nil)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusCreated,
+					Message: "SUCCESS",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
 		{
 			name: "Success :: Push msg failure",
 			credentials: model.NewAccount{
@@ -94,7 +138,8 @@ func TestAccountManagmentSvcLogic_CreateAccount(t *testing.T) {
 			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
 				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{}, nil)
-				mockDs.EXPECT().Insert(model.Account{Id: "123"}).Times(1).Return(nil)
+				mockDs.EXPECT().Insert(model.Account{Id: "123", Currency: "USD", AccountType: "current", Status: model.AccountPending, Default: true}).Times(1).Return(1, nil)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountActive).Times(1).Return(model.AccountPending, nil)
 				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("")}, config.CookieStruct{}
 			},
 			want: func(resp *respModel.Response) {
@@ -108,6 +153,29 @@ func TestAccountManagmentSvcLogic_CreateAccount(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Success :: activation failure leaves the account pending",
+			credentials: model.NewAccount{
+				UserId: "123",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{}, nil)
+				mockDs.EXPECT().Insert(model.Account{Id: "123", Currency: "USD", AccountType: "current", Status: model.AccountPending, Default: true}).Times(1).Return(1, nil)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountActive).Times(1).Return("", errors.New("DB ERR"))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusCreated,
+					Message: "SUCCESS",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
 		{
 			name: "Failure::Get from db err",
 			credentials: model.NewAccount{
@@ -137,7 +205,7 @@ func TestAccountManagmentSvcLogic_CreateAccount(t *testing.T) {
 			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
 				var users []model.Account
-				users = append(users, model.Account{Id: "123"})
+				users = append(users, model.Account{Id: "123", AccountNumber: 1, AccountType: "current"})
 				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(users, nil)
 				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9091")}, config.CookieStruct{}
 			},
@@ -160,7 +228,7 @@ func TestAccountManagmentSvcLogic_CreateAccount(t *testing.T) {
 			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
 				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{}, nil)
-				mockDs.EXPECT().Insert(model.Account{Id: "123"}).Times(1).Return(errors.New(""))
+				mockDs.EXPECT().Insert(model.Account{Id: "123", Currency: "USD", AccountType: "current", Status: model.AccountPending, Default: true}).Times(1).Return(0, errors.New(""))
 				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9091")}, config.CookieStruct{}
 			},
 			want: func(resp *respModel.Response) {
@@ -177,7 +245,8 @@ func TestAccountManagmentSvcLogic_CreateAccount(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rec := NewAccountManagmentSvcLogic(tt.setup())
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
 
 			got := rec.CreateAccount(tt.credentials)
 
@@ -185,311 +254,4219 @@ func TestAccountManagmentSvcLogic_CreateAccount(t *testing.T) {
 		})
 	}
 }
-func TestAccountManagmentSvcLogic_AccountSummary(t *testing.T) {
+func TestAccountManagmentSvcLogic_OpenAccount(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
 	tests := []struct {
-		name        string
-		credentials string
-		setup       func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
-		want        func(*respModel.Response)
+		name    string
+		account model.OpenAccount
+		setup   func() datasource.DataSourceI
+		want    *respModel.Response
 	}{
 		{
-			name:        "Success :: AccDetails",
-			credentials: "123",
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+			name:    "Success",
+			account: model.OpenAccount{AccountType: "savings", Currency: "EUR"},
+			setup: func() datasource.DataSourceI {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
-				var acc []model.Account
-				acc = append(acc, model.Account{Id: "123", AccountNumber: 1})
-				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(acc, nil)
-				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
-			},
-			want: func(resp *respModel.Response) {
-				var users = model.AccountSummary{AccountNumber: 1}
-				temp := respModel.Response{
-					Status:  http.StatusOK,
-					Message: "SUCCESS",
-					Data:    users,
-				}
-				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", &temp, resp)
-				}
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, AccountType: "current", Default: true}}, nil)
+				mockDs.EXPECT().Insert(model.Account{Id: "123", Currency: "EUR", AccountType: "savings", Status: model.AccountPending}).Times(1).Return(2, nil)
+				mockDs.EXPECT().SetAccountStatus(2, model.AccountActive).Times(1).Return(model.AccountPending, nil)
+				return mockDs
 			},
+			want: &respModel.Response{Status: http.StatusCreated, Message: "SUCCESS", Data: model.AccountSummary{AccountNumber: 2, Currency: "EUR", AccountType: "savings", Status: model.AccountActive}},
 		},
 		{
-			name:        "Failure :: AccDetails :: db err",
-			credentials: "123",
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+			name:    "Success :: activation failure leaves the account pending",
+			account: model.OpenAccount{AccountType: "wallet"},
+			setup: func() datasource.DataSourceI {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
-				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(nil, errors.New(""))
-				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
-			},
-			want: func(resp *respModel.Response) {
-				temp := respModel.Response{
-					Status:  http.StatusInternalServerError,
-					Message: codes.GetErr(codes.ErrFetchingUser),
-					Data:    nil,
-				}
-				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", &temp, resp)
-				}
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(nil, nil)
+				mockDs.EXPECT().Insert(model.Account{Id: "123", Currency: "USD", AccountType: "wallet", Status: model.AccountPending, Default: true}).Times(1).Return(2, nil)
+				mockDs.EXPECT().SetAccountStatus(2, model.AccountActive).Times(1).Return("", errors.New("DB ERR"))
+				return mockDs
 			},
+			want: &respModel.Response{Status: http.StatusCreated, Message: "SUCCESS", Data: model.AccountSummary{AccountNumber: 2, Currency: "USD", AccountType: "wallet", Status: model.AccountPending, Default: true}},
 		},
 		{
-			name:        "Failure :: AccDetails :: db err",
-			credentials: "123",
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+			name:    "Failure :: account of the type exists",
+			account: model.OpenAccount{AccountType: "savings"},
+			setup: func() datasource.DataSourceI {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
-				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(nil, nil)
-				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, AccountType: "savings"}}, nil)
+				return mockDs
 			},
-			want: func(resp *respModel.Response) {
-				temp := respModel.Response{
-					Status:  http.StatusBadRequest,
-					Message: codes.GetErr(codes.AccNotFound),
-					Data:    nil,
-				}
-				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", &temp, resp)
-				}
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrAccExists), Data: nil},
+		},
+		{
+			name:    "Failure :: db err",
+			account: model.OpenAccount{AccountType: "savings"},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(nil, errors.New("DB ERR"))
+				return mockDs
 			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrCreatingAccount), Data: nil},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rec := NewAccountManagmentSvcLogic(tt.setup())
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
 
-			got := rec.AccountDetails(tt.credentials)
+			got := rec.OpenAccount("123", tt.account)
 
-			tt.want(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
 		})
 	}
 }
-func TestAccountManagmentSvcLogic_UpdateServices(t *testing.T) {
+func TestAccountManagmentSvcLogic_AccountNumbers(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
+	var allocated int
 	tests := []struct {
-		name        string
-		credentials model.UpdateServices
-		setup       func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
-		want        func(*respModel.Response)
+		name  string
+		setup func() datasource.DataSourceI
+		run   func(l AccountManagmentSvcLogicIer) *respModel.Response
+		want  func() *respModel.Response
 	}{
 		{
-			name: "Success",
-			credentials: model.UpdateServices{
-				AccountNumber: 1,
-				ServiceId:     "10",
-				UpdateType:    "add",
+			name: "Success :: taken number is drawn again",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(nil, nil)
+				gomock.InOrder(
+					mockDs.EXPECT().Insert(gomock.Any()).Times(1).Return(0, datasource.ErrAccountNumberTaken),
+					mockDs.EXPECT().Insert(gomock.Any()).Times(1).DoAndReturn(func(acc model.Account) (int, error) {
+						if acc.AccountNumber < 100000000 || acc.AccountNumber > 999999999 || !accountnumber.Valid(acc.AccountNumber) {
+							t.Errorf("Want: valid account number of 9 digits, Got: %d", acc.AccountNumber)
+						}
+						allocated = acc.AccountNumber
+						return acc.AccountNumber, nil
+					}),
+				)
+				mockDs.EXPECT().SetAccountStatus(gomock.Any(), model.AccountActive).Times(1).Return(model.AccountPending, nil)
+				return mockDs
 			},
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+			run: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.OpenAccount("123", model.OpenAccount{AccountType: "current"})
+			},
+			want: func() *respModel.Response {
+				return &respModel.Response{Status: http.StatusCreated, Message: "SUCCESS", Data: model.AccountSummary{AccountNumber: allocated, Currency: "USD", AccountType: "current", Status: model.AccountActive, Default: true}}
+			},
+		},
+		{
+			name: "Failure :: every number drawn is taken",
+			setup: func() datasource.DataSourceI {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				mockDs.EXPECT().Update(map[string]interface{}{"active_services": model.ColumnUpdate{UpdateSet: "JSON_INSERT(active_services, '$.\"10\"', JSON_OBJECT())"}, "inactive_services": model.ColumnUpdate{UpdateSet: "JSON_REMOVE(inactive_services, '$.\"10\"')"}}, map[string]interface{}{"account_number": 1, "user_id": "1234"}).Times(1).Return(nil)
-				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(nil, nil)
+				mockDs.EXPECT().Insert(gomock.Any()).Times(maxAllocationAttempts).Return(0, datasource.ErrAccountNumberTaken)
+				return mockDs
 			},
-			want: func(resp *respModel.Response) {
-				temp := respModel.Response{
-					Status:  http.StatusAccepted,
-					Message: "SUCCESS",
-					Data:    nil,
-				}
-				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", temp, resp)
-				}
+			run: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.OpenAccount("123", model.OpenAccount{AccountType: "current"})
+			},
+			want: func() *respModel.Response {
+				return &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrCreatingAccount), Data: nil}
 			},
 		},
 		{
-			name: "Success :: remove",
-			credentials: model.UpdateServices{
-				AccountNumber: 1,
-				ServiceId:     "10",
-				UpdateType:    "remove",
+			name: "Failure :: mistyped account number",
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
 			},
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
-				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				mockDs.EXPECT().Update(map[string]interface{}{"active_services": model.ColumnUpdate{UpdateSet: "JSON_REMOVE(active_services, '$.\"10\"')"}, "inactive_services": model.ColumnUpdate{UpdateSet: "JSON_INSERT(inactive_services, '$.\"10\"', JSON_OBJECT())"}}, map[string]interface{}{"account_number": 1, "user_id": "1234"}).Times(1).Return(nil)
-				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			run: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.SetDefaultAccount("123", model.DefaultAccount{AccountNumber: 123456789})
 			},
-			want: func(resp *respModel.Response) {
-				temp := respModel.Response{
-					Status:  http.StatusAccepted,
-					Message: "SUCCESS",
-					Data:    nil,
-				}
-				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", temp, resp)
-				}
+			want: func() *respModel.Response {
+				return &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrInvalidAccountNumber), Data: nil}
 			},
 		},
 		{
-			name: "Failure::DB ERR",
-			credentials: model.UpdateServices{
-				AccountNumber: 1,
-				ServiceId:     "10",
-				UpdateType:    "add",
+			name: "Failure :: transfer to a number with swapped digits",
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
 			},
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
-				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				mockDs.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(errors.New("DB ERR"))
-				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			run: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Transfer(model.Transfer{FromAccount: 123456782, ToAccount: 123456728, Amount: 100})
 			},
-			want: func(resp *respModel.Response) {
-				temp := respModel.Response{
-					Status:  http.StatusInternalServerError,
-					Message: codes.GetErr(codes.ErrUpdatingServices),
-					Data:    nil,
-				}
-				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", temp, resp)
-				}
+			want: func() *respModel.Response {
+				return &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrInvalidAccountNumber), Data: nil}
 			},
 		},
 		{
-			name: "Failure::switch default case",
-			credentials: model.UpdateServices{
-				AccountNumber: 1,
-				ServiceId:     "10",
-				UpdateType:    "",
+			name: "Failure :: mistyped account number in the query",
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
 			},
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+			run: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Budgets("123", 123456789)
+			},
+			want: func() *respModel.Response {
+				return &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrInvalidAccountNumber), Data: nil}
+			},
+		},
+		{
+			name: "Success :: sequential account number of an earlier release",
+			setup: func() datasource.DataSourceI {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+				mockDs.EXPECT().SetDefaultAccount("123", 12).Times(1).Return(nil)
+				return mockDs
 			},
-			want: func(resp *respModel.Response) {
-				temp := respModel.Response{
-					Status:  http.StatusBadRequest,
-					Message: codes.GetErr(codes.ErrUpdatingServices),
-					Data:    nil,
-				}
-				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", temp, resp)
-				}
+			run: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.SetDefaultAccount("123", model.DefaultAccount{AccountNumber: 12})
+			},
+			want: func() *respModel.Response {
+				return &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: nil}
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rec := NewAccountManagmentSvcLogic(tt.setup())
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency, AccountNumbers: config.AccountNumberCfg{Length: 9}})
 
-			got := rec.UpdateServices("1234", tt.credentials)
+			got := tt.run(rec)
 
-			tt.want(got)
+			if want := tt.want(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Want: %v, Got: %v", want, got)
+			}
 		})
 	}
 }
-func TestAccountManagmentSvcLogic_UpdateTransaction(t *testing.T) {
+
+func TestAccountManagmentSvcLogic_SetDefaultAccount(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
 	tests := []struct {
-		name        string
-		credentials model.UpdateTransaction
-		setup       func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
-		want        func(*respModel.Response)
+		name string
+		err  error
+		want *respModel.Response
 	}{
 		{
-			name: "Success::DEBIT",
-			credentials: model.UpdateTransaction{
-				AccountNumber:   1,
-				Amount:          1000.00,
-				TransactionType: "debit",
+			name: "Success",
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: nil},
+		},
+		{
+			name: "Failure :: account of another user",
+			err:  datasource.ErrAccountNotFound,
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.AccNotFound), Data: nil},
+		},
+		{
+			name: "Failure :: closed account",
+			err:  datasource.ErrAccountClosed,
+			want: &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrAccountClosed), Data: nil},
+		},
+		{
+			name: "Failure :: db err",
+			err:  errors.New("DB ERR"),
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrUpdatingDefault), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDs := mock.NewMockDataSourceI(mockCtrl)
+			mockDs.EXPECT().SetDefaultAccount("123", 2).Times(1).Return(tt.err)
+			rec := NewAccountManagmentSvcLogic(mockDs, nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			got := rec.SetDefaultAccount("123", model.DefaultAccount{AccountNumber: 2})
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_Members(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	now := time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC)
+	account := model.Account{Id: "123", AccountNumber: 1, Status: model.AccountActive}
+	shared := func(role string) []model.MemberAccount {
+		return []model.MemberAccount{{Account: account, Role: role}}
+	}
+	tests := []struct {
+		name  string
+		call  func(l AccountManagmentSvcLogicIer) *respModel.Response
+		setup func() datasource.DataSourceI
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: viewer lists the members",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Members("456", 1)
 			},
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+			setup: func() datasource.DataSourceI {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				mockDs.EXPECT().Update(map[string]interface{}{"spends": model.ColumnUpdate{UpdateSet: "spends + 1000.00"}}, map[string]interface{}{"account_number": 1}).Times(1).Return(nil)
-				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
-			},
-			want: func(resp *respModel.Response) {
-				temp := respModel.Response{
-					Status:  http.StatusAccepted,
-					Message: "SUCCESS",
-					Data:    nil,
-				}
-				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", temp, resp)
-				}
+				mockDs.EXPECT().GetMemberAccounts("456", 1).Times(1).Return(shared(model.RoleViewer), nil)
+				mockDs.EXPECT().GetMembers(1).Times(1).Return([]model.Member{{AccountNumber: 1, UserId: "123", Role: model.RoleOwner, CreatedOn: now}, {AccountNumber: 1, UserId: "456", Role: model.RoleViewer, CreatedOn: now}}, nil)
+				return mockDs
 			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: []model.Member{{AccountNumber: 1, UserId: "123", Role: model.RoleOwner, CreatedOn: now}, {AccountNumber: 1, UserId: "456", Role: model.RoleViewer, CreatedOn: now}}},
 		},
 		{
-			name: "Success :: CREDIT",
-			credentials: model.UpdateTransaction{
-				AccountNumber:   1,
-				Amount:          1000.00,
-				TransactionType: "credit",
+			name: "Failure :: members :: db err",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Members("123", 0)
 			},
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+			setup: func() datasource.DataSourceI {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				mockDs.EXPECT().Update(map[string]interface{}{"income": model.ColumnUpdate{UpdateSet: "income + 1000.00"}}, map[string]interface{}{"account_number": 1}).Times(1).Return(nil)
-				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
-			},
-			want: func(resp *respModel.Response) {
-				temp := respModel.Response{
-					Status:  http.StatusAccepted,
-					Message: "SUCCESS",
-					Data:    nil,
-				}
-				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", temp, resp)
-				}
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(shared(model.RoleOwner), nil)
+				mockDs.EXPECT().GetMembers(1).Times(1).Return(nil, errors.New("DB ERR"))
+				return mockDs
 			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrFetchingMembers), Data: nil},
 		},
 		{
-			name: "Failure::DB ERR",
-			credentials: model.UpdateTransaction{
-				AccountNumber:   1,
-				Amount:          1000.00,
-				TransactionType: "debit",
+			name: "Success :: owner invites a co-owner",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.InviteMember("123", 1, model.InviteMember{UserId: "456", Role: model.RoleCoOwner})
 			},
-			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+			setup: func() datasource.DataSourceI {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				mockDs.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(errors.New("DB ERR"))
-				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(shared(model.RoleOwner), nil)
+				mockDs.EXPECT().InsertMember(model.Member{AccountNumber: 1, UserId: "456", Role: model.RoleCoOwner}).Times(1).Return(nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusCreated, Message: "SUCCESS", Data: model.Member{AccountNumber: 1, UserId: "456", Role: model.RoleCoOwner}},
+		},
+		{
+			name: "Failure :: co-owner invites a member",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.InviteMember("456", 1, model.InviteMember{UserId: "789", Role: model.RoleViewer})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("456", 1).Times(1).Return(shared(model.RoleCoOwner), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusForbidden, Message: codes.GetErr(codes.ErrRoleNotAllowed), Data: nil},
+		},
+		{
+			name: "Failure :: invited user is a member already",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.InviteMember("123", 1, model.InviteMember{UserId: "456", Role: model.RoleViewer})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(shared(model.RoleOwner), nil)
+				mockDs.EXPECT().InsertMember(gomock.Any()).Times(1).Return(datasource.ErrMemberExists)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrMemberExists), Data: nil},
+		},
+		{
+			name: "Failure :: closed account is not shared",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.InviteMember("123", 1, model.InviteMember{UserId: "456", Role: model.RoleViewer})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				closed := account
+				closed.Status = model.AccountClosed
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(owned(closed), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrAccountClosed), Data: nil},
+		},
+		{
+			name: "Success :: owner removes a member",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.RemoveMember("123", 1, "456")
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(shared(model.RoleOwner), nil)
+				mockDs.EXPECT().DeleteMember(1, "456").Times(1).Return(nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: nil},
+		},
+		{
+			name: "Failure :: removed user is not a member",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.RemoveMember("123", 1, "123")
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(shared(model.RoleOwner), nil)
+				mockDs.EXPECT().DeleteMember(1, "123").Times(1).Return(datasource.ErrMemberNotFound)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusNotFound, Message: codes.GetErr(codes.ErrMemberNotFound), Data: nil},
+		},
+		{
+			name: "Failure :: viewer removes a member",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.RemoveMember("456", 1, "789")
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("456", 1).Times(1).Return(shared(model.RoleViewer), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusForbidden, Message: codes.GetErr(codes.ErrRoleNotAllowed), Data: nil},
+		},
+		{
+			name: "Failure :: viewer updates the services",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateServices("456", model.UpdateServices{AccountNumber: 1, ServiceId: "10", UpdateType: "add"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("456", 1).Times(1).Return(shared(model.RoleViewer), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusForbidden, Message: codes.GetErr(codes.ErrRoleNotAllowed), Data: nil},
+		},
+		{
+			name: "Success :: co-owner updates the services",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateServices("456", model.UpdateServices{AccountNumber: 1, ServiceId: "10", UpdateType: "add"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("456", 1).Times(1).Return(shared(model.RoleCoOwner), nil)
+				mockDs.EXPECT().UpdateServices(1, gomock.Any()).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: nil},
+		},
+		{
+			name: "Failure :: viewer creates a budget",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CreateBudget("456", 1, model.NewBudget{Limit: 10000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("456", 1).Times(1).Return(shared(model.RoleViewer), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusForbidden, Message: codes.GetErr(codes.ErrRoleNotAllowed), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			got := tt.call(rec)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_AccountSummary(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	asOf := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	changedOn := time.Date(2022, 2, 27, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		credentials   string
+		accountNumber int
+		asOf          time.Time
+		setup         func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want          func(*respModel.Response)
+	}{
+		{
+			name:        "Success :: AccDetails",
+			credentials: "123",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
+				var acc []model.Account
+				acc = append(acc, model.Account{Id: "123", AccountNumber: 1, Income: 10000, Spends: 12500, Currency: "EUR", OverdraftLimit: 5000, Held: 1000})
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(acc...), nil)
+				mockDs.EXPECT().GetHolds(1, model.HoldPending).Times(1).Return([]model.Hold{{Id: 4, AccountNumber: 1, Amount: 1000, Currency: "EUR", Status: model.HoldPending}}, nil)
+				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				var users = model.AccountSummary{AccountNumber: 1, Income: 10000, Spends: 12500, Currency: "EUR", Balance: -2500, OverdraftLimit: 5000, AvailableBalance: 1500, Held: 1000,
+					PendingHolds: []model.Hold{{Id: 4, AccountNumber: 1, Amount: 1000, Currency: "EUR", Status: model.HoldPending}}, Role: model.RoleOwner}
+				temp := respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data:    []model.AccountSummary{users},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:        "Success :: AccDetails :: every account of the user",
+			credentials: "123",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return([]model.MemberAccount{
+					{Account: model.Account{Id: "123", AccountNumber: 1, Income: 10000, Currency: "EUR", AccountType: "current"}, Role: model.RoleOwner},
+					{Account: model.Account{Id: "123", AccountNumber: 2, Income: 500, Currency: "EUR", AccountType: "wallet", Default: true}, Role: model.RoleOwner},
+					{Account: model.Account{Id: "456", AccountNumber: 3, Income: 700, Currency: "USD", AccountType: "current"}, Role: model.RoleViewer},
+				}, nil)
+				mockDs.EXPECT().GetHolds(1, model.HoldPending).Times(1).Return(nil, nil)
+				mockDs.EXPECT().GetHolds(2, model.HoldPending).Times(1).Return(nil, nil)
+				mockDs.EXPECT().GetHolds(3, model.HoldPending).Times(1).Return(nil, nil)
+				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data: []model.AccountSummary{
+						{AccountNumber: 1, Income: 10000, Currency: "EUR", AccountType: "current", Balance: 10000, AvailableBalance: 10000, PendingHolds: []model.Hold{}, Role: model.RoleOwner},
+						{AccountNumber: 2, Income: 500, Currency: "EUR", AccountType: "wallet", Balance: 500, AvailableBalance: 500, PendingHolds: []model.Hold{}, Default: true, Role: model.RoleOwner},
+						{AccountNumber: 3, Income: 700, Currency: "USD", AccountType: "current", Balance: 700, AvailableBalance: 700, PendingHolds: []model.Hold{}, Role: model.RoleViewer},
+					},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:          "Failure :: AccDetails :: account of another user",
+			credentials:   "123",
+			accountNumber: 7,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 7).Times(1).Return(nil, nil)
+				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.AccNotFound),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:        "Failure :: AccDetails :: holds db err",
+			credentials: "123",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetHolds(1, model.HoldPending).Times(1).Return(nil, errors.New(""))
+				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
 			},
 			want: func(resp *respModel.Response) {
 				temp := respModel.Response{
 					Status:  http.StatusInternalServerError,
-					Message: codes.GetErr(codes.ErrUpdatingTransaction),
+					Message: codes.GetErr(codes.ErrFetchingHolds),
 					Data:    nil,
 				}
 				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", temp, resp)
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
 				}
 			},
 		},
 		{
-			name: "Failure::default switch case",
-			credentials: model.UpdateTransaction{
-				AccountNumber:   1,
-				Amount:          1000.00,
-				TransactionType: "",
+			name:        "Failure :: AccDetails :: db err",
+			credentials: "123",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(nil, errors.New(""))
+				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingUser),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
 			},
+		},
+		{
+			name:        "Failure :: AccDetails :: db err",
+			credentials: "123",
 			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
 				mockDs := mock.NewMockDataSourceI(mockCtrl)
-				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(nil, nil)
+				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
 			},
 			want: func(resp *respModel.Response) {
 				temp := respModel.Response{
 					Status:  http.StatusBadRequest,
-					Message: codes.GetErr(codes.ErrUpdatingTransaction),
+					Message: codes.GetErr(codes.AccNotFound),
 					Data:    nil,
 				}
 				if !reflect.DeepEqual(resp, &temp) {
-					t.Errorf("Want: %v, Got: %v", temp, resp)
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:        "Success :: AccDetails :: as of a past time",
+			credentials: "123",
+			asOf:        asOf,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1, Income: 90000, Default: true}, model.Account{Id: "123", AccountNumber: 2}), nil)
+				mockDs.EXPECT().GetAccountAsOf(1, asOf).Times(1).Return(model.Account{AccountNumber: 1, Income: 10000, Spends: 2500, Currency: "EUR", OverdraftLimit: 5000, Held: 1000, UpdatedOn: changedOn}, nil)
+				// the second account was opened later
+				mockDs.EXPECT().GetAccountAsOf(2, asOf).Times(1).Return(model.Account{}, datasource.ErrNoHistory)
+				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data: []model.AccountSummary{{AccountNumber: 1, Income: 10000, Spends: 2500, Currency: "EUR", Balance: 7500, OverdraftLimit: 5000, AvailableBalance: 11500, Held: 1000,
+						Default: true, Role: model.RoleOwner, AsOf: &asOf, ChangedOn: &changedOn}},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:        "Failure :: AccDetails :: no history at the time",
+			credentials: "123",
+			asOf:        asOf,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetAccountAsOf(1, asOf).Times(1).Return(model.Account{}, datasource.ErrNoHistory)
+				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusNotFound,
+					Message: codes.GetErr(codes.NoAccountHistory),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:        "Failure :: AccDetails :: history db err",
+			credentials: "123",
+			asOf:        asOf,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockJwtSvc := mock.NewMockJWTService(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetAccountAsOf(1, asOf).Times(1).Return(model.Account{}, errors.New(""))
+				return mockDs, mockJwtSvc, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingHistory),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
 				}
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rec := NewAccountManagmentSvcLogic(tt.setup())
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
 
-			got := rec.UpdateTransaction(tt.credentials)
+			got := rec.AccountDetails(tt.credentials, tt.accountNumber, tt.asOf)
 
 			tt.want(got)
 		})
 	}
 }
+func TestAccountManagmentSvcLogic_UpdateServices(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name        string
+		credentials model.UpdateServices
+		setup       func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want        func(*respModel.Response)
+	}{
+		{
+			name: "Success",
+			credentials: model.UpdateServices{
+				AccountNumber: 1,
+				ServiceId:     "10",
+				UpdateType:    "add",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(model.Account{Id: "1234", AccountNumber: 1, Status: model.AccountActive}), nil)
+				mockDs.EXPECT().UpdateServices(1, map[string]interface{}{"active_services": model.ColumnUpdate{UpdateSet: "JSON_INSERT(active_services, '$.\"10\"', JSON_OBJECT())"}, "inactive_services": model.ColumnUpdate{UpdateSet: "JSON_REMOVE(inactive_services, '$.\"10\"')"}}).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Success :: remove",
+			credentials: model.UpdateServices{
+				AccountNumber: 1,
+				ServiceId:     "10",
+				UpdateType:    "remove",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(model.Account{Id: "1234", AccountNumber: 1, Status: model.AccountActive}), nil)
+				mockDs.EXPECT().UpdateServices(1, map[string]interface{}{"active_services": model.ColumnUpdate{UpdateSet: "JSON_REMOVE(active_services, '$.\"10\"')"}, "inactive_services": model.ColumnUpdate{UpdateSet: "JSON_INSERT(inactive_services, '$.\"10\"', JSON_OBJECT())"}}).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure::DB ERR",
+			credentials: model.UpdateServices{
+				AccountNumber: 1,
+				ServiceId:     "10",
+				UpdateType:    "add",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(model.Account{Id: "1234", AccountNumber: 1, Status: model.AccountActive}), nil)
+				mockDs.EXPECT().UpdateServices(1, gomock.Any()).Times(1).Return(nil, errors.New("DB ERR"))
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrUpdatingServices),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Success :: frozen account drops a service",
+			credentials: model.UpdateServices{
+				AccountNumber: 1,
+				ServiceId:     "10",
+				UpdateType:    "remove",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(model.Account{Id: "1234", AccountNumber: 1, Status: model.AccountFrozen}), nil)
+				mockDs.EXPECT().UpdateServices(1, gomock.Any()).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: frozen account takes up a service",
+			credentials: model.UpdateServices{
+				AccountNumber: 1,
+				ServiceId:     "10",
+				UpdateType:    "add",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(model.Account{Id: "1234", AccountNumber: 1, Status: model.AccountFrozen}), nil)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusConflict,
+					Message: codes.GetErr(codes.ErrAccountFrozen),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: closed account",
+			credentials: model.UpdateServices{
+				AccountNumber: 1,
+				ServiceId:     "10",
+				UpdateType:    "remove",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(model.Account{Id: "1234", AccountNumber: 1, Status: model.AccountClosed}), nil)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusConflict,
+					Message: codes.GetErr(codes.ErrAccountClosed),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: account not found",
+			credentials: model.UpdateServices{
+				AccountNumber: 1,
+				ServiceId:     "10",
+				UpdateType:    "add",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.AccNotFound),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure::switch default case",
+			credentials: model.UpdateServices{
+				AccountNumber: 1,
+				ServiceId:     "10",
+				UpdateType:    "",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrUpdatingServices),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.UpdateServices("1234", tt.credentials)
+
+			tt.want(got)
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_UpdateTransaction(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name        string
+		credentials model.UpdateTransaction
+		setup       func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want        func(*respModel.Response)
+	}{
+		{
+			name: "Success::DEBIT",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          100000,
+				TransactionType: "debit",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 100000, TransactionType: "debit"}).Times(1).Return(int64(1), nil)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    model.TransactionReceipt{TransactionId: 1},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Success :: category and merchant",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          4210,
+				TransactionType: "debit",
+				Category:        " Groceries ",
+				Merchant:        " Corner Shop ",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 4210, TransactionType: "debit", Category: "groceries", Merchant: "Corner Shop"}).Times(1).Return(int64(3), nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    model.TransactionReceipt{TransactionId: 3},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Success :: CREDIT",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          100000,
+				TransactionType: "credit",
+				Reference:       "ref-1",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 100000, TransactionType: "credit", Reference: "ref-1"}).Times(1).Return(int64(2), nil)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    model.TransactionReceipt{TransactionId: 2},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure::DB ERR",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          100000,
+				TransactionType: "debit",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(gomock.Any()).Times(1).Return(int64(0), errors.New("DB ERR"))
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrUpdatingTransaction),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure::account not found",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          100000,
+				TransactionType: "credit",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertTransaction(gomock.Any()).Times(1).Return(int64(0), datasource.ErrAccountNotFound)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.AccNotFound),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure::default switch case",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          100000,
+				TransactionType: "",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				return mockDs, nil, config.MsgQueue{MsgBroker: sdk.NewMsgBrokerSvc("http://localhost:9095")}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrUpdatingTransaction),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Success :: converted to account currency",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          10000,
+				TransactionType: "credit",
+				Currency:        "EUR",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{{AccountNumber: 1, Currency: "USD"}}, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 11000, Currency: "USD", OriginalAmount: 10000, OriginalCurrency: "EUR", TransactionType: "credit"}).Times(1).Return(int64(3), nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    model.TransactionReceipt{TransactionId: 3},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Success :: same currency as account",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          10000,
+				TransactionType: "debit",
+				Currency:        "USD",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{{AccountNumber: 1, Currency: "USD"}}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 10000, Currency: "USD", TransactionType: "debit"}).Times(1).Return(int64(4), nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    model.TransactionReceipt{TransactionId: 4},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: no exchange rate",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          10000,
+				TransactionType: "debit",
+				Currency:        "GBP",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{{AccountNumber: 1, Currency: "USD"}}, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrUnsupportedCurrency),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: currency of unknown account",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          10000,
+				TransactionType: "debit",
+				Currency:        "EUR",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.AccNotFound),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: insufficient funds",
+			credentials: model.UpdateTransaction{
+				AccountNumber:   1,
+				Amount:          10000,
+				TransactionType: "debit",
+			},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(gomock.Any()).Times(1).Return(int64(0), datasource.ErrInsufficientFunds)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusUnprocessableEntity,
+					Message: codes.GetErr(codes.ErrInsufficientFunds),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.UpdateTransaction(tt.credentials)
+
+			tt.want(got)
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_Fees(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	fees := config.FeeCfg{Rules: []config.FeeRule{
+		{Name: "card fee", TransactionType: "debit", Type: "percentage", Amount: "1.5"},
+		{Name: "sms alert", TransactionType: "debit", ServiceId: "sms", Type: "flat", Amount: "0.10", Currency: "EUR"},
+		{Name: "sms activation", ServiceId: "sms", Type: "flat", Amount: "1.00"},
+	}}
+	sms := model.Svc{"sms": nil}
+	account := model.Account{Id: "1234", AccountNumber: 1, Currency: "USD", ActiveServices: &model.Svc{}, Status: model.AccountActive}
+	smsAccount := model.Account{Id: "1234", AccountNumber: 1, Currency: "USD", ActiveServices: &sms, Status: model.AccountActive}
+	cardFee := model.Transaction{AccountNumber: 1, Amount: 150, Currency: "USD", TransactionType: "debit", Reference: "card fee", Category: "fees", Contra: model.LedgerFees}
+	smsFee := model.Transaction{AccountNumber: 1, Amount: 11, Currency: "USD", OriginalAmount: 10, OriginalCurrency: "EUR", TransactionType: "debit", Reference: "sms alert", Category: "fees", Contra: model.LedgerFees}
+	activationFee := model.Transaction{AccountNumber: 1, Amount: 100, Currency: "USD", TransactionType: "debit", Reference: "sms activation", Category: "fees", Contra: model.LedgerFees}
+	tests := []struct {
+		name  string
+		call  func(l AccountManagmentSvcLogicIer) *respModel.Response
+		setup func() datasource.DataSourceI
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: transaction fee",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{account}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransactionWithFees(model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"}, cardFee).Times(1).Return([]int64{7, 8}, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransactionReceipt{TransactionId: 7, FeeTransactionIds: []int64{8}}},
+		},
+		{
+			name: "Success :: fee of an active service converted to the account currency",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{smsAccount}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransactionWithFees(model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"}, cardFee, smsFee).Times(1).Return([]int64{7, 8, 9}, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransactionReceipt{TransactionId: 7, FeeTransactionIds: []int64{8, 9}}},
+		},
+		{
+			name: "Success :: fee rounding to nothing",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 33, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{account}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 33, TransactionType: "debit"}).Times(1).Return(int64(7), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransactionReceipt{TransactionId: 7}},
+		},
+		{
+			name: "Success :: no rule for credits",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "credit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "credit"}).Times(1).Return(int64(7), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransactionReceipt{TransactionId: 7}},
+		},
+		{
+			name: "Failure :: transaction fee exceeds the funds",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{account}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransactionWithFees(gomock.Any(), gomock.Any()).Times(1).Return(nil, datasource.ErrInsufficientFunds)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusUnprocessableEntity, Message: codes.GetErr(codes.ErrInsufficientFunds), Data: nil},
+		},
+		{
+			name: "Failure :: transaction fee :: account not found",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.AccNotFound), Data: nil},
+		},
+		{
+			name: "Success :: service fee",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateServices("1234", model.UpdateServices{AccountNumber: 1, ServiceId: "sms", UpdateType: "add"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(account), nil)
+				mockDs.EXPECT().UpdateServices(1, gomock.Any(), activationFee).Times(1).Return([]int64{9}, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.ServiceReceipt{FeeTransactionIds: []int64{9}}},
+		},
+		{
+			name: "Success :: service already active",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateServices("1234", model.UpdateServices{AccountNumber: 1, ServiceId: "sms", UpdateType: "add"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(smsAccount), nil)
+				mockDs.EXPECT().UpdateServices(1, gomock.Any()).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: nil},
+		},
+		{
+			name: "Success :: no fee for removing a service",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateServices("1234", model.UpdateServices{AccountNumber: 1, ServiceId: "sms", UpdateType: "remove"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(smsAccount), nil)
+				mockDs.EXPECT().UpdateServices(1, gomock.Any()).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: nil},
+		},
+		{
+			name: "Failure :: service fee exceeds the funds",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateServices("1234", model.UpdateServices{AccountNumber: 1, ServiceId: "sms", UpdateType: "add"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(account), nil)
+				mockDs.EXPECT().UpdateServices(1, gomock.Any(), activationFee).Times(1).Return(nil, datasource.ErrInsufficientFunds)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusUnprocessableEntity, Message: codes.GetErr(codes.ErrInsufficientFunds), Data: nil},
+		},
+		{
+			name: "Failure :: service fee :: account not found",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateServices("1234", model.UpdateServices{AccountNumber: 1, ServiceId: "sms", UpdateType: "add"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.AccNotFound), Data: nil},
+		},
+		{
+			name: "Failure :: nothing charged when the update fails",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateServices("1234", model.UpdateServices{AccountNumber: 1, ServiceId: "sms", UpdateType: "add"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("1234", 1).Times(1).Return(owned(account), nil)
+				mockDs.EXPECT().UpdateServices(1, gomock.Any(), activationFee).Times(1).Return(nil, errors.New("DB ERR"))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrUpdatingServices), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency, Fees: fees})
+
+			got := tt.call(rec)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_SpendLimits(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	limits := config.SpendLimitCfg{Tiers: map[string]model.SpendLimits{
+		"current": {MaxDebit: 50000, MaxDailyDebit: 100000, MaxDailyCount: 10},
+	}}
+	current := model.Account{AccountNumber: 1, Currency: "USD", AccountType: "current"}
+	savings := model.Account{AccountNumber: 1, Currency: "USD", AccountType: "savings"}
+	debit := model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"}
+	maxDebit, unlimited, count := model.Money(20000), model.Money(0), 3
+	expiresOn := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	tests := []struct {
+		name  string
+		call  func(l AccountManagmentSvcLogicIer) *respModel.Response
+		setup func() datasource.DataSourceI
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: tier limits",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertDebit(limits.Tiers["current"], gomock.Any(), debit).Times(1).Return([]int64{7}, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransactionReceipt{TransactionId: 7}},
+		},
+		{
+			name: "Success :: overridden limits",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(&model.SpendLimitOverride{AccountNumber: 1, MaxDebit: &maxDebit, MaxDailyDebit: &unlimited}, nil)
+				mockDs.EXPECT().InsertDebit(model.SpendLimits{MaxDebit: 20000, MaxDailyCount: 10}, gomock.Any(), debit).Times(1).Return([]int64{7}, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransactionReceipt{TransactionId: 7}},
+		},
+		{
+			name: "Success :: tier without limits",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{savings}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(debit).Times(1).Return(int64(7), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransactionReceipt{TransactionId: 7}},
+		},
+		{
+			name: "Success :: credits are not limited",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "credit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "credit"}).Times(1).Return(int64(7), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransactionReceipt{TransactionId: 7}},
+		},
+		{
+			name: "Failure :: single debit limit",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertDebit(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, datasource.ErrDebitLimit)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusUnprocessableEntity, Message: codes.GetErr(codes.ErrDebitLimit), Data: nil},
+		},
+		{
+			name: "Failure :: daily debit limit",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertDebit(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, datasource.ErrDailyDebitLimit)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusUnprocessableEntity, Message: codes.GetErr(codes.ErrDailyDebitLimit), Data: nil},
+		},
+		{
+			name: "Failure :: daily debit count",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertDebit(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, datasource.ErrDailyDebitCount)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusUnprocessableEntity, Message: codes.GetErr(codes.ErrDailyDebitCount), Data: nil},
+		},
+		{
+			name: "Failure :: db err fetching the overridden limits",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateTransaction(model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrUpdatingTransaction), Data: nil},
+		},
+		{
+			name: "Success :: transfer :: tier limits",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Transfer(model.Transfer{FromAccount: 1, ToAccount: 2, Amount: 10000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(2).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 2}).Times(1).Return([]model.Account{{AccountNumber: 2, Currency: "USD"}}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransfer(limits.Tiers["current"], gomock.Any(), model.Transaction{AccountNumber: 1, Amount: 10000, Currency: "USD", TransactionType: "debit"}, gomock.Any()).Times(1).Return([]int64{7, 8}, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransferReceipt{DebitTransactionId: 7, CreditTransactionId: 8}},
+		},
+		{
+			name: "Failure :: transfer :: daily debit limit",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Transfer(model.Transfer{FromAccount: 1, ToAccount: 2, Amount: 10000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(3).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransfer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, datasource.ErrDailyDebitLimit)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusUnprocessableEntity, Message: codes.GetErr(codes.ErrDailyDebitLimit), Data: nil},
+		},
+		{
+			name: "Success :: hold :: overridden limits",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.PlaceHold(model.NewHold{AccountNumber: 1, Amount: 10000, ExpiresOn: &expiresOn})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(&model.SpendLimitOverride{AccountNumber: 1, MaxDebit: &maxDebit, MaxDailyDebit: &unlimited}, nil)
+				mockDs.EXPECT().InsertHold(model.SpendLimits{MaxDebit: 20000, MaxDailyCount: 10}, gomock.Any(), gomock.Any()).Times(1).Return(int64(5), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.HoldReceipt{HoldId: 5, ExpiresOn: expiresOn}},
+		},
+		{
+			name: "Failure :: hold :: single debit limit",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.PlaceHold(model.NewHold{AccountNumber: 1, Amount: 60000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertHold(limits.Tiers["current"], gomock.Any(), gomock.Any()).Times(1).Return(int64(0), datasource.ErrDebitLimit)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusUnprocessableEntity, Message: codes.GetErr(codes.ErrDebitLimit), Data: nil},
+		},
+		{
+			name: "Failure :: capture :: daily debit count",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CaptureHold(model.CaptureHold{HoldId: 5})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetHold(int64(5)).Times(1).Return(model.Hold{Id: 5, AccountNumber: 1, Amount: 10000, Status: model.HoldPending}, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().CaptureHold(limits.Tiers["current"], gomock.Any(), int64(5), model.Money(0), "", gomock.Any()).Times(1).Return(model.Transaction{}, datasource.ErrDailyDebitCount)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusUnprocessableEntity, Message: codes.GetErr(codes.ErrDailyDebitCount), Data: nil},
+		},
+		{
+			name: "Failure :: capture :: db err fetching the overridden limits",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CaptureHold(model.CaptureHold{HoldId: 5})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetHold(int64(5)).Times(1).Return(model.Hold{Id: 5, AccountNumber: 1, Amount: 10000, Status: model.HoldPending}, nil)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrCapturingHold), Data: nil},
+		},
+		{
+			name: "Success :: override",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateSpendLimits(model.UpdateSpendLimits{AccountNumber: 1, MaxDebit: &maxDebit, MaxDailyCount: &count})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().UpsertSpendLimits(model.SpendLimitOverride{AccountNumber: 1, MaxDebit: &maxDebit, MaxDailyCount: &count}).Times(1).Return(nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.SpendLimits{MaxDebit: 20000, MaxDailyDebit: 100000, MaxDailyCount: 3}},
+		},
+		{
+			name: "Failure :: override :: negative count",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				negative := -1
+				return l.UpdateSpendLimits(model.UpdateSpendLimits{AccountNumber: 1, MaxDailyCount: &negative})
+			},
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrInvalidSpendLimits), Data: nil},
+		},
+		{
+			name: "Failure :: override :: account not found",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateSpendLimits(model.UpdateSpendLimits{AccountNumber: 1})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.AccNotFound), Data: nil},
+		},
+		{
+			name: "Failure :: override :: db err",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateSpendLimits(model.UpdateSpendLimits{AccountNumber: 1})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return([]model.Account{current}, nil)
+				mockDs.EXPECT().UpsertSpendLimits(gomock.Any()).Times(1).Return(errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrUpdatingSpendLimits), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency, SpendLimits: limits})
+
+			got := tt.call(rec)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_TransactionHistory(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name   string
+		filter model.TransactionFilter
+		setup  func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want   func(*respModel.Response)
+	}{
+		{
+			name:   "Success :: next page available",
+			filter: model.TransactionFilter{Limit: 2, TransactionType: "debit"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetTransactions(model.TransactionFilter{AccountNumber: 1, Limit: 3, TransactionType: "debit"}).Times(1).Return([]model.Transaction{{Id: 9}, {Id: 8}, {Id: 7}}, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data:    model.TransactionHistory{Transactions: []model.Transaction{{Id: 9}, {Id: 8}}, NextCursor: 8},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:   "Success :: last page with default limit",
+			filter: model.TransactionFilter{Cursor: 8},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetTransactions(model.TransactionFilter{AccountNumber: 1, Cursor: 8, Limit: defaultPageSize + 1}).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data:    model.TransactionHistory{Transactions: []model.Transaction{}},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:   "Failure :: invalid amount range",
+			filter: model.TransactionFilter{MinAmount: 1000, MaxAmount: 500},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				return mock.NewMockDataSourceI(mockCtrl), nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidQuery),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: db err fetching account",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(nil, errors.New(""))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingUser),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: account not found",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.AccNotFound),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: db err fetching transactions",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetTransactions(gomock.Any()).Times(1).Return(nil, errors.New(""))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingTransactions),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.TransactionHistory("123", tt.filter)
+
+			tt.want(got)
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_Transfer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name     string
+		transfer model.Transfer
+		setup    func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want     func(*respModel.Response)
+	}{
+		{
+			name:     "Success",
+			transfer: model.Transfer{FromAccount: 1, ToAccount: 2, Amount: 50000, Reference: "rent"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{{AccountNumber: 1, Currency: "USD"}}, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 2}).Times(1).Return([]model.Account{{AccountNumber: 2, Currency: "USD"}}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransfer(model.SpendLimits{}, gomock.Any(),
+					model.Transaction{AccountNumber: 1, Amount: 50000, Currency: "USD", TransactionType: "debit", Reference: "rent"},
+					model.Transaction{AccountNumber: 2, Amount: 50000, Currency: "USD", TransactionType: "credit", Reference: "rent"},
+				).Times(1).Return([]int64{10, 11}, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    model.TransferReceipt{DebitTransactionId: 10, CreditTransactionId: 11},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name:     "Failure :: same account",
+			transfer: model.Transfer{FromAccount: 1, ToAccount: 1, Amount: 50000},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				return mock.NewMockDataSourceI(mockCtrl), nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrSameAccountTransfer),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name:     "Failure :: zero amount",
+			transfer: model.Transfer{FromAccount: 1, ToAccount: 2},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				return mock.NewMockDataSourceI(mockCtrl), nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidAmount),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name:     "Failure :: account not found",
+			transfer: model.Transfer{FromAccount: 1, ToAccount: 2, Amount: 50000},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{{AccountNumber: 1, Currency: "USD"}}, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 2}).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.AccNotFound),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name:     "Failure :: DB ERR",
+			transfer: model.Transfer{FromAccount: 1, ToAccount: 2, Amount: 50000},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(2).Return([]model.Account{{Currency: "USD"}}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransfer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("DB ERR"))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrTransferringFunds),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name:     "Success :: cross currency",
+			transfer: model.Transfer{FromAccount: 1, ToAccount: 2, Amount: 1000},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{{AccountNumber: 1, Currency: "USD"}}, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 2}).Times(1).Return([]model.Account{{AccountNumber: 2, Currency: "EUR"}}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransfer(model.SpendLimits{}, gomock.Any(),
+					model.Transaction{AccountNumber: 1, Amount: 1000, Currency: "USD", TransactionType: "debit"},
+					model.Transaction{AccountNumber: 2, Amount: 909, Currency: "EUR", OriginalAmount: 1000, OriginalCurrency: "USD", TransactionType: "credit"},
+				).Times(1).Return([]int64{12, 13}, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusAccepted,
+					Message: "SUCCESS",
+					Data:    model.TransferReceipt{DebitTransactionId: 12, CreditTransactionId: 13},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+		{
+			name:     "Failure :: currency mismatch while posting",
+			transfer: model.Transfer{FromAccount: 1, ToAccount: 2, Amount: 1000},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(2).Return([]model.Account{{Currency: "USD"}}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransfer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, datasource.ErrCurrencyMismatch)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrCurrencyMismatch),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", temp, resp)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.Transfer(tt.transfer)
+
+			tt.want(got)
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_ReverseTransaction(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name     string
+		reversal model.Reversal
+		setup    func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want     *respModel.Response
+	}{
+		{
+			name:     "Success",
+			reversal: model.Reversal{TransactionId: 5, Amount: 2000, Reference: "refund"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(int64(5), model.Money(2000), "refund").Times(1).Return(int64(9), nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusAccepted,
+				Message: "SUCCESS",
+				Data:    model.TransactionReceipt{TransactionId: 9},
+			},
+		},
+		{
+			name:     "Failure :: transaction not found",
+			reversal: model.Reversal{TransactionId: 5},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(int64(5), model.Money(0), "").Times(1).Return(int64(0), datasource.ErrTransactionNotFound)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusNotFound,
+				Message: codes.GetErr(codes.TransactionNotFound),
+			},
+		},
+		{
+			name:     "Failure :: already reversed",
+			reversal: model.Reversal{TransactionId: 5},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(int64(5), model.Money(0), "").Times(1).Return(int64(0), datasource.ErrAlreadyReversed)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusConflict,
+				Message: codes.GetErr(codes.ErrAlreadyReversed),
+			},
+		},
+		{
+			name:     "Failure :: amount exceeds remaining",
+			reversal: model.Reversal{TransactionId: 5, Amount: 50000},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(int64(5), model.Money(50000), "").Times(1).Return(int64(0), datasource.ErrReversalExceedsAmount)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrReversalExceedsAmount),
+			},
+		},
+		{
+			name:     "Failure :: reversal of a reversal",
+			reversal: model.Reversal{TransactionId: 9},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(int64(9), model.Money(0), "").Times(1).Return(int64(0), datasource.ErrReversalOfReversal)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.ErrReversalOfReversal),
+			},
+		},
+		{
+			name:     "Failure :: insufficient funds for the refund of a credit",
+			reversal: model.Reversal{TransactionId: 5},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(int64(5), model.Money(0), "").Times(1).Return(int64(0), datasource.ErrInsufficientFunds)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusUnprocessableEntity,
+				Message: codes.GetErr(codes.ErrInsufficientFunds),
+			},
+		},
+		{
+			name:     "Failure :: frozen account",
+			reversal: model.Reversal{TransactionId: 5},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(int64(5), model.Money(0), "").Times(1).Return(int64(0), datasource.ErrAccountFrozen)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusConflict,
+				Message: codes.GetErr(codes.ErrAccountFrozen),
+			},
+		},
+		{
+			name:     "Failure :: closed account",
+			reversal: model.Reversal{TransactionId: 5},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(int64(5), model.Money(0), "").Times(1).Return(int64(0), datasource.ErrAccountClosed)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusConflict,
+				Message: codes.GetErr(codes.ErrAccountClosed),
+			},
+		},
+		{
+			name:     "Failure :: account not active",
+			reversal: model.Reversal{TransactionId: 5},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(int64(5), model.Money(0), "").Times(1).Return(int64(0), datasource.ErrAccountNotActive)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusConflict,
+				Message: codes.GetErr(codes.ErrAccountNotActive),
+			},
+		},
+		{
+			name:     "Failure :: DB ERR",
+			reversal: model.Reversal{TransactionId: 5},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReverseTransaction(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(int64(0), errors.New("DB ERR"))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusInternalServerError,
+				Message: codes.GetErr(codes.ErrReversingTransaction),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.ReverseTransaction(tt.reversal)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_UpdateOverdraftLimit(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		limit model.OverdraftLimit
+		setup func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want  *respModel.Response
+	}{
+		{
+			name:  "Success",
+			limit: model.OverdraftLimit{AccountNumber: 1, Limit: 50000},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return([]model.Account{{AccountNumber: 1}}, nil)
+				mockDs.EXPECT().Update(map[string]interface{}{"overdraft_limit": model.Money(50000)}, map[string]interface{}{"account_number": 1}).Times(1).Return(nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusAccepted,
+				Message: "SUCCESS",
+			},
+		},
+		{
+			name:  "Failure :: account not found",
+			limit: model.OverdraftLimit{AccountNumber: 1, Limit: 50000},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.AccNotFound),
+			},
+		},
+		{
+			name:  "Failure :: get account err",
+			limit: model.OverdraftLimit{AccountNumber: 1, Limit: 50000},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return(nil, errors.New("DB ERR"))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusInternalServerError,
+				Message: codes.GetErr(codes.ErrUpdatingOverdraft),
+			},
+		},
+		{
+			name:  "Failure :: update err",
+			limit: model.OverdraftLimit{AccountNumber: 1},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(gomock.Any()).Times(1).Return([]model.Account{{AccountNumber: 1}}, nil)
+				mockDs.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(errors.New("DB ERR"))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusInternalServerError,
+				Message: codes.GetErr(codes.ErrUpdatingOverdraft),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.UpdateOverdraftLimit(tt.limit)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_UpdateAccountStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name   string
+		update model.UpdateAccountStatus
+		setup  func() datasource.DataSourceI
+		want   *respModel.Response
+	}{
+		{
+			name:   "Success",
+			update: model.UpdateAccountStatus{AccountNumber: 1, Status: model.AccountFrozen},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountFrozen).Times(1).Return(model.AccountActive, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.AccountStatusChange{AccountNumber: 1, Status: model.AccountFrozen, PreviousStatus: model.AccountActive}},
+		},
+		{
+			name:   "Failure :: transition not allowed",
+			update: model.UpdateAccountStatus{AccountNumber: 1, Status: model.AccountActive},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountActive).Times(1).Return(model.AccountClosed, datasource.ErrInvalidTransition)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrInvalidTransition), Data: model.AccountStatusChange{AccountNumber: 1, Status: model.AccountClosed}},
+		},
+		{
+			name:   "Failure :: account not found",
+			update: model.UpdateAccountStatus{AccountNumber: 1, Status: model.AccountClosed},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountClosed).Times(1).Return("", datasource.ErrAccountNotFound)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.AccNotFound), Data: nil},
+		},
+		{
+			name:   "Failure :: db err",
+			update: model.UpdateAccountStatus{AccountNumber: 1, Status: model.AccountClosed},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountClosed).Times(1).Return("", errors.New("DB ERR"))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrUpdatingStatus), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			got := rec.UpdateAccountStatus(tt.update)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_CloseAccount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	confirmed := model.ClosureEvent{Event: model.ClosureConfirmed, UserId: "123", AccountNumber: 1}
+	tests := []struct {
+		name       string
+		setup      func() datasource.DataSourceI
+		want       *respModel.Response
+		wantEvents []model.ClosureEvent
+	}{
+		{
+			name: "Success",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, Status: model.AccountActive}}, nil)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountClosed).Times(1).Return(model.AccountActive, nil)
+				mockDs.EXPECT().DeleteUserMembers("123").Times(1).Return(nil)
+				return mockDs
+			},
+			want:       &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: []model.ClosureEvent{confirmed}},
+			wantEvents: []model.ClosureEvent{confirmed},
+		},
+		{
+			name: "Success :: already closed",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, Status: model.AccountClosed}}, nil)
+				mockDs.EXPECT().DeleteUserMembers("123").Times(1).Return(nil)
+				return mockDs
+			},
+			want:       &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: []model.ClosureEvent{confirmed}},
+			wantEvents: []model.ClosureEvent{confirmed},
+		},
+		{
+			name: "Failure :: balance not settled",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, Status: model.AccountActive}}, nil)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountClosed).Times(1).Return(model.AccountActive, datasource.ErrBalanceNotSettled)
+				mockDs.EXPECT().DeleteUserMembers("123").Times(1).Return(nil)
+				return mockDs
+			},
+			want:       &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrBalanceNotSettled), Data: []model.ClosureEvent{{Event: model.ClosureRejected, UserId: "123", AccountNumber: 1, Reason: codes.GetErr(codes.ErrBalanceNotSettled)}}},
+			wantEvents: []model.ClosureEvent{{Event: model.ClosureRejected, UserId: "123", AccountNumber: 1, Reason: codes.GetErr(codes.ErrBalanceNotSettled)}},
+		},
+		{
+			name: "Failure :: holds not settled",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, Status: model.AccountFrozen}}, nil)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountClosed).Times(1).Return(model.AccountFrozen, datasource.ErrHoldsNotSettled)
+				mockDs.EXPECT().DeleteUserMembers("123").Times(1).Return(nil)
+				return mockDs
+			},
+			want:       &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrHoldsNotSettled), Data: []model.ClosureEvent{{Event: model.ClosureRejected, UserId: "123", AccountNumber: 1, Reason: codes.GetErr(codes.ErrHoldsNotSettled)}}},
+			wantEvents: []model.ClosureEvent{{Event: model.ClosureRejected, UserId: "123", AccountNumber: 1, Reason: codes.GetErr(codes.ErrHoldsNotSettled)}},
+		},
+		{
+			name: "Failure :: one of the accounts is not settled",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, Status: model.AccountActive}, {Id: "123", AccountNumber: 2, Status: model.AccountActive}}, nil)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountClosed).Times(1).Return(model.AccountActive, datasource.ErrBalanceNotSettled)
+				mockDs.EXPECT().SetAccountStatus(2, model.AccountClosed).Times(1).Return(model.AccountActive, nil)
+				mockDs.EXPECT().DeleteUserMembers("123").Times(1).Return(nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrBalanceNotSettled), Data: []model.ClosureEvent{
+				{Event: model.ClosureRejected, UserId: "123", AccountNumber: 1, Reason: codes.GetErr(codes.ErrBalanceNotSettled)},
+				{Event: model.ClosureConfirmed, UserId: "123", AccountNumber: 2},
+			}},
+			wantEvents: []model.ClosureEvent{
+				{Event: model.ClosureRejected, UserId: "123", AccountNumber: 1, Reason: codes.GetErr(codes.ErrBalanceNotSettled)},
+				{Event: model.ClosureConfirmed, UserId: "123", AccountNumber: 2},
+			},
+		},
+		{
+			name: "Failure :: account not found",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(nil, nil)
+				mockDs.EXPECT().DeleteUserMembers("123").Times(1).Return(nil)
+				return mockDs
+			},
+			want:       &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.AccNotFound), Data: nil},
+			wantEvents: []model.ClosureEvent{{Event: model.ClosureRejected, UserId: "123", Reason: codes.GetErr(codes.AccNotFound)}},
+		},
+		{
+			name: "Failure :: db err fetching account",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(nil, errors.New("DB ERR"))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrClosingAccount), Data: nil},
+		},
+		{
+			name: "Failure :: db err closing account",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, Status: model.AccountActive}}, nil)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountClosed).Times(1).Return("", errors.New("DB ERR"))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrClosingAccount), Data: nil},
+		},
+		{
+			name: "Failure :: db err removing memberships",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return([]model.Account{{Id: "123", AccountNumber: 1, Status: model.AccountActive}}, nil)
+				mockDs.EXPECT().SetAccountStatus(1, model.AccountClosed).Times(1).Return(model.AccountActive, nil)
+				mockDs.EXPECT().DeleteUserMembers("123").Times(1).Return(errors.New("DB ERR"))
+				return mockDs
+			},
+			want:       &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrClosingAccount), Data: nil},
+			wantEvents: []model.ClosureEvent{confirmed},
+		},
+		{
+			name: "Failure :: db err removing memberships of a user without accounts",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"user_id": "123"}).Times(1).Return(nil, nil)
+				mockDs.EXPECT().DeleteUserMembers("123").Times(1).Return(errors.New("DB ERR"))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrClosingAccount), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []model.ClosureEvent
+			broker := mock.NewMockMsgBrokerSvcI(mockCtrl)
+			broker.EXPECT().PushMsg(gomock.Any(), "pub", "closure.result.channel").Times(len(tt.wantEvents)).DoAndReturn(func(msg string, pubId string, channel string) error {
+				var event model.ClosureEvent
+				if err := json.Unmarshal([]byte(msg), &event); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				events = append(events, event)
+				return nil
+			})
+			msgQueue := config.MsgQueue{MsgBroker: broker, ClosurePubId: "pub", ClosureChannel: "closure.result.channel"}
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, msgQueue, config.Config{Currency: testCurrency})
+
+			got := rec.CloseAccount(model.AccountClosure{UserId: "123"})
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+			if !reflect.DeepEqual(events, tt.wantEvents) {
+				t.Errorf("Want: %v, Got: %v", tt.wantEvents, events)
+			}
+		})
+	}
+}
+func TestAccountManagmentSvcLogic_Statement(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	month := time.Date(2022, 9, 17, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	filter := model.TransactionFilter{AccountNumber: 1, From: from, To: to.Add(-time.Second)}
+	credit := model.Transaction{Id: 1, AccountNumber: 1, Amount: 5000, Currency: "USD", TransactionType: "credit", CreatedOn: from.Add(time.Hour)}
+	debit := model.Transaction{Id: 2, AccountNumber: 1, Amount: 2500, Currency: "USD", TransactionType: "debit", CreatedOn: from.Add(2 * time.Hour)}
+	wantCsv, err := statement.CSV(model.Statement{AccountNumber: 1, Currency: "USD", From: from, To: to, OpeningBalance: 10000, ClosingBalance: 12500, Transactions: []model.Transaction{credit, debit}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		format string
+		setup  func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want   func(*respModel.Response)
+	}{
+		{
+			name:   "Success :: csv",
+			format: StatementFormatCsv,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1, Currency: "USD"}), nil)
+				mockDs.EXPECT().GetBalance(1, from).Times(1).Return(model.Money(10000), nil)
+				mockDs.EXPECT().GetTransactions(filter).Times(1).Return([]model.Transaction{debit, credit}, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data:    model.StatementFile{Name: "statement-1-2022-09.csv", ContentType: "text/csv", Content: wantCsv},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:   "Success :: pdf",
+			format: StatementFormatPdf,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1, Currency: "USD"}), nil)
+				mockDs.EXPECT().GetBalance(1, from).Times(1).Return(model.Money(0), nil)
+				mockDs.EXPECT().GetTransactions(filter).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				file, ok := resp.Data.(model.StatementFile)
+				if resp.Status != http.StatusOK || !ok {
+					t.Errorf("Want: %v, Got: %v", http.StatusOK, resp)
+					return
+				}
+				if file.Name != "statement-1-2022-09.pdf" || file.ContentType != "application/pdf" || !strings.HasPrefix(string(file.Content), "%PDF-") {
+					t.Errorf("Want: %v, Got: %v", "statement-1-2022-09.pdf", file.Name)
+				}
+			},
+		},
+		{
+			name:   "Failure :: unsupported format",
+			format: "xml",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				return mock.NewMockDataSourceI(mockCtrl), nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidQuery),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:   "Failure :: db err fetching account",
+			format: StatementFormatPdf,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(nil, errors.New(""))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingUser),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:   "Failure :: account not found",
+			format: StatementFormatPdf,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.AccNotFound),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:   "Failure :: db err fetching opening balance",
+			format: StatementFormatCsv,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetBalance(1, from).Times(1).Return(model.Money(0), errors.New(""))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingTransactions),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:   "Failure :: db err fetching transactions",
+			format: StatementFormatCsv,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetBalance(1, from).Times(1).Return(model.Money(0), nil)
+				mockDs.EXPECT().GetTransactions(filter).Times(1).Return(nil, errors.New(""))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingTransactions),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.Statement("123", 0, month, tt.format)
+
+			tt.want(got)
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_Analytics(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	from := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		from, to time.Time
+		setup    func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want     func(*respModel.Response)
+	}{
+		{
+			name: "Success",
+			from: from.Add(36 * time.Hour),
+			to:   to.Add(72 * time.Hour),
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1, Currency: "USD"}), nil)
+				mockDs.EXPECT().GetTransactionTotals(1, from, end).Times(1).Return([]model.TransactionTotals{
+					{Month: "2022-07", Category: "", Income: 300000, Spends: 1000},
+					{Month: "2022-07", Category: "groceries", Spends: 4000},
+					{Month: "2022-09", Category: "rent", Spends: 150000},
+					{Month: "2022-09", Category: "groceries", Spends: 2000},
+					{Month: "2022-09", Category: "salary", Income: 300000},
+				}, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data: model.Analytics{
+						AccountNumber: 1,
+						Currency:      "USD",
+						From:          "2022-07",
+						To:            "2022-09",
+						SpendByCategory: []model.CategorySpend{
+							{Category: "rent", Spends: 150000},
+							{Category: "groceries", Spends: 6000},
+							{Category: uncategorized, Spends: 1000},
+						},
+						Monthly: []model.MonthlySummary{
+							{Month: "2022-07", Income: 300000, Spends: 5000, Net: 295000},
+							{Month: "2022-08"},
+							{Month: "2022-09", Income: 300000, Spends: 152000, Net: 148000},
+						},
+					},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name: "Success :: no transactions",
+			from: to,
+			to:   to,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1, Currency: "USD"}), nil)
+				mockDs.EXPECT().GetTransactionTotals(1, to, end).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusOK,
+					Message: "SUCCESS",
+					Data: model.Analytics{
+						AccountNumber:   1,
+						Currency:        "USD",
+						From:            "2022-09",
+						To:              "2022-09",
+						SpendByCategory: []model.CategorySpend{},
+						Monthly:         []model.MonthlySummary{{Month: "2022-09"}},
+					},
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: from after to",
+			from: to,
+			to:   from,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				return mock.NewMockDataSourceI(mockCtrl), nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidQuery),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: window too long",
+			from: from,
+			to:   from.AddDate(0, maxAnalyticsMonths, 0),
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				return mock.NewMockDataSourceI(mockCtrl), nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidQuery),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: account not found",
+			from: from,
+			to:   to,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.AccNotFound),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name: "Failure :: db err fetching totals",
+			from: from,
+			to:   to,
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetTransactionTotals(1, from, end).Times(1).Return(nil, errors.New(""))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrFetchingAnalytics),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.Analytics("123", 0, tt.from, tt.to)
+
+			tt.want(got)
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_CreateStandingOrder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	past := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		order model.NewStandingOrder
+		setup func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want  func(*respModel.Response)
+	}{
+		{
+			name:  "Success",
+			order: model.NewStandingOrder{AccountNumber: 1, Amount: 2500, TransactionType: "debit", Currency: "EUR", Reference: "rent", Schedule: "0 9 1 * *"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1, Currency: "USD"}), nil)
+				mockDs.EXPECT().InsertStandingOrder(gomock.Any()).Times(1).Return(int64(5), nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				order, ok := resp.Data.(model.StandingOrder)
+				if resp.Status != http.StatusCreated || !ok {
+					t.Errorf("Want: %v, Got: %v", http.StatusCreated, resp)
+					return
+				}
+				if order.Id != 5 || order.Status != model.StandingOrderActive || order.Currency != "EUR" || order.NextRun.Day() != 1 || order.NextRun.Hour() != 9 || !order.NextRun.After(time.Now()) {
+					t.Errorf("Want: %v, Got: %v", "active standing order 5 running on the 1st at 09:00", order)
+				}
+			},
+		},
+		{
+			name:  "Failure :: invalid amount",
+			order: model.NewStandingOrder{AccountNumber: 1, TransactionType: "debit", Schedule: "@daily"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				return mock.NewMockDataSourceI(mockCtrl), nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidAmount),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:  "Failure :: invalid schedule",
+			order: model.NewStandingOrder{AccountNumber: 1, Amount: 2500, TransactionType: "debit", Schedule: "every day"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				return mock.NewMockDataSourceI(mockCtrl), nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidSchedule),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:  "Failure :: ends before the first run",
+			order: model.NewStandingOrder{AccountNumber: 1, Amount: 2500, TransactionType: "debit", Schedule: "@daily", EndDate: &past},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				return mock.NewMockDataSourceI(mockCtrl), nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrInvalidSchedule),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:  "Failure :: account of another user",
+			order: model.NewStandingOrder{AccountNumber: 1, Amount: 2500, TransactionType: "debit", Schedule: "@daily"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.AccNotFound),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:  "Failure :: viewer of a shared account",
+			order: model.NewStandingOrder{AccountNumber: 1, Amount: 2500, TransactionType: "debit", Schedule: "@daily"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return([]model.MemberAccount{{Account: model.Account{Id: "456", AccountNumber: 1}, Role: model.RoleViewer}}, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusForbidden,
+					Message: codes.GetErr(codes.ErrRoleNotAllowed),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:  "Failure :: unsupported currency",
+			order: model.NewStandingOrder{AccountNumber: 1, Amount: 2500, TransactionType: "debit", Currency: "JPY", Schedule: "@daily"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1, Currency: "USD"}), nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusBadRequest,
+					Message: codes.GetErr(codes.ErrUnsupportedCurrency),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+		{
+			name:  "Failure :: db err inserting",
+			order: model.NewStandingOrder{AccountNumber: 1, Amount: 2500, TransactionType: "debit", Schedule: "@daily"},
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1, Currency: "USD"}), nil)
+				mockDs.EXPECT().InsertStandingOrder(gomock.Any()).Times(1).Return(int64(0), errors.New(""))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: func(resp *respModel.Response) {
+				temp := respModel.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrCreatingStandingOrder),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(resp, &temp) {
+					t.Errorf("Want: %v, Got: %v", &temp, resp)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.CreateStandingOrder("123", tt.order)
+
+			tt.want(got)
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_StandingOrders(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		setup func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: no standing orders",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetStandingOrders(1).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusOK,
+				Message: "SUCCESS",
+				Data:    []model.StandingOrder{},
+			},
+		},
+		{
+			name: "Success :: viewer of a shared account",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return([]model.MemberAccount{{Account: model.Account{Id: "456", AccountNumber: 1}, Role: model.RoleViewer}}, nil)
+				mockDs.EXPECT().GetStandingOrders(1).Times(1).Return([]model.StandingOrder{{Id: 3, AccountNumber: 1}}, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusOK,
+				Message: "SUCCESS",
+				Data:    []model.StandingOrder{{Id: 3, AccountNumber: 1}},
+			},
+		},
+		{
+			name: "Failure :: account of another user",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.AccNotFound),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: db err",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().GetStandingOrders(1).Times(1).Return(nil, errors.New(""))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusInternalServerError,
+				Message: codes.GetErr(codes.ErrListingStandingOrders),
+				Data:    nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.StandingOrders("123", 1)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_CancelStandingOrder(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name  string
+		setup func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct)
+		want  *respModel.Response
+	}{
+		{
+			name: "Success",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().CancelStandingOrder(int64(5), 1).Times(1).Return(nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusAccepted,
+				Message: "SUCCESS",
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: not found",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().CancelStandingOrder(int64(5), 1).Times(1).Return(datasource.ErrStandingOrderNotFound)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusNotFound,
+				Message: codes.GetErr(codes.StandingOrderNotFound),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: account of another user",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(nil, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusBadRequest,
+				Message: codes.GetErr(codes.AccNotFound),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: viewer of a shared account",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return([]model.MemberAccount{{Account: model.Account{Id: "456", AccountNumber: 1}, Role: model.RoleViewer}}, nil)
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusForbidden,
+				Message: codes.GetErr(codes.ErrRoleNotAllowed),
+				Data:    nil,
+			},
+		},
+		{
+			name: "Failure :: db err",
+			setup: func() (datasource.DataSourceI, authentication.JWTService, config.MsgQueue, config.CookieStruct) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 1).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}), nil)
+				mockDs.EXPECT().CancelStandingOrder(int64(5), 1).Times(1).Return(errors.New(""))
+				return mockDs, nil, config.MsgQueue{}, config.CookieStruct{}
+			},
+			want: &respModel.Response{
+				Status:  http.StatusInternalServerError,
+				Message: codes.GetErr(codes.ErrCancelStandingOrder),
+				Data:    nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, jwt, msgQueue, cookie := tt.setup()
+			rec := NewAccountManagmentSvcLogic(ds, jwt, msgQueue, config.Config{Cookie: cookie, Currency: testCurrency})
+
+			got := rec.CancelStandingOrder("123", 1, 5)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_RunStandingOrders(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	now := time.Date(2022, 2, 1, 9, 0, 30, 0, time.UTC)
+	nextRun := time.Date(2022, 3, 1, 9, 0, 0, 0, time.UTC)
+	order := model.StandingOrder{Id: 5, AccountNumber: 1, Amount: 2500, TransactionType: "debit", Schedule: "0 9 1 * *", NextRun: now.Truncate(time.Minute), Status: model.StandingOrderActive}
+	endsToday := order
+	endDate := now.Add(time.Hour)
+	endsToday.EndDate = &endDate
+	tests := []struct {
+		name  string
+		setup func() datasource.DataSourceI
+	}{
+		{
+			name: "Success :: posts the due order",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetDueStandingOrders(now, standingOrderBatch).Times(1).Return([]model.StandingOrder{order}, nil)
+				mockDs.EXPECT().AdvanceStandingOrder(order, nextRun, model.StandingOrderActive).Times(1).Return(true, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 2500, TransactionType: "debit", Reference: "standing order 5"}).Times(1).Return(int64(11), nil)
+				mockDs.EXPECT().RecordStandingOrderRun(int64(5), now, int64(11), "").Times(1).Return(nil)
+				return mockDs
+			},
+		},
+		{
+			name: "Success :: last run records the failure and completes the order",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetDueStandingOrders(now, standingOrderBatch).Times(1).Return([]model.StandingOrder{endsToday}, nil)
+				mockDs.EXPECT().AdvanceStandingOrder(endsToday, nextRun, model.StandingOrderCompleted).Times(1).Return(true, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(gomock.Any()).Times(1).Return(int64(0), datasource.ErrInsufficientFunds)
+				mockDs.EXPECT().RecordStandingOrderRun(int64(5), now, int64(0), codes.GetErr(codes.ErrInsufficientFunds)).Times(1).Return(nil)
+				return mockDs
+			},
+		},
+		{
+			name: "Success :: run already taken by another instance",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetDueStandingOrders(now, standingOrderBatch).Times(1).Return([]model.StandingOrder{order}, nil)
+				mockDs.EXPECT().AdvanceStandingOrder(order, nextRun, model.StandingOrderActive).Times(1).Return(false, nil)
+				return mockDs
+			},
+		},
+		{
+			name: "Failure :: db err fetching due orders",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetDueStandingOrders(now, standingOrderBatch).Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+		},
+		{
+			name: "Failure :: db err advancing stops the run",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetDueStandingOrders(now, standingOrderBatch).Times(1).Return([]model.StandingOrder{order, endsToday}, nil)
+				mockDs.EXPECT().AdvanceStandingOrder(order, nextRun, model.StandingOrderActive).Times(1).Return(false, errors.New(""))
+				return mockDs
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			rec.RunStandingOrders(now)
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_BudgetAlerts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	budgets := []model.Budget{{Id: 2, AccountNumber: 1, Limit: 100000}, {Id: 3, AccountNumber: 1, Category: "groceries", Limit: 10000}}
+	account := []model.Account{{Id: "123", AccountNumber: 1, Currency: "USD"}}
+	tests := []struct {
+		name        string
+		transaction model.UpdateTransaction
+		fees        config.FeeCfg
+		setup       func() (datasource.DataSourceI, config.MsgQueue)
+		receipt     model.TransactionReceipt
+		wantAlerts  []model.BudgetAlert
+	}{
+		{
+			name:        "Success :: category budget reaches 80%",
+			transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 4210, TransactionType: "debit", Category: "Groceries"},
+			setup: func() (datasource.DataSourceI, config.MsgQueue) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 4210, TransactionType: "debit", Category: "groceries"}).Times(1).Return(int64(9), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(budgets, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(account, nil)
+				mockDs.EXPECT().GetTransactionTotals(1, gomock.Any(), gomock.Any()).Times(1).Return([]model.TransactionTotals{
+					{Category: "", Spends: 20000},
+					{Category: "groceries", Spends: 8500},
+				}, nil)
+				return mockDs, config.MsgQueue{BudgetAlertPubId: "pub", BudgetAlertChannel: "budget.alert.channel"}
+			},
+			wantAlerts: []model.BudgetAlert{{UserId: "123", AccountNumber: 1, BudgetId: 3, Category: "groceries", Threshold: 80, Limit: 10000, Spent: 8500, Currency: "USD", TransactionId: 9}},
+		},
+		{
+			name:        "Success :: overall budget exceeded",
+			transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 30000, TransactionType: "debit"},
+			setup: func() (datasource.DataSourceI, config.MsgQueue) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 30000, TransactionType: "debit"}).Times(1).Return(int64(9), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(budgets, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(account, nil)
+				mockDs.EXPECT().GetTransactionTotals(1, gomock.Any(), gomock.Any()).Times(1).Return([]model.TransactionTotals{
+					{Category: "", Spends: 95000},
+					{Category: "groceries", Spends: 7000},
+				}, nil)
+				return mockDs, config.MsgQueue{BudgetAlertPubId: "pub", BudgetAlertChannel: "budget.alert.channel"}
+			},
+			wantAlerts: []model.BudgetAlert{{UserId: "123", AccountNumber: 1, BudgetId: 2, Threshold: 100, Limit: 100000, Spent: 102000, Currency: "USD", TransactionId: 9}},
+		},
+		{
+			name:        "Success :: overall budget reaches 80% with the fee",
+			transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"},
+			fees:        config.FeeCfg{Rules: []config.FeeRule{{Name: "card fee", TransactionType: "debit", Type: "percentage", Amount: "1.5"}}},
+			setup: func() (datasource.DataSourceI, config.MsgQueue) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(account, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransactionWithFees(model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"}, model.Transaction{AccountNumber: 1, Amount: 150, Currency: "USD", TransactionType: "debit", Reference: "card fee", Category: "fees", Contra: model.LedgerFees}).Times(1).Return([]int64{7, 8}, nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(budgets, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(account, nil)
+				mockDs.EXPECT().GetTransactionTotals(1, gomock.Any(), gomock.Any()).Times(1).Return([]model.TransactionTotals{
+					{Category: "", Spends: 80000},
+					{Category: "fees", Spends: 10050},
+				}, nil)
+				return mockDs, config.MsgQueue{BudgetAlertPubId: "pub", BudgetAlertChannel: "budget.alert.channel"}
+			},
+			receipt:    model.TransactionReceipt{TransactionId: 7, FeeTransactionIds: []int64{8}},
+			wantAlerts: []model.BudgetAlert{{UserId: "123", AccountNumber: 1, BudgetId: 2, Threshold: 80, Limit: 100000, Spent: 90050, Currency: "USD", TransactionId: 7}},
+		},
+		{
+			name:        "Success :: threshold already passed",
+			transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 100, TransactionType: "debit", Category: "groceries"},
+			setup: func() (datasource.DataSourceI, config.MsgQueue) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 100, TransactionType: "debit", Category: "groceries"}).Times(1).Return(int64(9), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(budgets, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(account, nil)
+				mockDs.EXPECT().GetTransactionTotals(1, gomock.Any(), gomock.Any()).Times(1).Return([]model.TransactionTotals{{Category: "groceries", Spends: 9000}}, nil)
+				return mockDs, config.MsgQueue{BudgetAlertPubId: "pub", BudgetAlertChannel: "budget.alert.channel"}
+			},
+		},
+		{
+			name:        "Success :: no budgets",
+			transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 100, TransactionType: "debit"},
+			setup: func() (datasource.DataSourceI, config.MsgQueue) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 100, TransactionType: "debit"}).Times(1).Return(int64(9), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(nil, nil)
+				return mockDs, config.MsgQueue{BudgetAlertPubId: "pub", BudgetAlertChannel: "budget.alert.channel"}
+			},
+		},
+		{
+			name:        "Success :: credits are not checked",
+			transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 100, TransactionType: "credit"},
+			setup: func() (datasource.DataSourceI, config.MsgQueue) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 100, TransactionType: "credit"}).Times(1).Return(int64(9), nil)
+				return mockDs, config.MsgQueue{BudgetAlertPubId: "pub", BudgetAlertChannel: "budget.alert.channel"}
+			},
+		},
+		{
+			name:        "Success :: db err fetching budgets",
+			transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 100, TransactionType: "debit"},
+			setup: func() (datasource.DataSourceI, config.MsgQueue) {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 100, TransactionType: "debit"}).Times(1).Return(int64(9), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(nil, errors.New(""))
+				return mockDs, config.MsgQueue{BudgetAlertPubId: "pub", BudgetAlertChannel: "budget.alert.channel"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, msgQueue := tt.setup()
+			var alerts []model.BudgetAlert
+			broker := mock.NewMockMsgBrokerSvcI(mockCtrl)
+			broker.EXPECT().PushMsg(gomock.Any(), "pub", "budget.alert.channel").Times(len(tt.wantAlerts)).DoAndReturn(func(msg string, pubId string, channel string) error {
+				var alert model.BudgetAlert
+				if err := json.Unmarshal([]byte(msg), &alert); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				alerts = append(alerts, alert)
+				return nil
+			})
+			msgQueue.MsgBroker = broker
+			rec := NewAccountManagmentSvcLogic(ds, nil, msgQueue, config.Config{Currency: testCurrency, Fees: tt.fees})
+
+			got := rec.UpdateTransaction(tt.transaction)
+
+			receipt := model.TransactionReceipt{TransactionId: 9}
+			if tt.receipt.TransactionId != 0 {
+				receipt = tt.receipt
+			}
+			want := &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: receipt}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Want: %v, Got: %v", want, got)
+			}
+			month := time.Now().UTC().Format("2006-01")
+			for i := range tt.wantAlerts {
+				tt.wantAlerts[i].Month = month
+			}
+			if !reflect.DeepEqual(alerts, tt.wantAlerts) {
+				t.Errorf("Want: %v, Got: %v", tt.wantAlerts, alerts)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_Budgets(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	account := []model.Account{{Id: "123", AccountNumber: 1, Currency: "USD"}}
+	budgets := []model.Budget{{Id: 2, AccountNumber: 1, Limit: 100000}, {Id: 3, AccountNumber: 1, Category: "groceries", Limit: 10000}}
+	tests := []struct {
+		name  string
+		call  func(l AccountManagmentSvcLogicIer) *respModel.Response
+		setup func() datasource.DataSourceI
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: create",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CreateBudget("123", 0, model.NewBudget{Category: " Groceries", Limit: 10000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().InsertBudget(model.Budget{AccountNumber: 1, Category: "groceries", Limit: 10000}).Times(1).Return(int64(3), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusCreated, Message: "SUCCESS", Data: budgets[1]},
+		},
+		{
+			name: "Failure :: create :: invalid limit",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CreateBudget("123", 0, model.NewBudget{Limit: 0})
+			},
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrInvalidAmount), Data: nil},
+		},
+		{
+			name: "Failure :: create :: category already budgeted",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CreateBudget("123", 0, model.NewBudget{Category: "groceries", Limit: 10000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().InsertBudget(gomock.Any()).Times(1).Return(int64(0), datasource.ErrBudgetExists)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrBudgetExists), Data: nil},
+		},
+		{
+			name: "Failure :: create :: account not found",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CreateBudget("123", 0, model.NewBudget{Limit: 10000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.AccNotFound), Data: nil},
+		},
+		{
+			name: "Failure :: create :: db err",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CreateBudget("123", 0, model.NewBudget{Limit: 10000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().InsertBudget(gomock.Any()).Times(1).Return(int64(0), errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrCreatingBudget), Data: nil},
+		},
+		{
+			name: "Success :: list",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Budgets("123", 0)
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(budgets, nil)
+				mockDs.EXPECT().GetTransactionTotals(1, gomock.Any(), gomock.Any()).Times(1).Return([]model.TransactionTotals{{Category: "groceries", Spends: 4000}, {Category: "rent", Spends: 50000}}, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: []model.BudgetUsage{
+				{Budget: budgets[0], Month: time.Now().UTC().Format("2006-01"), Spent: 54000},
+				{Budget: budgets[1], Month: time.Now().UTC().Format("2006-01"), Spent: 4000},
+			}},
+		},
+		{
+			name: "Success :: list :: default account of the user",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Budgets("123", 0)
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 1}, model.Account{Id: "123", AccountNumber: 2, Default: true}), nil)
+				mockDs.EXPECT().GetBudgets(2).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: []model.BudgetUsage{}},
+		},
+		{
+			name: "Success :: list :: named account of the user",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Budgets("123", 2)
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 2).Times(1).Return(owned(model.Account{Id: "123", AccountNumber: 2}), nil)
+				mockDs.EXPECT().GetBudgets(2).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: []model.BudgetUsage{}},
+		},
+		{
+			name: "Failure :: list :: account of another user",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Budgets("123", 7)
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 7).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.AccNotFound), Data: nil},
+		},
+		{
+			name: "Success :: list :: no budgets",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Budgets("123", 0)
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: []model.BudgetUsage{}},
+		},
+		{
+			name: "Failure :: list :: db err fetching spends",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.Budgets("123", 0)
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(budgets, nil)
+				mockDs.EXPECT().GetTransactionTotals(1, gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrFetchingBudgets), Data: nil},
+		},
+		{
+			name: "Success :: update",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateBudget("123", 0, 3, model.UpdateBudget{Limit: 20000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(budgets, nil)
+				mockDs.EXPECT().UpdateBudget(model.Budget{Id: 3, AccountNumber: 1, Category: "groceries", Limit: 20000}).Times(1).Return(nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.Budget{Id: 3, AccountNumber: 1, Category: "groceries", Limit: 20000}},
+		},
+		{
+			name: "Failure :: update :: budget of another account",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateBudget("123", 0, 7, model.UpdateBudget{Limit: 20000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(budgets, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusNotFound, Message: codes.GetErr(codes.BudgetNotFound), Data: nil},
+		},
+		{
+			name: "Failure :: update :: db err",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.UpdateBudget("123", 0, 3, model.UpdateBudget{Limit: 20000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().GetBudgets(1).Times(1).Return(budgets, nil)
+				mockDs.EXPECT().UpdateBudget(gomock.Any()).Times(1).Return(errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrUpdatingBudget), Data: nil},
+		},
+		{
+			name: "Success :: delete",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.DeleteBudget("123", 0, 3)
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().DeleteBudget(int64(3), 1).Times(1).Return(nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: nil},
+		},
+		{
+			name: "Failure :: delete :: not found",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.DeleteBudget("123", 0, 3)
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetMemberAccounts("123", 0).Times(1).Return(owned(account...), nil)
+				mockDs.EXPECT().DeleteBudget(int64(3), 1).Times(1).Return(datasource.ErrBudgetNotFound)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusNotFound, Message: codes.GetErr(codes.BudgetNotFound), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			got := tt.call(rec)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_Holds(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	expiresOn := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	tooLate := time.Now().Add(maxHoldExpiry + time.Hour)
+	tests := []struct {
+		name  string
+		call  func(l AccountManagmentSvcLogicIer) *respModel.Response
+		setup func() datasource.DataSourceI
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: place",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.PlaceHold(model.NewHold{AccountNumber: 1, Amount: 6000, Reference: "card auth", Category: " Travel", Merchant: " hotel ", ExpiresOn: &expiresOn})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertHold(model.SpendLimits{}, gomock.Any(), model.Hold{AccountNumber: 1, Amount: 6000, Reference: "card auth", Category: "travel", Merchant: "hotel", ExpiresOn: expiresOn}).Times(1).Return(int64(5), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.HoldReceipt{HoldId: 5, ExpiresOn: expiresOn}},
+		},
+		{
+			name: "Failure :: place :: invalid amount",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.PlaceHold(model.NewHold{AccountNumber: 1, Amount: -1})
+			},
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrInvalidAmount), Data: nil},
+		},
+		{
+			name: "Failure :: place :: expiry too far out",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.PlaceHold(model.NewHold{AccountNumber: 1, Amount: 6000, ExpiresOn: &tooLate})
+			},
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrInvalidHoldExpiry), Data: nil},
+		},
+		{
+			name: "Failure :: place :: insufficient funds",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.PlaceHold(model.NewHold{AccountNumber: 1, Amount: 6000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().InsertHold(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(int64(0), datasource.ErrInsufficientFunds)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusUnprocessableEntity, Message: codes.GetErr(codes.ErrInsufficientFunds), Data: nil},
+		},
+		{
+			name: "Success :: capture",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CaptureHold(model.CaptureHold{HoldId: 5, Amount: 4500})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetHold(int64(5)).Times(1).Return(model.Hold{Id: 5, AccountNumber: 1, Amount: 6000, Status: model.HoldPending}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().CaptureHold(model.SpendLimits{}, gomock.Any(), int64(5), model.Money(4500), "", gomock.Any()).Times(1).Return(model.Transaction{Id: 9, AccountNumber: 1, Amount: 4500, TransactionType: "debit"}, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: model.TransactionReceipt{TransactionId: 9}},
+		},
+		{
+			name: "Failure :: capture :: exceeds hold",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CaptureHold(model.CaptureHold{HoldId: 5, Amount: 7000})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetHold(int64(5)).Times(1).Return(model.Hold{Id: 5, AccountNumber: 1, Amount: 6000, Status: model.HoldPending}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().CaptureHold(model.SpendLimits{}, gomock.Any(), int64(5), model.Money(7000), "", gomock.Any()).Times(1).Return(model.Transaction{}, datasource.ErrCaptureExceedsHold)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrCaptureExceedsHold), Data: nil},
+		},
+		{
+			name: "Failure :: capture :: expired",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CaptureHold(model.CaptureHold{HoldId: 5})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetHold(int64(5)).Times(1).Return(model.Hold{Id: 5, AccountNumber: 1, Amount: 6000, Status: model.HoldPending}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().CaptureHold(model.SpendLimits{}, gomock.Any(), int64(5), model.Money(0), "", gomock.Any()).Times(1).Return(model.Transaction{}, datasource.ErrHoldExpired)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrHoldExpired), Data: nil},
+		},
+		{
+			name: "Failure :: capture :: db err",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CaptureHold(model.CaptureHold{HoldId: 5})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetHold(int64(5)).Times(1).Return(model.Hold{Id: 5, AccountNumber: 1, Amount: 6000, Status: model.HoldPending}, nil)
+				mockDs.EXPECT().GetSpendLimits(1).Times(1).Return(nil, nil)
+				mockDs.EXPECT().CaptureHold(model.SpendLimits{}, gomock.Any(), int64(5), model.Money(0), "", gomock.Any()).Times(1).Return(model.Transaction{}, errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrCapturingHold), Data: nil},
+		},
+		{
+			name: "Failure :: capture :: not found",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.CaptureHold(model.CaptureHold{HoldId: 5})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetHold(int64(5)).Times(1).Return(model.Hold{}, datasource.ErrHoldNotFound)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusNotFound, Message: codes.GetErr(codes.HoldNotFound), Data: nil},
+		},
+		{
+			name: "Success :: release",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.ReleaseHold(model.ReleaseHold{HoldId: 5})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReleaseHold(int64(5), model.HoldReleased).Times(1).Return(nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusAccepted, Message: "SUCCESS", Data: nil},
+		},
+		{
+			name: "Failure :: release :: not found",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.ReleaseHold(model.ReleaseHold{HoldId: 5})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReleaseHold(int64(5), model.HoldReleased).Times(1).Return(datasource.ErrHoldNotFound)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusNotFound, Message: codes.GetErr(codes.HoldNotFound), Data: nil},
+		},
+		{
+			name: "Failure :: release :: already captured",
+			call: func(l AccountManagmentSvcLogicIer) *respModel.Response {
+				return l.ReleaseHold(model.ReleaseHold{HoldId: 5})
+			},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().ReleaseHold(int64(5), model.HoldReleased).Times(1).Return(datasource.ErrHoldNotPending)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusConflict, Message: codes.GetErr(codes.ErrHoldNotPending), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			got := tt.call(rec)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_ExpireHolds(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	now := time.Date(2022, 2, 1, 9, 0, 0, 0, time.UTC)
+	expired := []model.Hold{{Id: 4, AccountNumber: 1, Status: model.HoldPending}, {Id: 5, AccountNumber: 2, Status: model.HoldPending}}
+	tests := []struct {
+		name  string
+		setup func() datasource.DataSourceI
+	}{
+		{
+			name: "Success :: releases expired holds",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetExpiredHolds(now, holdExpiryBatch).Times(1).Return(expired, nil)
+				mockDs.EXPECT().ReleaseHold(int64(4), model.HoldExpired).Times(1).Return(nil)
+				mockDs.EXPECT().ReleaseHold(int64(5), model.HoldExpired).Times(1).Return(nil)
+				return mockDs
+			},
+		},
+		{
+			name: "Success :: hold captured in the meantime is skipped",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetExpiredHolds(now, holdExpiryBatch).Times(1).Return(expired, nil)
+				mockDs.EXPECT().ReleaseHold(int64(4), model.HoldExpired).Times(1).Return(datasource.ErrHoldNotPending)
+				mockDs.EXPECT().ReleaseHold(int64(5), model.HoldExpired).Times(1).Return(nil)
+				return mockDs
+			},
+		},
+		{
+			name: "Failure :: db err releasing stops the run",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetExpiredHolds(now, holdExpiryBatch).Times(1).Return(expired, nil)
+				mockDs.EXPECT().ReleaseHold(int64(4), model.HoldExpired).Times(1).Return(errors.New(""))
+				return mockDs
+			},
+		},
+		{
+			name: "Failure :: db err fetching expired holds",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetExpiredHolds(now, holdExpiryBatch).Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			rec.ExpireHolds(now)
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_PurgeIdempotencyKeys(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	before := time.Date(2022, 1, 31, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		setup func() datasource.DataSourceI
+	}{
+		{
+			name: "Success :: purges expired keys",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().DeleteExpiredIdempotencyKeys(before).Times(1).Return(int64(3), nil)
+				return mockDs
+			},
+		},
+		{
+			name: "Success :: nothing to purge",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().DeleteExpiredIdempotencyKeys(before).Times(1).Return(int64(0), nil)
+				return mockDs
+			},
+		},
+		{
+			name: "Failure :: db err",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().DeleteExpiredIdempotencyKeys(before).Times(1).Return(int64(0), errors.New(""))
+				return mockDs
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			rec.PurgeIdempotencyKeys(before)
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_AccrueInterest(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	interestCfg := config.InterestCfg{Rates: map[string]string{"savings": "3.65", "current": "0"}, DayCount: "act/365"}
+	now := time.Date(2022, 2, 1, 9, 0, 0, 0, time.UTC)
+	jan := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	janFilter := model.TransactionFilter{AccountNumber: 1, From: jan, To: now.Truncate(24 * time.Hour).Add(-time.Nanosecond)}
+	accounts := []model.Account{
+		{AccountNumber: 1, Currency: "USD", AccountType: "savings", CreatedOn: jan.AddDate(-1, 0, 0), Status: model.AccountActive},
+		{AccountNumber: 2, Currency: "USD", AccountType: "savings", CreatedOn: jan.AddDate(-1, 0, 0), Status: model.AccountFrozen},
+		{AccountNumber: 3, Currency: "USD", AccountType: "savings", CreatedOn: now.Add(-time.Hour), Status: model.AccountActive},
+		{AccountNumber: 4, Currency: "USD", AccountType: "savings", CreatedOn: jan.AddDate(-1, 0, 0), Status: model.AccountClosed},
+		{AccountNumber: 5, Currency: "USD", AccountType: "savings", CreatedOn: jan.AddDate(-1, 0, 0), Status: model.AccountPending},
+	}
+	tests := []struct {
+		name  string
+		setup func() datasource.DataSourceI
+	}{
+		{
+			name: "Success :: posts the months not paid yet",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetInterestAccruals("2022-01").Times(1).Return([]model.InterestAccrual{{AccountNumber: 2, Period: "2022-01"}}, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_type": "savings"}).Times(1).Return(accounts, nil)
+				mockDs.EXPECT().GetBalance(1, jan).Times(1).Return(model.Money(100000), nil)
+				mockDs.EXPECT().GetTransactions(janFilter).Times(1).Return(nil, nil)
+				mockDs.EXPECT().PostInterest(model.InterestAccrual{AccountNumber: 1, Period: "2022-01", Currency: "USD", Rate: "3.65", DayCount: "act/365", Amount: 310}).Times(1).Return(int64(9), nil)
+				return mockDs
+			},
+		},
+		{
+			name: "Success :: month posted by another instance",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetInterestAccruals("2022-01").Times(1).Return(nil, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_type": "savings"}).Times(1).Return(accounts[:2], nil)
+				mockDs.EXPECT().GetBalance(gomock.Any(), jan).Times(2).Return(model.Money(0), nil)
+				mockDs.EXPECT().GetTransactions(gomock.Any()).Times(2).Return(nil, nil)
+				mockDs.EXPECT().PostInterest(gomock.Any()).Times(2).Return(int64(0), datasource.ErrInterestPosted)
+				return mockDs
+			},
+		},
+		{
+			name: "Failure :: db err fetching posted months",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetInterestAccruals("2022-01").Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+		},
+		{
+			name: "Failure :: db err reading the ledger stops the run",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetInterestAccruals("2022-01").Times(1).Return(nil, nil)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_type": "savings"}).Times(1).Return(accounts, nil)
+				mockDs.EXPECT().GetBalance(1, jan).Times(1).Return(model.Money(0), errors.New(""))
+				return mockDs
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency, Interest: interestCfg})
+
+			rec.AccrueInterest(now)
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_InterestReport(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	interestCfg := config.InterestCfg{Rates: map[string]string{"savings": "3.65"}, DayCount: "act/365"}
+	jan := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	account := []model.Account{{AccountNumber: 1, Currency: "USD", AccountType: "savings", CreatedOn: jan.AddDate(-1, 0, 0)}}
+	expectMonth := func(mockDs *mock.MockDataSourceI, month time.Time, end time.Time) {
+		mockDs.EXPECT().GetBalance(1, month).Times(1).Return(model.Money(100000), nil)
+		mockDs.EXPECT().GetTransactions(model.TransactionFilter{AccountNumber: 1, From: month, To: end.Add(-time.Nanosecond)}).Times(1).Return(nil, nil)
+	}
+	tests := []struct {
+		name  string
+		from  time.Time
+		to    time.Time
+		post  bool
+		setup func() datasource.DataSourceI
+		want  *respModel.Response
+	}{
+		{
+			name: "Success :: dry run compares posted months",
+			from: jan,
+			to:   feb,
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(account, nil)
+				mockDs.EXPECT().GetInterestAccruals("2022-01").Times(1).Return([]model.InterestAccrual{{AccountNumber: 1, Period: "2022-01", Rate: "3.65", DayCount: "act/360", Amount: 300, TransactionId: 9}}, nil)
+				expectMonth(mockDs, jan, feb)
+				mockDs.EXPECT().GetInterestAccruals("2022-02").Times(1).Return(nil, nil)
+				expectMonth(mockDs, feb, mar)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: []model.InterestReportLine{
+				{InterestAccrual: model.InterestAccrual{AccountNumber: 1, Period: "2022-01", Currency: "USD", Rate: "3.65", DayCount: "act/360", Amount: 314, TransactionId: 9}, Status: model.InterestMismatch, PostedAmount: 300},
+				{InterestAccrual: model.InterestAccrual{AccountNumber: 1, Period: "2022-02", Currency: "USD", Rate: "3.65", DayCount: "act/365", Amount: 280}, Status: model.InterestUnposted},
+			}},
+		},
+		{
+			name: "Success :: posts the months not paid yet",
+			from: feb,
+			to:   feb,
+			post: true,
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(account, nil)
+				mockDs.EXPECT().GetInterestAccruals("2022-02").Times(1).Return(nil, nil)
+				expectMonth(mockDs, feb, mar)
+				mockDs.EXPECT().PostInterest(model.InterestAccrual{AccountNumber: 1, Period: "2022-02", Currency: "USD", Rate: "3.65", DayCount: "act/365", Amount: 280}).Times(1).Return(int64(11), nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: []model.InterestReportLine{
+				{InterestAccrual: model.InterestAccrual{AccountNumber: 1, Period: "2022-02", Currency: "USD", Rate: "3.65", DayCount: "act/365", Amount: 280, TransactionId: 11}, Status: model.InterestPosted, PostedAmount: 280},
+			}},
+		},
+		{
+			name: "Failure :: window ends in the current month",
+			from: jan,
+			to:   time.Now(),
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrInvalidQuery), Data: nil},
+		},
+		{
+			name: "Failure :: window ends before it starts",
+			from: feb,
+			to:   jan,
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrInvalidQuery), Data: nil},
+		},
+		{
+			name: "Failure :: account not found",
+			from: jan,
+			to:   jan,
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.AccNotFound), Data: nil},
+		},
+		{
+			name: "Failure :: db err",
+			from: jan,
+			to:   jan,
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().Get(map[string]interface{}{"account_number": 1}).Times(1).Return(account, nil)
+				mockDs.EXPECT().GetInterestAccruals("2022-01").Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrInterestReport), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency, Interest: interestCfg})
+
+			got := rec.InterestReport(tt.from, tt.to, 1, tt.post)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_Reconcile(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	drifted := model.ReconciliationLine{AccountNumber: 1, Status: model.ReconcileMismatch, Income: 10000, Spends: 500, LedgerIncome: 10000, LedgerSpends: 700}
+	repaired := drifted
+	repaired.Status = model.ReconcileRepaired
+	trialBalance := []model.TrialBalanceLine{
+		{Currency: "USD", Debits: 700, Credits: 10000},
+		{Account: model.LedgerSuspense, Currency: "USD", Debits: 10000, Credits: 700},
+	}
+	journal := []model.JournalLine{{Currency: "USD", Accounts: 9500, Journal: 9300}}
+	tests := []struct {
+		name          string
+		accountNumber int
+		repair        bool
+		setup         func() datasource.DataSourceI
+		want          *respModel.Response
+	}{
+		{
+			name: "Success :: report only",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(0).Times(1).Return([]model.ReconciliationLine{drifted}, nil)
+				mockDs.EXPECT().GetTrialBalance().Times(1).Return(trialBalance, nil)
+				mockDs.EXPECT().GetJournalMismatches().Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: model.ReconciliationReport{Mismatches: []model.ReconciliationLine{drifted}, JournalMismatches: []model.JournalLine{}, Balanced: true, TrialBalance: trialBalance}},
+		},
+		{
+			name:          "Success :: repair",
+			accountNumber: 1,
+			repair:        true,
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(1).Times(1).Return([]model.ReconciliationLine{drifted}, nil)
+				mockDs.EXPECT().RepairTotals(1).Times(1).Return(repaired, nil)
+				mockDs.EXPECT().GetTrialBalance().Times(1).Return(trialBalance, nil)
+				mockDs.EXPECT().GetJournalMismatches().Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: model.ReconciliationReport{Mismatches: []model.ReconciliationLine{repaired}, JournalMismatches: []model.JournalLine{}, Balanced: true, TrialBalance: trialBalance}},
+		},
+		{
+			name: "Success :: no mismatch",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(0).Times(1).Return(nil, nil)
+				mockDs.EXPECT().GetTrialBalance().Times(1).Return(nil, nil)
+				mockDs.EXPECT().GetJournalMismatches().Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: model.ReconciliationReport{Mismatches: []model.ReconciliationLine{}, JournalMismatches: []model.JournalLine{}, Balanced: true}},
+		},
+		{
+			name: "Success :: journal out of balance",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(0).Times(1).Return(nil, nil)
+				mockDs.EXPECT().GetTrialBalance().Times(1).Return(trialBalance[:1], nil)
+				mockDs.EXPECT().GetJournalMismatches().Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: model.ReconciliationReport{Mismatches: []model.ReconciliationLine{}, JournalMismatches: []model.JournalLine{}, TrialBalance: trialBalance[:1]}},
+		},
+		{
+			name: "Success :: journal out of line with the accounts",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(0).Times(1).Return(nil, nil)
+				mockDs.EXPECT().GetTrialBalance().Times(1).Return(trialBalance, nil)
+				mockDs.EXPECT().GetJournalMismatches().Times(1).Return(journal, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: model.ReconciliationReport{Mismatches: []model.ReconciliationLine{}, JournalMismatches: journal, TrialBalance: trialBalance}},
+		},
+		{
+			name: "Failure :: journal db err",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(0).Times(1).Return(nil, nil)
+				mockDs.EXPECT().GetTrialBalance().Times(1).Return(trialBalance, nil)
+				mockDs.EXPECT().GetJournalMismatches().Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrReconciliation), Data: nil},
+		},
+		{
+			name: "Failure :: trial balance db err",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(0).Times(1).Return(nil, nil)
+				mockDs.EXPECT().GetTrialBalance().Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrReconciliation), Data: nil},
+		},
+		{
+			name: "Failure :: db err",
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(0).Times(1).Return(nil, errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrReconciliation), Data: nil},
+		},
+		{
+			name:   "Success :: account without ledger entries is not repaired",
+			repair: true,
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(0).Times(1).Return([]model.ReconciliationLine{drifted}, nil)
+				mockDs.EXPECT().RepairTotals(1).Times(1).Return(drifted, datasource.ErrNoLedgerEntries)
+				mockDs.EXPECT().GetTrialBalance().Times(1).Return(trialBalance, nil)
+				mockDs.EXPECT().GetJournalMismatches().Times(1).Return(nil, nil)
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusOK, Message: "SUCCESS", Data: model.ReconciliationReport{Mismatches: []model.ReconciliationLine{drifted}, JournalMismatches: []model.JournalLine{}, Balanced: true, TrialBalance: trialBalance}},
+		},
+		{
+			name:   "Failure :: repair db err",
+			repair: true,
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().GetLedgerMismatches(0).Times(1).Return([]model.ReconciliationLine{drifted}, nil)
+				mockDs.EXPECT().RepairTotals(1).Times(1).Return(model.ReconciliationLine{}, errors.New(""))
+				return mockDs
+			},
+			want: &respModel.Response{Status: http.StatusInternalServerError, Message: codes.GetErr(codes.ErrReconciliation), Data: nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			got := rec.Reconcile(tt.accountNumber, tt.repair)
+			if report, ok := got.Data.(model.ReconciliationReport); ok {
+				if report.CheckedOn.IsZero() {
+					t.Errorf("Want: check time, Got: %v", report.CheckedOn)
+				}
+				report.CheckedOn = time.Time{}
+				got.Data = report
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountManagmentSvcLogic_ImportTransactions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	credit := model.ImportRow{Line: 2, Transaction: model.UpdateTransaction{AccountNumber: 1, Amount: 1000, TransactionType: "credit", Reference: "refund"}}
+	debit := model.ImportRow{Line: 3, Transaction: model.UpdateTransaction{AccountNumber: 2, Amount: 500, TransactionType: "debit"}}
+	invalid := model.ImportRow{Line: 4, Transaction: model.UpdateTransaction{AccountNumber: 1}, Err: "amount cannot be negative"}
+	creditTx := model.Transaction{AccountNumber: 1, Amount: 1000, TransactionType: "credit", Reference: "refund"}
+	debitTx := model.Transaction{AccountNumber: 2, Amount: 500, TransactionType: "debit"}
+	result := func(row model.ImportRow, status string, id int64, err string) model.ImportRowResult {
+		return model.ImportRowResult{Line: row.Line, AccountNumber: row.Transaction.AccountNumber, Amount: row.Transaction.Amount, TransactionType: row.Transaction.TransactionType, Reference: row.Transaction.Reference, Status: status, TransactionId: id, Error: err}
+	}
+	many := make([]model.ImportRow, importBatchSize+1)
+	for i := range many {
+		many[i] = credit
+	}
+	tests := []struct {
+		name   string
+		rows   []model.ImportRow
+		dryRun bool
+		setup  func() datasource.DataSourceI
+		want   func(report model.ImportReport) bool
+		report *model.ImportReport
+	}{
+		{
+			name:   "Success :: dry run",
+			rows:   []model.ImportRow{credit, invalid},
+			dryRun: true,
+			setup: func() datasource.DataSourceI {
+				return mock.NewMockDataSourceI(mockCtrl)
+			},
+			report: &model.ImportReport{DryRun: true, Rows: 2, Valid: 1, Failed: 1, Results: []model.ImportRowResult{
+				result(credit, model.ImportValid, 0, ""),
+				result(invalid, model.ImportInvalid, 0, "amount cannot be negative"),
+			}},
+		},
+		{
+			name: "Success :: batch applied",
+			rows: []model.ImportRow{credit, invalid, debit},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertTransactions(creditTx, debitTx).Times(1).Return([]int64{7, 8}, nil)
+				return mockDs
+			},
+			report: &model.ImportReport{Rows: 3, Valid: 2, Applied: 2, Failed: 1, Results: []model.ImportRowResult{
+				result(credit, model.ImportApplied, 7, ""),
+				result(invalid, model.ImportInvalid, 0, "amount cannot be negative"),
+				result(debit, model.ImportApplied, 8, ""),
+			}},
+		},
+		{
+			name: "Success :: failed batch posted row by row",
+			rows: []model.ImportRow{credit, debit},
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertTransactions(creditTx, debitTx).Times(1).Return(nil, datasource.ErrInsufficientFunds)
+				mockDs.EXPECT().InsertTransaction(creditTx).Times(1).Return(int64(7), nil)
+				mockDs.EXPECT().InsertTransaction(debitTx).Times(1).Return(int64(0), datasource.ErrInsufficientFunds)
+				return mockDs
+			},
+			report: &model.ImportReport{Rows: 2, Valid: 2, Applied: 1, Failed: 1, Results: []model.ImportRowResult{
+				result(credit, model.ImportApplied, 7, ""),
+				result(debit, model.ImportFailed, 0, codes.GetErr(codes.ErrInsufficientFunds)),
+			}},
+		},
+		{
+			name: "Success :: rows split into batches",
+			rows: many,
+			setup: func() datasource.DataSourceI {
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				ids := make([]int64, importBatchSize)
+				mockDs.EXPECT().InsertTransactions(gomock.Any()).Times(2).DoAndReturn(func(transactions ...model.Transaction) ([]int64, error) {
+					return ids[:len(transactions)], nil
+				})
+				return mockDs
+			},
+			want: func(report model.ImportReport) bool {
+				return report.Rows == importBatchSize+1 && report.Applied == importBatchSize+1 && report.Failed == 0
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewAccountManagmentSvcLogic(tt.setup(), nil, config.MsgQueue{}, config.Config{Currency: testCurrency})
+
+			got := rec.ImportTransactions(tt.rows, tt.dryRun)
+
+			report, ok := got.Data.(model.ImportReport)
+			if got.Status != http.StatusOK || !ok {
+				t.Errorf("Want: %v, Got: %v", http.StatusOK, got)
+				return
+			}
+			if tt.report != nil && !reflect.DeepEqual(report, *tt.report) {
+				t.Errorf("Want: %v, Got: %v", *tt.report, report)
+			}
+			if tt.want != nil && !tt.want(report) {
+				t.Errorf("Got: %v", report)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/PereRohit/util/log"
@@ -11,12 +13,19 @@ import (
 	svcCfg "github.com/vatsal278/AccountManagmentSvc/internal/config"
 	"github.com/vatsal278/AccountManagmentSvc/internal/model"
 	"github.com/vatsal278/AccountManagmentSvc/internal/repo/authentication"
+	"github.com/vatsal278/AccountManagmentSvc/internal/repo/datasource"
 	"github.com/vatsal278/AccountManagmentSvc/pkg/session"
 	"github.com/vatsal278/go-redis-cache"
 	"github.com/vatsal278/msgbroker/pkg/sdk"
 	"io"
 	"net/http"
 	"strings"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
 )
 
 type AccMgmtMiddleware struct {
@@ -24,6 +33,7 @@ type AccMgmtMiddleware struct {
 	jwt    authentication.JWTService
 	msg    func(io.ReadCloser) (string, error)
 	cacher redis.Cacher
+	ds     datasource.DataSourceI
 }
 
 type respWriterWithStatus struct {
@@ -50,6 +60,7 @@ func NewAccMgmtMiddleware(cfg *svcCfg.SvcConfig) *AccMgmtMiddleware {
 		jwt:    cfg.JwtSvc.JwtSvc,
 		msg:    msg,
 		cacher: cfg.Cacher.Cacher,
+		ds:     datasource.NewSql(cfg.DbSvc, cfg.Cfg.DataBase),
 	}
 }
 
@@ -96,6 +107,25 @@ func (u AccMgmtMiddleware) ExtractUser(next http.Handler) http.Handler {
 	})
 }
 
+// Admin only lets the users listed as admins in the config through, it reads the user set by ExtractUser.
+func (u AccMgmtMiddleware) Admin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := session.GetSession(r.Context()).(string)
+		if !ok {
+			response.ToJson(w, http.StatusBadRequest, codes.GetErr(codes.ErrAssertUserid), nil)
+			return
+		}
+		for _, admin := range u.cfg.Admins {
+			if admin == id {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		log.Error(fmt.Errorf("user %s is not an admin", id))
+		response.ToJson(w, http.StatusForbidden, codes.GetErr(codes.ErrAdminOnly), nil)
+	})
+}
+
 func (u AccMgmtMiddleware) ScreenRequest(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var urlMatch bool
@@ -180,3 +210,107 @@ func (u AccMgmtMiddleware) Cacher(requireAuth bool) func(http.Handler) http.Hand
 		})
 	}
 }
+
+// Idempotency persists the outcome of requests carrying an Idempotency-Key header and replays it
+// for retries of the same request within the configured retention window instead of executing them again.
+func (u AccMgmtMiddleware) Idempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		scope := r.Method + " " + r.URL.Path
+		id, ok := session.GetSession(r.Context()).(string)
+		if ok {
+			scope = scope + "/auth/" + id
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			log.Error(err)
+			response.ToJson(w, http.StatusInternalServerError, codes.GetErr(codes.ErrReadingReqBody), nil)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewBuffer(body))
+		hash := sha256.Sum256(body)
+		record := model.IdempotencyRecord{Key: key, Scope: scope, RequestHash: hex.EncodeToString(hash[:])}
+
+		reserved, existing, err := u.reserveIdempotencyKey(record)
+		if err != nil {
+			log.Error(err)
+			response.ToJson(w, http.StatusInternalServerError, codes.GetErr(codes.ErrIdempotencyKey), nil)
+			return
+		}
+		if !reserved {
+			replay(w, record, existing)
+			return
+		}
+
+		defer func() {
+			// a panicking handler reached no outcome either, the panic is raised again for net/http to handle
+			if p := recover(); p != nil {
+				u.releaseIdempotencyKey(record)
+				panic(p)
+			}
+		}()
+		hijackedWriter := &respWriterWithStatus{-1, "", w}
+		next.ServeHTTP(hijackedWriter, r)
+		if hijackedWriter.status < 200 || hijackedWriter.status >= 500 {
+			// the request did not reach a final outcome, release the key so that it can be retried
+			u.releaseIdempotencyKey(record)
+			return
+		}
+		record.Status = hijackedWriter.status
+		record.Response = hijackedWriter.response
+		record.ContentType = w.Header().Get("Content-Type")
+		err = u.ds.UpdateIdempotencyKey(record)
+		if err != nil {
+			log.Error(err)
+		}
+	})
+}
+
+// reserveIdempotencyKey reserves the key for this request, replacing a previous reservation which outlived the
+// retention window. When the key is already taken the existing record is returned instead.
+func (u AccMgmtMiddleware) reserveIdempotencyKey(record model.IdempotencyRecord) (bool, *model.IdempotencyRecord, error) {
+	reserved, err := u.ds.InsertIdempotencyKey(record)
+	if err != nil || reserved {
+		return reserved, nil, err
+	}
+	existing, err := u.ds.GetIdempotencyKey(record.Key, record.Scope)
+	if err != nil {
+		return false, nil, err
+	}
+	if existing == nil || time.Since(existing.CreatedOn) <= u.cfg.Idempotency.Time {
+		return false, existing, nil
+	}
+	err = u.ds.DeleteIdempotencyKey(record.Key, record.Scope)
+	if err != nil {
+		return false, nil, err
+	}
+	reserved, err = u.ds.InsertIdempotencyKey(record)
+	return reserved, nil, err
+}
+
+// releaseIdempotencyKey deletes the reservation of a request which reached no final outcome, so it can be retried.
+func (u AccMgmtMiddleware) releaseIdempotencyKey(record model.IdempotencyRecord) {
+	err := u.ds.DeleteIdempotencyKey(record.Key, record.Scope)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+func replay(w http.ResponseWriter, record model.IdempotencyRecord, existing *model.IdempotencyRecord) {
+	if existing == nil || existing.Status == 0 {
+		response.ToJson(w, http.StatusConflict, codes.GetErr(codes.ErrIdempotencyInProgress), nil)
+		return
+	}
+	if existing.RequestHash != record.RequestHash {
+		response.ToJson(w, http.StatusUnprocessableEntity, codes.GetErr(codes.ErrIdempotencyKeyReused), nil)
+		return
+	}
+	w.Header().Set("Content-Type", existing.ContentType)
+	w.Header().Set(IdempotencyReplayedHeader, "true")
+	w.WriteHeader(existing.Status)
+	w.Write([]byte(existing.Response))
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"github.com/PereRohit/util/model"
@@ -804,3 +805,300 @@ func TestUserMgmtMiddleware_Cacher(t *testing.T) {
 		})
 	}
 }
+func TestUserMgmtMiddleware_Idempotency(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	// sha256 of the request body `{"amount":1}`
+	hash := "c2b11e657e12fd177359627ca89412018e2274d0873cfbfcf1fc50f685582e9e"
+	// the panic a handler raised, after it went through the middleware
+	var recovered interface{}
+	tests := []struct {
+		name      string
+		config    config.Config
+		handler   http.HandlerFunc
+		setupFunc func() (*http.Request, *mock.MockDataSourceI)
+		validator func(*httptest.ResponseRecorder)
+	}{
+		{
+			name:    "SUCCESS::Idempotency::no key",
+			handler: test,
+			setupFunc: func() (*http.Request, *mock.MockDataSourceI) {
+				req := httptest.NewRequest(http.MethodPut, "http://localhost:80/update/transaction", bytes.NewBufferString(`{"amount":1}`))
+				return req, mock.NewMockDataSourceI(mockCtrl)
+			},
+			validator: func(res *httptest.ResponseRecorder) {
+				if hit != true {
+					t.Errorf("Want: %v, Got: %v", true, hit)
+				}
+			},
+		},
+		{
+			name:    "SUCCESS::Idempotency::first request is stored",
+			handler: test,
+			setupFunc: func() (*http.Request, *mock.MockDataSourceI) {
+				req := httptest.NewRequest(http.MethodPut, "http://localhost:80/update/service", bytes.NewBufferString(`{"amount":1}`))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+				ctx := session.SetSession(req.Context(), "123")
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				record := model2.IdempotencyRecord{Key: "key", Scope: "PUT /update/service/auth/123", RequestHash: hash}
+				mockDs.EXPECT().InsertIdempotencyKey(record).Return(true, nil)
+				record.Status = http.StatusBadRequest
+				record.Response = "{\"status\":400,\"message\":\"passed\",\"data\":\"123\"}\n"
+				record.ContentType = "application/json"
+				mockDs.EXPECT().UpdateIdempotencyKey(record).Return(nil)
+				return req.WithContext(ctx), mockDs
+			},
+			validator: func(res *httptest.ResponseRecorder) {
+				if hit != true {
+					t.Errorf("Want: %v, Got: %v", true, hit)
+				}
+				if res.Code != http.StatusBadRequest {
+					t.Errorf("Want: %v, Got: %v", http.StatusBadRequest, res.Code)
+				}
+			},
+		},
+		{
+			name:    "SUCCESS::Idempotency::replay stored response",
+			config:  config.Config{Idempotency: config.IdempotencyCfg{Time: time.Hour}},
+			handler: test,
+			setupFunc: func() (*http.Request, *mock.MockDataSourceI) {
+				req := httptest.NewRequest(http.MethodPut, "http://localhost:80/update/transaction", bytes.NewBufferString(`{"amount":1}`))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertIdempotencyKey(gomock.Any()).Return(false, nil)
+				mockDs.EXPECT().GetIdempotencyKey("key", "PUT /update/transaction").Return(&model2.IdempotencyRecord{Key: "key", Scope: "PUT /update/transaction", RequestHash: hash, Status: http.StatusAccepted, Response: "stored", ContentType: "application/json", CreatedOn: time.Now()}, nil)
+				return req, mockDs
+			},
+			validator: func(res *httptest.ResponseRecorder) {
+				if hit != false {
+					t.Errorf("Want: %v, Got: %v", false, hit)
+				}
+				if res.Code != http.StatusAccepted {
+					t.Errorf("Want: %v, Got: %v", http.StatusAccepted, res.Code)
+				}
+				if res.Header().Get(IdempotencyReplayedHeader) != "true" {
+					t.Errorf("Want: %v, Got: %v", "true", res.Header().Get(IdempotencyReplayedHeader))
+				}
+				by, _ := ioutil.ReadAll(res.Body)
+				if string(by) != "stored" {
+					t.Errorf("Want: %v, Got: %v", "stored", string(by))
+				}
+			},
+		},
+		{
+			name:    "FAILURE::Idempotency::request in progress",
+			config:  config.Config{Idempotency: config.IdempotencyCfg{Time: time.Hour}},
+			handler: test,
+			setupFunc: func() (*http.Request, *mock.MockDataSourceI) {
+				req := httptest.NewRequest(http.MethodPut, "http://localhost:80/update/transaction", bytes.NewBufferString(`{"amount":1}`))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertIdempotencyKey(gomock.Any()).Return(false, nil)
+				mockDs.EXPECT().GetIdempotencyKey("key", "PUT /update/transaction").Return(&model2.IdempotencyRecord{RequestHash: hash, CreatedOn: time.Now()}, nil)
+				return req, mockDs
+			},
+			validator: func(res *httptest.ResponseRecorder) {
+				if hit != false {
+					t.Errorf("Want: %v, Got: %v", false, hit)
+				}
+				if res.Code != http.StatusConflict {
+					t.Errorf("Want: %v, Got: %v", http.StatusConflict, res.Code)
+				}
+			},
+		},
+		{
+			name:    "FAILURE::Idempotency::key reused with a different body",
+			config:  config.Config{Idempotency: config.IdempotencyCfg{Time: time.Hour}},
+			handler: test,
+			setupFunc: func() (*http.Request, *mock.MockDataSourceI) {
+				req := httptest.NewRequest(http.MethodPut, "http://localhost:80/update/transaction", bytes.NewBufferString(`{"amount":1}`))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertIdempotencyKey(gomock.Any()).Return(false, nil)
+				mockDs.EXPECT().GetIdempotencyKey("key", "PUT /update/transaction").Return(&model2.IdempotencyRecord{RequestHash: "abc", Status: http.StatusAccepted, CreatedOn: time.Now()}, nil)
+				return req, mockDs
+			},
+			validator: func(res *httptest.ResponseRecorder) {
+				by, _ := ioutil.ReadAll(res.Body)
+				result := model.Response{}
+				json.Unmarshal(by, &result)
+				expected := model.Response{
+					Status:  http.StatusUnprocessableEntity,
+					Message: codes.GetErr(codes.ErrIdempotencyKeyReused),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(result, expected) {
+					t.Errorf("Want: %v, Got: %v", expected, result)
+				}
+			},
+		},
+		{
+			name:    "SUCCESS::Idempotency::expired key is replaced",
+			config:  config.Config{Idempotency: config.IdempotencyCfg{Time: time.Hour}},
+			handler: test,
+			setupFunc: func() (*http.Request, *mock.MockDataSourceI) {
+				req := httptest.NewRequest(http.MethodPut, "http://localhost:80/update/transaction", bytes.NewBufferString(`{"amount":1}`))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				gomock.InOrder(
+					mockDs.EXPECT().InsertIdempotencyKey(gomock.Any()).Return(false, nil),
+					mockDs.EXPECT().GetIdempotencyKey("key", "PUT /update/transaction").Return(&model2.IdempotencyRecord{RequestHash: hash, Status: http.StatusAccepted, CreatedOn: time.Now().Add(-2 * time.Hour)}, nil),
+					mockDs.EXPECT().DeleteIdempotencyKey("key", "PUT /update/transaction").Return(nil),
+					mockDs.EXPECT().InsertIdempotencyKey(gomock.Any()).Return(true, nil),
+					mockDs.EXPECT().UpdateIdempotencyKey(gomock.Any()).Return(nil),
+				)
+				return req, mockDs
+			},
+			validator: func(res *httptest.ResponseRecorder) {
+				if hit != true {
+					t.Errorf("Want: %v, Got: %v", true, hit)
+				}
+			},
+		},
+		{
+			name: "SUCCESS::Idempotency::server error releases the key",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				hit = true
+				response.ToJson(w, http.StatusInternalServerError, "failed", nil)
+			},
+			setupFunc: func() (*http.Request, *mock.MockDataSourceI) {
+				req := httptest.NewRequest(http.MethodPut, "http://localhost:80/update/transaction", bytes.NewBufferString(`{"amount":1}`))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertIdempotencyKey(gomock.Any()).Return(true, nil)
+				mockDs.EXPECT().DeleteIdempotencyKey("key", "PUT /update/transaction").Return(nil)
+				return req, mockDs
+			},
+			validator: func(res *httptest.ResponseRecorder) {
+				if res.Code != http.StatusInternalServerError {
+					t.Errorf("Want: %v, Got: %v", http.StatusInternalServerError, res.Code)
+				}
+			},
+		},
+		{
+			name: "SUCCESS::Idempotency::panic releases the key",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				hit = true
+				panic("handler panic")
+			},
+			setupFunc: func() (*http.Request, *mock.MockDataSourceI) {
+				req := httptest.NewRequest(http.MethodPut, "http://localhost:80/update/transaction", bytes.NewBufferString(`{"amount":1}`))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertIdempotencyKey(gomock.Any()).Return(true, nil)
+				mockDs.EXPECT().DeleteIdempotencyKey("key", "PUT /update/transaction").Return(nil)
+				return req, mockDs
+			},
+			validator: func(res *httptest.ResponseRecorder) {
+				if recovered != "handler panic" {
+					t.Errorf("Want: %v, Got: %v", "handler panic", recovered)
+				}
+			},
+		},
+		{
+			name:    "FAILURE::Idempotency::db error",
+			handler: test,
+			setupFunc: func() (*http.Request, *mock.MockDataSourceI) {
+				req := httptest.NewRequest(http.MethodPut, "http://localhost:80/update/transaction", bytes.NewBufferString(`{"amount":1}`))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+				mockDs := mock.NewMockDataSourceI(mockCtrl)
+				mockDs.EXPECT().InsertIdempotencyKey(gomock.Any()).Return(false, errors.New("db error"))
+				return req, mockDs
+			},
+			validator: func(res *httptest.ResponseRecorder) {
+				if hit != false {
+					t.Errorf("Want: %v, Got: %v", false, hit)
+				}
+				by, _ := ioutil.ReadAll(res.Body)
+				result := model.Response{}
+				json.Unmarshal(by, &result)
+				expected := model.Response{
+					Status:  http.StatusInternalServerError,
+					Message: codes.GetErr(codes.ErrIdempotencyKey),
+					Data:    nil,
+				}
+				if !reflect.DeepEqual(result, expected) {
+					t.Errorf("Want: %v, Got: %v", expected, result)
+				}
+			},
+		},
+	}
+
+	// to execute the tests in the table
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// STEP 1: seting up all instances for the specific test case
+			res := httptest.NewRecorder()
+			req, ds := tt.setupFunc()
+			middleware := AccMgmtMiddleware{
+				cfg: &tt.config,
+				ds:  ds,
+			}
+			hit = false
+			recovered = nil
+			x := middleware.Idempotency(tt.handler)
+			func() {
+				defer func() {
+					recovered = recover()
+				}()
+				x.ServeHTTP(res, req)
+			}()
+
+			tt.validator(res)
+		})
+	}
+}
+
+func TestUserMgmtMiddleware_Admin(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupFunc func() *http.Request
+		wantHit   bool
+		want      *model.Response
+	}{
+		{
+			name: "SUCCESS::Admin",
+			setupFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost:80/update/limits", nil)
+				return req.WithContext(session.SetSession(req.Context(), "admin"))
+			},
+			wantHit: true,
+			want:    &model.Response{Status: http.StatusOK, Message: "passed", Data: "admin"},
+		},
+		{
+			name: "Failure::Admin:: user is not an admin",
+			setupFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost:80/update/limits", nil)
+				return req.WithContext(session.SetSession(req.Context(), "123"))
+			},
+			want: &model.Response{Status: http.StatusForbidden, Message: codes.GetErr(codes.ErrAdminOnly)},
+		},
+		{
+			name: "Failure::Admin:: no user",
+			setupFunc: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "http://localhost:80/update/limits", nil)
+			},
+			want: &model.Response{Status: http.StatusBadRequest, Message: codes.GetErr(codes.ErrAssertUserid)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			middleware := AccMgmtMiddleware{
+				cfg: &config.Config{Admins: []string{"admin"}},
+			}
+			hit = false
+			x := middleware.Admin(http.HandlerFunc(test))
+			x.ServeHTTP(res, tt.setupFunc())
+
+			if hit != tt.wantHit {
+				t.Errorf("Want: %v, Got: %v", tt.wantHit, hit)
+			}
+			var result model.Response
+			err := json.Unmarshal(res.Body.Bytes(), &result)
+			if err != nil || !reflect.DeepEqual(&result, tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, &result)
+			}
+		})
+	}
+}
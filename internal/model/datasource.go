@@ -14,15 +14,265 @@ type DsResponse struct {
 type PingDs struct {
 	Data string
 }
+
+// Account types, a user holds at most one account of each type.
+const (
+	AccountTypeCurrent = "current"
+	AccountTypeSavings = "savings"
+	AccountTypeWallet  = "wallet"
+)
+
+// Account statuses, an account is opened pending and only takes postings once active.
+const (
+	AccountPending = "pending"
+	AccountActive  = "active"
+	AccountFrozen  = "frozen"
+	AccountClosed  = "closed"
+)
+
+// accountTransitions lists the statuses an account may move to from each status, closed is final.
+var accountTransitions = map[string][]string{
+	AccountPending: {AccountActive, AccountClosed},
+	AccountActive:  {AccountFrozen, AccountClosed},
+	AccountFrozen:  {AccountActive, AccountClosed},
+}
+
+// CanTransition reports whether an account may move from one status to the other.
+func CanTransition(from string, to string) bool {
+	for _, status := range accountTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
 type Account struct {
 	Id               string
 	AccountNumber    int
-	Income           float64
-	Spends           float64
+	Income           Money
+	Spends           Money
+	Currency         string
+	AccountType      string
+	OverdraftLimit   Money
+	Held             Money
 	CreatedOn        time.Time
 	UpdatedOn        time.Time
 	ActiveServices   *Svc
 	InactiveServices *Svc
+	Status           string
+	// Default marks the account used when the user does not name one
+	Default bool
+}
+
+// Member roles, the owner opened the account and manages its members, co-owners operate it and viewers only see it.
+const (
+	RoleOwner   = "owner"
+	RoleCoOwner = "co-owner"
+	RoleViewer  = "viewer"
+)
+
+// roleRanks orders the roles, a role may do whatever the roles ranked below it may.
+var roleRanks = map[string]int{RoleViewer: 1, RoleCoOwner: 2, RoleOwner: 3}
+
+// RoleAllows reports whether a member of the role may do what the required role may.
+func RoleAllows(role string, required string) bool {
+	return roleRanks[required] > 0 && roleRanks[role] >= roleRanks[required]
+}
+
+// Member gives a user access to an account in the role.
+type Member struct {
+	AccountNumber int       `json:"account_number"`
+	UserId        string    `json:"user_id"`
+	Role          string    `json:"role"`
+	CreatedOn     time.Time `json:"created_on"`
+}
+
+// MemberAccount is an account as seen by one of its members.
+type MemberAccount struct {
+	Account
+	Role string
+}
+
+// Accepts reports whether the account takes a posting of the transaction type in its status,
+// pending and frozen accounts accept credits only and closed accounts accept nothing.
+func (a Account) Accepts(transactionType string) bool {
+	switch a.Status {
+	case AccountActive:
+		return true
+	case AccountPending, AccountFrozen:
+		return transactionType == "credit"
+	}
+	return false
+}
+
+// Balance is what the account holds, it goes negative while the account is overdrawn.
+func (a Account) Balance() Money {
+	return a.Income - a.Spends
+}
+
+// AvailableBalance is what can still be debited from the account including its overdraft, less the pending holds.
+func (a Account) AvailableBalance() Money {
+	return a.Balance() + a.OverdraftLimit - a.Held
+}
+
+type Transaction struct {
+	Id               int64     `json:"transaction_id"`
+	AccountNumber    int       `json:"account_number"`
+	Amount           Money     `json:"amount"`
+	Currency         string    `json:"currency"`
+	OriginalAmount   Money     `json:"original_amount,omitempty"`
+	OriginalCurrency string    `json:"original_currency,omitempty"`
+	TransactionType  string    `json:"transaction_type"`
+	Reference        string    `json:"reference,omitempty"`
+	Category         string    `json:"category,omitempty"`
+	Merchant         string    `json:"merchant,omitempty"`
+	ReversalOf       int64     `json:"reversal_of,omitempty"`
+	FeeOf            int64     `json:"fee_of,omitempty"`
+	ReversedAmount   Money     `json:"reversed_amount,omitempty"`
+	CreatedOn        time.Time `json:"created_on"`
+	// Contra is the internal account taking the other leg of the posting, the suspense account when empty
+	Contra string `json:"-"`
+}
+
+// Internal accounts of the chart of accounts, every posting has a leg on a customer account and the opposite leg
+// on one of them.
+const (
+	LedgerCash            = "cash"
+	LedgerSuspense        = "suspense"
+	LedgerFees            = "fees"
+	LedgerInterestExpense = "interest_expense"
+	LedgerOpening         = "opening_balance"
+)
+
+// OpeningBalanceReference is the reference of the ledger entries carrying over the totals of the accounts opened
+// before the ledger existed.
+const OpeningBalanceReference = "opening balance"
+
+type InternalAccount struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ChartOfAccounts holds the internal accounts, the customer accounts are liabilities of the bank. Postings with
+// no better counterpart, such as deposits and withdrawals, clear through the suspense account.
+var ChartOfAccounts = []InternalAccount{
+	{Code: LedgerCash, Name: "Cash", Type: "asset"},
+	{Code: LedgerSuspense, Name: "Suspense", Type: "liability"},
+	{Code: LedgerFees, Name: "Fee income", Type: "income"},
+	{Code: LedgerInterestExpense, Name: "Interest expense", Type: "expense"},
+	{Code: LedgerOpening, Name: "Opening balances", Type: "equity"},
+}
+
+// JournalLeg is one side of a posting, either on a customer account or on an internal account.
+type JournalLeg struct {
+	TransactionId   int64  `json:"transaction_id"`
+	AccountNumber   int    `json:"account_number,omitempty"`
+	InternalAccount string `json:"internal_account,omitempty"`
+	Direction       string `json:"direction"`
+	Amount          Money  `json:"amount"`
+	Currency        string `json:"currency"`
+}
+
+// Legs splits the posting into its balanced legs. The customer leg follows the transaction type, a debit takes
+// money out of the account, and the contra leg goes the opposite way.
+func (t Transaction) Legs() []JournalLeg {
+	contra := t.Contra
+	if contra == "" {
+		contra = LedgerSuspense
+	}
+	opposite := "debit"
+	if t.TransactionType == "debit" {
+		opposite = "credit"
+	}
+	return []JournalLeg{
+		{TransactionId: t.Id, AccountNumber: t.AccountNumber, Direction: t.TransactionType, Amount: t.Amount, Currency: t.Currency},
+		{TransactionId: t.Id, InternalAccount: contra, Direction: opposite, Amount: t.Amount, Currency: t.Currency},
+	}
+}
+
+// SignedAmount is the effect of the entry on the balance, debits take money out of the account.
+func (t Transaction) SignedAmount() Money {
+	if t.TransactionType == "debit" {
+		return -t.Amount
+	}
+	return t.Amount
+}
+
+// TransactionTotals sums the transactions of one category within one calendar month (YYYY-MM), reversals
+// count against the side of the entry they reverse.
+type TransactionTotals struct {
+	Month    string
+	Category string
+	Income   Money
+	Spends   Money
+}
+
+const (
+	StandingOrderActive    = "active"
+	StandingOrderCancelled = "cancelled"
+	StandingOrderCompleted = "completed"
+)
+
+// StandingOrder posts the same transaction every time its cron schedule fires, until its end date.
+type StandingOrder struct {
+	Id                int64      `json:"standing_order_id"`
+	AccountNumber     int        `json:"account_number"`
+	Amount            Money      `json:"amount"`
+	Currency          string     `json:"currency,omitempty"`
+	TransactionType   string     `json:"transaction_type"`
+	Reference         string     `json:"reference,omitempty"`
+	Schedule          string     `json:"schedule"`
+	EndDate           *time.Time `json:"end_date,omitempty"`
+	NextRun           time.Time  `json:"next_run"`
+	LastRun           *time.Time `json:"last_run,omitempty"`
+	LastTransactionId int64      `json:"last_transaction_id,omitempty"`
+	LastError         string     `json:"last_error,omitempty"`
+	Status            string     `json:"status"`
+	CreatedOn         time.Time  `json:"created_on"`
+}
+
+const (
+	HoldPending  = "pending"
+	HoldCaptured = "captured"
+	HoldReleased = "released"
+	HoldExpired  = "expired"
+)
+
+// Hold reserves part of the available balance of an account until it is captured into a debit, released or expires.
+type Hold struct {
+	Id             int64     `json:"hold_id"`
+	AccountNumber  int       `json:"account_number"`
+	Amount         Money     `json:"amount"`
+	Currency       string    `json:"currency"`
+	Reference      string    `json:"reference,omitempty"`
+	Category       string    `json:"category,omitempty"`
+	Merchant       string    `json:"merchant,omitempty"`
+	Status         string    `json:"status"`
+	ExpiresOn      time.Time `json:"expires_on"`
+	CapturedAmount Money     `json:"captured_amount,omitempty"`
+	TransactionId  int64     `json:"transaction_id,omitempty"`
+	CreatedOn      time.Time `json:"created_on"`
+}
+
+// Budget caps the spends of an account within a calendar month, a budget without a category covers all spends.
+type Budget struct {
+	Id            int64     `json:"budget_id"`
+	AccountNumber int       `json:"account_number"`
+	Category      string    `json:"category,omitempty"`
+	Limit         Money     `json:"limit"`
+	CreatedOn     time.Time `json:"created_on"`
+}
+
+type IdempotencyRecord struct {
+	Key         string
+	Scope       string
+	RequestHash string
+	Status      int
+	Response    string
+	ContentType string
+	CreatedOn   time.Time
 }
 type ColumnUpdate struct {
 	UpdateSet string
@@ -48,15 +298,276 @@ func (s *Svc) Scan(value any) error {
 
 const Schema = `
 	(
-	user_id varchar(225) not null unique,
+	user_id varchar(225) not null,
 	account_number int AUTO_INCREMENT,
+	is_default bool not null DEFAULT false,
 	income dec(18,2) DEFAULT 0.00,
 	spends dec(18,2) DEFAULT 0.00,
+	currency char(3) not null DEFAULT 'USD',
+	account_type varchar(20) not null DEFAULT 'current',
+	overdraft_limit dec(18,2) not null DEFAULT 0.00,
+	held dec(18,2) not null DEFAULT 0.00,
 	created_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
 	updated_on timestamp not null DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
 	active_services json,
 	inactive_services json,
+	status varchar(10) not null DEFAULT 'active',
 	primary key (account_number),
 	index(user_id)
 );
 	`
+
+// Column is a column added to a table after its first release, tables created before it are altered to add it.
+type Column struct {
+	Name       string
+	Definition string
+	// Backfill is run on the table, given as its only argument, once the column is added.
+	Backfill string
+}
+
+// AccountColumns are the columns added to Schema since its first release, in the order they were added.
+var AccountColumns = []Column{
+	{Name: "currency", Definition: "char(3) not null DEFAULT 'USD'"},
+	{Name: "overdraft_limit", Definition: "dec(18,2) not null DEFAULT 0.00"},
+	{Name: "held", Definition: "dec(18,2) not null DEFAULT 0.00"},
+	{Name: "account_type", Definition: "varchar(20) not null DEFAULT 'current'"},
+	{Name: "status", Definition: "varchar(10) not null DEFAULT 'active'"},
+	// users held a single account before, which becomes their default one
+	{Name: "is_default", Definition: "bool not null DEFAULT false", Backfill: "UPDATE %s SET is_default = true;"},
+}
+
+const MemberSchema = `
+	(
+	account_number int not null,
+	user_id varchar(225) not null,
+	role varchar(10) not null,
+	created_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
+	primary key (account_number, user_id),
+	index(user_id)
+);
+	`
+
+const TransactionSchema = `
+	(
+	transaction_id bigint AUTO_INCREMENT,
+	account_number int not null,
+	amount dec(18,2) not null,
+	currency char(3) not null,
+	original_amount dec(18,2) not null DEFAULT 0,
+	original_currency char(3) not null DEFAULT '',
+	transaction_type varchar(10) not null,
+	reference varchar(225),
+	category varchar(64) not null DEFAULT '',
+	merchant varchar(225) not null DEFAULT '',
+	reversal_of bigint not null DEFAULT 0,
+	fee_of bigint not null DEFAULT 0,
+	reversed_amount dec(18,2) not null DEFAULT 0,
+	created_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
+	primary key (transaction_id),
+	index(account_number, created_on)
+);
+	`
+
+// TransactionColumns are the columns added to TransactionSchema since its first release, in the order they were added.
+// Transactions recorded before currencies were in the currency of their account, which was USD.
+var TransactionColumns = []Column{
+	{Name: "reversal_of", Definition: "bigint not null DEFAULT 0"},
+	{Name: "reversed_amount", Definition: "dec(18,2) not null DEFAULT 0"},
+	{Name: "currency", Definition: "char(3) not null DEFAULT 'USD'"},
+	{Name: "original_amount", Definition: "dec(18,2) not null DEFAULT 0"},
+	{Name: "original_currency", Definition: "char(3) not null DEFAULT ''"},
+	{Name: "category", Definition: "varchar(64) not null DEFAULT ''"},
+	{Name: "merchant", Definition: "varchar(225) not null DEFAULT ''"},
+	{Name: "fee_of", Definition: "bigint not null DEFAULT 0"},
+}
+
+const JournalSchema = `
+	(
+	leg_id bigint AUTO_INCREMENT,
+	transaction_id bigint not null,
+	account_number int not null DEFAULT 0,
+	internal_account varchar(32) not null DEFAULT '',
+	direction varchar(6) not null,
+	amount dec(18,2) not null,
+	currency char(3) not null,
+	created_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
+	primary key (leg_id),
+	index(transaction_id),
+	index(internal_account, currency)
+);
+	`
+
+const InternalAccountSchema = `
+	(
+	code varchar(32) not null,
+	name varchar(64) not null,
+	account_type varchar(16) not null,
+	primary key (code)
+);
+	`
+
+const AccountHistorySchema = `
+	(
+	history_id bigint AUTO_INCREMENT,
+	account_number int not null,
+	income dec(18,2) not null,
+	spends dec(18,2) not null,
+	currency char(3) not null,
+	account_type varchar(20) not null,
+	overdraft_limit dec(18,2) not null,
+	held dec(18,2) not null,
+	active_services json,
+	inactive_services json,
+	status varchar(10) not null,
+	changed_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
+	primary key (history_id),
+	index(account_number, changed_on)
+);
+	`
+
+// AccountHistoryColumns are the columns added to AccountHistorySchema since its first release.
+var AccountHistoryColumns = []Column{
+	{Name: "status", Definition: "varchar(10) not null DEFAULT 'active'"},
+}
+
+const StandingOrderSchema = `
+	(
+	standing_order_id bigint AUTO_INCREMENT,
+	account_number int not null,
+	amount dec(18,2) not null,
+	currency char(3) not null DEFAULT '',
+	transaction_type varchar(10) not null,
+	reference varchar(225) not null DEFAULT '',
+	schedule varchar(225) not null,
+	end_date datetime null DEFAULT null,
+	next_run datetime not null,
+	last_run datetime null DEFAULT null,
+	last_transaction_id bigint not null DEFAULT 0,
+	last_error varchar(225) not null DEFAULT '',
+	status varchar(10) not null DEFAULT 'active',
+	created_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
+	primary key (standing_order_id),
+	index(status, next_run),
+	index(account_number)
+);
+	`
+
+// InterestAccrual is the interest of an account for one calendar month (YYYY-MM), at most one is posted per month.
+type InterestAccrual struct {
+	AccountNumber int    `json:"account_number"`
+	Period        string `json:"period"`
+	Currency      string `json:"currency"`
+	// Rate is the yearly rate in percent the interest was accrued at
+	Rate          string    `json:"rate"`
+	DayCount      string    `json:"day_count"`
+	Amount        Money     `json:"amount"`
+	TransactionId int64     `json:"transaction_id,omitempty"`
+	CreatedOn     time.Time `json:"created_on"`
+}
+
+// SpendLimits bound the debits of an account within a calendar day (UTC), a zero limit does not apply.
+type SpendLimits struct {
+	MaxDebit      Money `json:"max_debit"`
+	MaxDailyDebit Money `json:"max_daily_debit"`
+	MaxDailyCount int   `json:"max_daily_count"`
+}
+
+// IsZero reports whether none of the limits applies.
+func (s SpendLimits) IsZero() bool {
+	return s == SpendLimits{}
+}
+
+// SpendLimitOverride replaces the limits of the account tier for one account, a nil limit keeps the tier default.
+type SpendLimitOverride struct {
+	AccountNumber int
+	MaxDebit      *Money
+	MaxDailyDebit *Money
+	MaxDailyCount *int
+	UpdatedOn     time.Time
+}
+
+// Apply returns the tier limits with the overridden ones replaced.
+func (o SpendLimitOverride) Apply(limits SpendLimits) SpendLimits {
+	if o.MaxDebit != nil {
+		limits.MaxDebit = *o.MaxDebit
+	}
+	if o.MaxDailyDebit != nil {
+		limits.MaxDailyDebit = *o.MaxDailyDebit
+	}
+	if o.MaxDailyCount != nil {
+		limits.MaxDailyCount = *o.MaxDailyCount
+	}
+	return limits
+}
+
+const SpendLimitSchema = `
+	(
+	account_number int not null,
+	max_debit dec(18,2),
+	max_daily_debit dec(18,2),
+	max_daily_count int,
+	updated_on timestamp not null DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	primary key (account_number)
+);
+	`
+
+const InterestAccrualSchema = `
+	(
+	account_number int not null,
+	period char(7) not null,
+	currency char(3) not null,
+	rate varchar(20) not null,
+	day_count varchar(10) not null,
+	amount dec(18,2) not null,
+	transaction_id bigint not null DEFAULT 0,
+	created_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
+	primary key (account_number, period),
+	index(period)
+);
+	`
+
+const HoldSchema = `
+	(
+	hold_id bigint AUTO_INCREMENT,
+	account_number int not null,
+	amount dec(18,2) not null,
+	currency char(3) not null,
+	reference varchar(225) not null DEFAULT '',
+	category varchar(64) not null DEFAULT '',
+	merchant varchar(225) not null DEFAULT '',
+	status varchar(10) not null DEFAULT 'pending',
+	expires_on datetime not null,
+	captured_amount dec(18,2) not null DEFAULT 0,
+	transaction_id bigint not null DEFAULT 0,
+	created_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
+	primary key (hold_id),
+	index(account_number, status),
+	index(status, expires_on)
+);
+	`
+
+const BudgetSchema = `
+	(
+	budget_id bigint AUTO_INCREMENT,
+	account_number int not null,
+	category varchar(64) not null DEFAULT '',
+	amount dec(18,2) not null,
+	created_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
+	primary key (budget_id),
+	unique(account_number, category)
+);
+	`
+
+const IdempotencySchema = `
+	(
+	idempotency_key varchar(225) not null,
+	scope varchar(225) not null,
+	request_hash char(64) not null,
+	status int not null DEFAULT 0,
+	response mediumtext not null,
+	content_type varchar(225) not null,
+	created_on timestamp not null DEFAULT CURRENT_TIMESTAMP,
+	primary key (idempotency_key, scope),
+	index(created_on)
+);
+	`
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount in cents, it maps onto the dec(18,2) columns without going through float64.
+type Money int64
+
+// maxMoneyDigits is the number of digits a dec(18,2) column holds before the decimal point.
+const maxMoneyDigits = 16
+
+var (
+	ErrInvalidMoney   = errors.New("invalid amount")
+	ErrMoneyPrecision = errors.New("amount has more than two decimal places")
+	ErrNegativeMoney  = errors.New("amount cannot be negative")
+)
+
+// ParseMoney parses a non-negative decimal amount with at most two decimal places.
+func ParseMoney(s string) (Money, error) {
+	if strings.HasPrefix(s, "-") {
+		return 0, ErrNegativeMoney
+	}
+	return parseMoney(s)
+}
+
+// parseMoney parses a signed decimal amount, only digits and a single decimal point are accepted
+// so exponents, NaN and infinities are rejected.
+func parseMoney(s string) (Money, error) {
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	if len(strings.TrimRight(fraction, "0")) > 2 {
+		return 0, ErrMoneyPrecision
+	}
+	whole = strings.TrimLeft(whole, "0")
+	if len(whole) > maxMoneyDigits {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidMoney, s)
+	}
+	fraction = (fraction + "00")[:2]
+	cents, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	if negative {
+		cents = -cents
+	}
+	return Money(cents), nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the amount with exactly two decimal places.
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a quoted decimal string, amounts sent by clients can never be negative.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	s := string(b)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	money, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) Scan(value any) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		*m = Money(v * 100)
+		return nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidMoney, value)
+	}
+	money, err := parseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
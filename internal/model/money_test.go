@@ -0,0 +1,176 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    string
+		want    Money
+		wantErr error
+	}{
+		{
+			name: "Success :: whole number",
+			give: `12`,
+			want: 1200,
+		},
+		{
+			name: "Success :: two decimal places",
+			give: `12.34`,
+			want: 1234,
+		},
+		{
+			name: "Success :: trailing zeros",
+			give: `0.100`,
+			want: 10,
+		},
+		{
+			name: "Success :: quoted string",
+			give: `"99999999.99"`,
+			want: 9999999999,
+		},
+		{
+			name: "Success :: null",
+			give: `null`,
+			want: 0,
+		},
+		{
+			name:    "Failure :: too many decimal places",
+			give:    `10.005`,
+			wantErr: ErrMoneyPrecision,
+		},
+		{
+			name:    "Failure :: negative",
+			give:    `-1.00`,
+			wantErr: ErrNegativeMoney,
+		},
+		{
+			name:    "Failure :: exponent",
+			give:    `1e3`,
+			wantErr: ErrInvalidMoney,
+		},
+		{
+			name:    "Failure :: non finite",
+			give:    `"NaN"`,
+			wantErr: ErrInvalidMoney,
+		},
+		{
+			name:    "Failure :: out of range",
+			give:    `12345678901234567`,
+			wantErr: ErrInvalidMoney,
+		},
+		{
+			name:    "Failure :: missing fraction",
+			give:    `"1."`,
+			wantErr: ErrInvalidMoney,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.give), &got)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMoney_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		give Money
+		want string
+	}{
+		{
+			name: "zero",
+			give: 0,
+			want: `0.00`,
+		},
+		{
+			name: "cents",
+			give: 5,
+			want: `0.05`,
+		},
+		{
+			name: "negative balance",
+			give: -1050,
+			want: `-10.50`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.give)
+			if err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Want: %v, Got: %v", tt.want, string(got))
+			}
+		})
+	}
+}
+
+func TestMoney_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    any
+		want    Money
+		wantErr error
+	}{
+		{
+			name: "Success :: decimal column",
+			give: []byte("1234.50"),
+			want: 123450,
+		},
+		{
+			name: "Success :: negative value",
+			give: "-0.75",
+			want: -75,
+		},
+		{
+			name: "Success :: integer",
+			give: int64(3),
+			want: 300,
+		},
+		{
+			name: "Success :: float",
+			give: 0.1,
+			want: 10,
+		},
+		{
+			name: "Success :: null",
+			give: nil,
+			want: 0,
+		},
+		{
+			name:    "Failure :: invalid value",
+			give:    []byte("abc"),
+			wantErr: ErrInvalidMoney,
+		},
+		{
+			name:    "Failure :: unsupported type",
+			give:    true,
+			wantErr: ErrInvalidMoney,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := got.Scan(tt.give)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
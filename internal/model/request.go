@@ -1,11 +1,33 @@
 package model
 
+import "time"
+
 type PingRequest struct {
 	Data string `json:"data" validate:"required"`
 }
 
 type NewAccount struct {
-	UserId string `json:"user_id" validate:"required"`
+	UserId   string `json:"user_id" validate:"required"`
+	Currency string `json:"currency" validate:"omitempty,iso4217"`
+	// AccountType decides the interest paid on the account, current when omitted
+	AccountType string `json:"account_type" validate:"omitempty,oneof=current savings wallet"`
+}
+
+// OpenAccount opens another account for the signed in user, a user holds at most one account of each type.
+type OpenAccount struct {
+	Currency    string `json:"currency" validate:"omitempty,iso4217"`
+	AccountType string `json:"account_type" validate:"required,oneof=current savings wallet"`
+}
+
+// DefaultAccount names the account used when the user does not name one.
+type DefaultAccount struct {
+	AccountNumber int `json:"account_number" validate:"required"`
+}
+
+// InviteMember shares the account with another user, the owner cannot be invited.
+type InviteMember struct {
+	UserId string `json:"user_id" validate:"required,max=225"`
+	Role   string `json:"role" validate:"required,oneof=co-owner viewer"`
 }
 
 type UpdateServices struct {
@@ -14,7 +36,96 @@ type UpdateServices struct {
 	UpdateType    string `json:"update_type" validate:"required,oneof=add remove"`
 }
 type UpdateTransaction struct {
-	AccountNumber   int     `json:"account_number" validate:"required"`
-	Amount          float64 `json:"amount" validate:"required"`
-	TransactionType string  `json:"transaction_type" validate:"required,oneof=debit credit"`
+	AccountNumber   int    `json:"account_number" validate:"required"`
+	Amount          Money  `json:"amount" validate:"required"`
+	TransactionType string `json:"transaction_type" validate:"required,oneof=debit credit"`
+	Currency        string `json:"currency" validate:"omitempty,iso4217"`
+	Reference       string `json:"reference" validate:"omitempty,max=225"`
+	Category        string `json:"category" validate:"omitempty,max=64"`
+	Merchant        string `json:"merchant" validate:"omitempty,max=225"`
+}
+
+// ImportRow is a row of a bulk transaction import, Line is its line in the file and Err tells why it cannot be posted.
+type ImportRow struct {
+	Line        int
+	Transaction UpdateTransaction
+	Err         string
+}
+type Transfer struct {
+	FromAccount int    `json:"from_account" validate:"required"`
+	ToAccount   int    `json:"to_account" validate:"required"`
+	Amount      Money  `json:"amount" validate:"required"`
+	Reference   string `json:"reference" validate:"omitempty,max=225"`
+}
+type Reversal struct {
+	TransactionId int64  `json:"transaction_id" validate:"required"`
+	Amount        Money  `json:"amount"`
+	Reference     string `json:"reference" validate:"omitempty,max=225"`
+}
+type OverdraftLimit struct {
+	AccountNumber int   `json:"account_number" validate:"required"`
+	Limit         Money `json:"limit"`
+}
+
+// AccountClosure is sent through the message broker when a user is deleted, it closes the account of the user.
+type AccountClosure struct {
+	UserId string `json:"user_id" validate:"required"`
+}
+
+// UpdateAccountStatus moves the account along its lifecycle, accounts only become pending when opened.
+type UpdateAccountStatus struct {
+	AccountNumber int    `json:"account_number" validate:"required"`
+	Status        string `json:"status" validate:"required,oneof=active frozen closed"`
+}
+
+// UpdateSpendLimits overrides the limits of the account tier, an omitted limit falls back to the tier default
+// and a limit of zero lifts it.
+type UpdateSpendLimits struct {
+	AccountNumber int    `json:"account_number" validate:"required"`
+	MaxDebit      *Money `json:"max_debit"`
+	MaxDailyDebit *Money `json:"max_daily_debit"`
+	MaxDailyCount *int   `json:"max_daily_count"`
+}
+type NewStandingOrder struct {
+	AccountNumber   int        `json:"account_number" validate:"required"`
+	Amount          Money      `json:"amount" validate:"required"`
+	TransactionType string     `json:"transaction_type" validate:"required,oneof=debit credit"`
+	Currency        string     `json:"currency" validate:"omitempty,iso4217"`
+	Reference       string     `json:"reference" validate:"omitempty,max=225"`
+	Schedule        string     `json:"schedule" validate:"required,max=225"`
+	EndDate         *time.Time `json:"end_date"`
+}
+type NewHold struct {
+	AccountNumber int        `json:"account_number" validate:"required"`
+	Amount        Money      `json:"amount" validate:"required"`
+	Reference     string     `json:"reference" validate:"omitempty,max=225"`
+	Category      string     `json:"category" validate:"omitempty,max=64"`
+	Merchant      string     `json:"merchant" validate:"omitempty,max=225"`
+	ExpiresOn     *time.Time `json:"expires_on"`
+}
+type CaptureHold struct {
+	HoldId    int64  `json:"hold_id" validate:"required"`
+	Amount    Money  `json:"amount"`
+	Reference string `json:"reference" validate:"omitempty,max=225"`
+}
+type ReleaseHold struct {
+	HoldId int64 `json:"hold_id" validate:"required"`
+}
+type NewBudget struct {
+	Category string `json:"category" validate:"omitempty,max=64"`
+	Limit    Money  `json:"limit" validate:"required"`
+}
+type UpdateBudget struct {
+	Limit Money `json:"limit" validate:"required"`
+}
+
+type TransactionFilter struct {
+	AccountNumber   int
+	Cursor          int64
+	Limit           int
+	From            time.Time
+	To              time.Time
+	TransactionType string
+	MinAmount       Money
+	MaxAmount       Money
 }
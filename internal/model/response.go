@@ -1,11 +1,209 @@
 package model
 
+import "time"
+
 type AccountSummary struct {
-	AccountNumber    int     `json:"account_number,omitempty"`
-	Income           float64 `json:"income"`
-	Spends           float64 `json:"spends"`
-	ActiveServices   *Svc    `json:"active_services"`
-	InactiveServices *Svc    `json:"inactive_services"`
+	AccountNumber    int    `json:"account_number,omitempty"`
+	Income           Money  `json:"income"`
+	Spends           Money  `json:"spends"`
+	Currency         string `json:"currency"`
+	AccountType      string `json:"account_type"`
+	Balance          Money  `json:"balance"`
+	OverdraftLimit   Money  `json:"overdraft_limit"`
+	AvailableBalance Money  `json:"available_balance"`
+	Held             Money  `json:"held"`
+	PendingHolds     []Hold `json:"pending_holds"`
+	ActiveServices   *Svc   `json:"active_services"`
+	InactiveServices *Svc   `json:"inactive_services"`
+	Status           string `json:"status"`
+	Default          bool   `json:"default"`
+	// Role is the role of the user on the account, the account is shared with them unless they own it
+	Role string `json:"role,omitempty"`
+	// AsOf is the time a past summary was asked for, ChangedOn is when the account last changed before it
+	AsOf      *time.Time `json:"as_of,omitempty"`
+	ChangedOn *time.Time `json:"changed_on,omitempty"`
+}
+type TransactionReceipt struct {
+	TransactionId int64 `json:"transaction_id"`
+	// FeeTransactionIds are the ledger entries of the fees charged on the transaction
+	FeeTransactionIds []int64 `json:"fee_transaction_ids,omitempty"`
+}
+type AccountStatusChange struct {
+	AccountNumber  int    `json:"account_number"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status"`
+}
+
+const (
+	ClosureConfirmed = "closure_confirmed"
+	ClosureRejected  = "closure_rejected"
+)
+
+// ClosureEvent is published once an account closure sent through the message broker was handled,
+// the reason tells why a closure was rejected.
+type ClosureEvent struct {
+	Event         string `json:"event"`
+	UserId        string `json:"user_id"`
+	AccountNumber int    `json:"account_number,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+type ServiceReceipt struct {
+	// FeeTransactionIds are the ledger entries of the fees charged for adding the service
+	FeeTransactionIds []int64 `json:"fee_transaction_ids"`
+}
+type HoldReceipt struct {
+	HoldId    int64     `json:"hold_id"`
+	ExpiresOn time.Time `json:"expires_on"`
+}
+type TransferReceipt struct {
+	DebitTransactionId  int64 `json:"debit_transaction_id"`
+	CreditTransactionId int64 `json:"credit_transaction_id"`
+}
+type TransactionHistory struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   int64         `json:"next_cursor,omitempty"`
+}
+
+// Statement covers the period [From, To) of an account, its transactions are ordered oldest first.
+type Statement struct {
+	AccountNumber  int
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance Money
+	ClosingBalance Money
+	Transactions   []Transaction
+}
+type StatementFile struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// Analytics covers the calendar months From to To (YYYY-MM) of an account, both included.
+type Analytics struct {
+	AccountNumber   int              `json:"account_number"`
+	Currency        string           `json:"currency"`
+	From            string           `json:"from"`
+	To              string           `json:"to"`
+	SpendByCategory []CategorySpend  `json:"spend_by_category"`
+	Monthly         []MonthlySummary `json:"monthly"`
+}
+type CategorySpend struct {
+	Category string `json:"category"`
+	Spends   Money  `json:"spends"`
+}
+type MonthlySummary struct {
+	Month  string `json:"month"`
+	Income Money  `json:"income"`
+	Spends Money  `json:"spends"`
+	Net    Money  `json:"net"`
+}
+
+// BudgetUsage is a budget with the spends it covers in the current calendar month (UTC).
+type BudgetUsage struct {
+	Budget
+	Month string `json:"month"`
+	Spent Money  `json:"spent"`
+}
+
+const (
+	InterestPosted   = "posted"
+	InterestMatches  = "matches"
+	InterestMismatch = "mismatch"
+	InterestUnposted = "unposted"
+)
+
+// InterestReportLine is the interest computed for an account and month next to what was posted for it,
+// a posted month is recomputed with the rate and day count it was posted with.
+type InterestReportLine struct {
+	InterestAccrual
+	Status       string `json:"status"`
+	PostedAmount Money  `json:"posted_amount"`
+}
+
+const (
+	ReconcileMismatch = "mismatch"
+	ReconcileRepaired = "repaired"
+)
+
+// ReconciliationLine is an account whose income and spends columns differ from the totals of its ledger.
+type ReconciliationLine struct {
+	AccountNumber int    `json:"account_number"`
+	Status        string `json:"status"`
+	Income        Money  `json:"income"`
+	Spends        Money  `json:"spends"`
+	LedgerIncome  Money  `json:"ledger_income"`
+	LedgerSpends  Money  `json:"ledger_spends"`
+}
+
+// TrialBalanceLine sums the legs of an internal account in one currency, the account is empty for the legs on
+// customer accounts.
+type TrialBalanceLine struct {
+	Account  string `json:"account"`
+	Currency string `json:"currency"`
+	Debits   Money  `json:"debits"`
+	Credits  Money  `json:"credits"`
+}
+
+// JournalLine is a currency in which the balances of the accounts differ from the customer legs of the journal, the
+// entries posted before the journal existed count on the journal side.
+type JournalLine struct {
+	Currency string `json:"currency"`
+	Accounts Money  `json:"accounts"`
+	Journal  Money  `json:"journal"`
+}
+
+// ReconciliationReport lists the accounts found out of line with their ledger, a repaired account had its
+// columns set to the ledger totals. The journal is balanced when the debits equal the credits in every currency
+// and its customer legs add up to the balances of the accounts.
+type ReconciliationReport struct {
+	CheckedOn         time.Time            `json:"checked_on"`
+	Mismatches        []ReconciliationLine `json:"mismatches"`
+	JournalMismatches []JournalLine        `json:"journal_mismatches"`
+	Balanced          bool                 `json:"balanced"`
+	TrialBalance      []TrialBalanceLine   `json:"trial_balance"`
+}
+
+const (
+	ImportValid   = "valid"
+	ImportInvalid = "invalid"
+	ImportApplied = "applied"
+	ImportFailed  = "failed"
+)
+
+// ImportRowResult is the outcome of a row of a bulk transaction import, a valid row is only posted outside a dry run.
+type ImportRowResult struct {
+	Line            int    `json:"line"`
+	AccountNumber   int    `json:"account_number,omitempty"`
+	Amount          Money  `json:"amount"`
+	TransactionType string `json:"transaction_type,omitempty"`
+	Reference       string `json:"reference,omitempty"`
+	Status          string `json:"status"`
+	TransactionId   int64  `json:"transaction_id,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Rows    int               `json:"rows"`
+	Valid   int               `json:"valid"`
+	Applied int               `json:"applied"`
+	Failed  int               `json:"failed"`
+	Results []ImportRowResult `json:"results"`
+}
+
+// BudgetAlert is published when a debit takes the spends of the month to a threshold (in percent) of a budget.
+type BudgetAlert struct {
+	UserId        string `json:"user_id"`
+	AccountNumber int    `json:"account_number"`
+	BudgetId      int64  `json:"budget_id"`
+	Category      string `json:"category,omitempty"`
+	Month         string `json:"month"`
+	Threshold     int    `json:"threshold"`
+	Limit         Money  `json:"limit"`
+	Spent         Money  `json:"spent"`
+	Currency      string `json:"currency"`
+	TransactionId int64  `json:"transaction_id"`
 }
 type CacheResponse struct {
 	Status      int
@@ -1,7 +1,10 @@
 package datasource
 
 import (
+	"errors"
+
 	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+	"time"
 )
 
 //go:generate mockgen --build_flags=--mod=mod --destination=./../../../pkg/mock/mock_datasource.go --package=mock github.com/vatsal278/AccountManagmentSvc/internal/repo/datasource DataSourceI
@@ -9,6 +12,85 @@ import (
 type DataSourceI interface {
 	HealthCheck() bool
 	Get(map[string]interface{}) ([]model.Account, error)
-	Insert(user model.Account) error
+	Insert(user model.Account) (int, error)
 	Update(filterSet map[string]interface{}, filterWhere map[string]interface{}) error
+	GetAccountAsOf(accountNumber int, asOf time.Time) (model.Account, error)
+	SetAccountStatus(accountNumber int, status string) (string, error)
+	SetDefaultAccount(userId string, accountNumber int) error
+	GetMemberAccounts(userId string, accountNumber int) ([]model.MemberAccount, error)
+	GetMembers(accountNumber int) ([]model.Member, error)
+	InsertMember(member model.Member) error
+	DeleteMember(accountNumber int, userId string) error
+	DeleteUserMembers(userId string) error
+	InsertTransaction(transaction model.Transaction) (int64, error)
+	InsertTransactions(transactions ...model.Transaction) ([]int64, error)
+	InsertTransactionWithFees(transaction model.Transaction, fees ...model.Transaction) ([]int64, error)
+	InsertDebit(limits model.SpendLimits, since time.Time, transaction model.Transaction, fees ...model.Transaction) ([]int64, error)
+	InsertTransfer(limits model.SpendLimits, since time.Time, debit model.Transaction, credit model.Transaction) ([]int64, error)
+	UpdateServices(accountNumber int, filterSet map[string]interface{}, fees ...model.Transaction) ([]int64, error)
+	GetTransactions(filter model.TransactionFilter) ([]model.Transaction, error)
+	GetBalance(accountNumber int, before time.Time) (model.Money, error)
+	GetTransactionTotals(accountNumber int, from time.Time, to time.Time) ([]model.TransactionTotals, error)
+	ReverseTransaction(transactionId int64, amount model.Money, reference string) (int64, error)
+	InsertStandingOrder(order model.StandingOrder) (int64, error)
+	GetStandingOrders(accountNumber int) ([]model.StandingOrder, error)
+	GetDueStandingOrders(now time.Time, limit int) ([]model.StandingOrder, error)
+	AdvanceStandingOrder(order model.StandingOrder, nextRun time.Time, status string) (bool, error)
+	RecordStandingOrderRun(id int64, run time.Time, transactionId int64, runErr string) error
+	CancelStandingOrder(id int64, accountNumber int) error
+	InsertBudget(budget model.Budget) (int64, error)
+	GetBudgets(accountNumber int) ([]model.Budget, error)
+	UpdateBudget(budget model.Budget) error
+	DeleteBudget(id int64, accountNumber int) error
+	InsertHold(limits model.SpendLimits, since time.Time, hold model.Hold) (int64, error)
+	GetHold(id int64) (model.Hold, error)
+	GetHolds(accountNumber int, status string) ([]model.Hold, error)
+	GetExpiredHolds(now time.Time, limit int) ([]model.Hold, error)
+	CaptureHold(limits model.SpendLimits, since time.Time, id int64, amount model.Money, reference string, now time.Time) (model.Transaction, error)
+	ReleaseHold(id int64, status string) error
+	PostInterest(accrual model.InterestAccrual) (int64, error)
+	GetInterestAccruals(period string) ([]model.InterestAccrual, error)
+	GetLedgerMismatches(accountNumber int) ([]model.ReconciliationLine, error)
+	RepairTotals(accountNumber int) (model.ReconciliationLine, error)
+	GetJournalMismatches() ([]model.JournalLine, error)
+	GetTrialBalance() ([]model.TrialBalanceLine, error)
+	GetSpendLimits(accountNumber int) (*model.SpendLimitOverride, error)
+	UpsertSpendLimits(override model.SpendLimitOverride) error
+	InsertIdempotencyKey(record model.IdempotencyRecord) (bool, error)
+	GetIdempotencyKey(key string, scope string) (*model.IdempotencyRecord, error)
+	UpdateIdempotencyKey(record model.IdempotencyRecord) error
+	DeleteIdempotencyKey(key string, scope string) error
+	DeleteExpiredIdempotencyKeys(before time.Time) (int64, error)
 }
+
+var (
+	ErrAccountNotFound       = errors.New("account not found")
+	ErrTransactionNotFound   = errors.New("transaction not found")
+	ErrAlreadyReversed       = errors.New("transaction already fully reversed")
+	ErrReversalExceedsAmount = errors.New("reversal exceeds the amount left to reverse")
+	ErrReversalOfReversal    = errors.New("a reversal cannot be reversed")
+	ErrCurrencyMismatch      = errors.New("transaction currency does not match the account currency")
+	ErrInsufficientFunds     = errors.New("insufficient funds")
+	ErrStandingOrderNotFound = errors.New("standing order not found or no longer active")
+	ErrBudgetExists          = errors.New("budget already exists for the category")
+	ErrBudgetNotFound        = errors.New("budget not found")
+	ErrHoldNotFound          = errors.New("hold not found")
+	ErrHoldNotPending        = errors.New("hold was already captured, released or expired")
+	ErrHoldExpired           = errors.New("hold expired")
+	ErrCaptureExceedsHold    = errors.New("capture exceeds the held amount")
+	ErrInterestPosted        = errors.New("interest already posted for the period")
+	ErrDebitLimit            = errors.New("debit exceeds the single debit limit")
+	ErrDailyDebitLimit       = errors.New("debit exceeds the daily debit limit")
+	ErrDailyDebitCount       = errors.New("daily debit count limit reached")
+	ErrNoHistory             = errors.New("no account history at the given time")
+	ErrAccountNotActive      = errors.New("account is not active yet")
+	ErrAccountFrozen         = errors.New("account is frozen")
+	ErrAccountClosed         = errors.New("account is closed")
+	ErrInvalidTransition     = errors.New("account status cannot change to the given status")
+	ErrBalanceNotSettled     = errors.New("account balance is not zero")
+	ErrHoldsNotSettled       = errors.New("account has pending holds")
+	ErrAccountNumberTaken    = errors.New("account number already in use")
+	ErrMemberExists          = errors.New("user is a member of the account already")
+	ErrMemberNotFound        = errors.New("user is not a member of the account")
+	ErrNoLedgerEntries       = errors.New("account has no ledger entries to repair its totals from")
+)
@@ -2,23 +2,46 @@ package datasource
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/vatsal278/AccountManagmentSvc/internal/config"
 	"github.com/vatsal278/AccountManagmentSvc/internal/model"
 	"log"
+	"sort"
 	"strings"
+	"time"
 )
 
 type sqlDs struct {
-	sqlSvc *sql.DB
-	table  string
+	sqlSvc             *sql.DB
+	table              string
+	transactionTable   string
+	idempotencyTable   string
+	standingOrderTable string
+	budgetTable        string
+	holdTable          string
+	interestTable      string
+	spendLimitTable    string
+	journalTable       string
+	historyTable       string
+	memberTable        string
 }
 
 //docker run --rm --env MYSQL_ROOT_PASSWORD=pass --env MYSQL_DATABASE=accmgmt --publish 9085:3306 --name mysqlDb -d mysql
-func NewSql(dbSvc config.DbSvc, tableName string) DataSourceI {
+func NewSql(dbSvc config.DbSvc, dbCfg config.DbCfg) DataSourceI {
 	return &sqlDs{
-		sqlSvc: dbSvc.Db,
-		table:  tableName,
+		sqlSvc:             dbSvc.Db,
+		table:              dbCfg.TableName,
+		transactionTable:   dbCfg.TransactionTableName,
+		idempotencyTable:   dbCfg.IdempotencyTableName,
+		standingOrderTable: dbCfg.StandingOrderTableName,
+		budgetTable:        dbCfg.BudgetTableName,
+		holdTable:          dbCfg.HoldTableName,
+		interestTable:      dbCfg.InterestAccrualTableName,
+		spendLimitTable:    dbCfg.SpendLimitTableName,
+		journalTable:       dbCfg.JournalTableName,
+		historyTable:       dbCfg.AccountHistoryTableName,
+		memberTable:        dbCfg.AccountMemberTableName,
 	}
 }
 
@@ -56,7 +79,7 @@ func (d sqlDs) Get(filter map[string]interface{}) ([]model.Account, error) {
 	//order the queries based on email address
 	var user model.Account
 	var users []model.Account
-	q := fmt.Sprintf("SELECT user_id, account_number, income, spends, created_on, updated_on, active_services, inactive_services FROM %s", d.table)
+	q := fmt.Sprintf("SELECT user_id, account_number, income, spends, currency, account_type, overdraft_limit, held, created_on, updated_on, active_services, inactive_services, status, is_default FROM %s", d.table)
 	whereQuery := queryFromMap(filter, " AND ")
 	if whereQuery != "" {
 		q += " WHERE " + whereQuery
@@ -67,7 +90,7 @@ func (d sqlDs) Get(filter map[string]interface{}) ([]model.Account, error) {
 		return nil, err
 	}
 	for rows.Next() {
-		err = rows.Scan(&user.Id, &user.AccountNumber, &user.Income, &user.Spends, &user.CreatedOn, &user.UpdatedOn, &user.ActiveServices, &user.InactiveServices)
+		err = rows.Scan(&user.Id, &user.AccountNumber, &user.Income, &user.Spends, &user.Currency, &user.AccountType, &user.OverdraftLimit, &user.Held, &user.CreatedOn, &user.UpdatedOn, &user.ActiveServices, &user.InactiveServices, &user.Status, &user.Default)
 		if err != nil {
 			return nil, err
 		}
@@ -76,17 +99,203 @@ func (d sqlDs) Get(filter map[string]interface{}) ([]model.Account, error) {
 	return users, nil
 }
 
-func (d sqlDs) Insert(user model.Account) error {
+// Insert opens the account owned by its user and returns its account number. The account is numbered by the database
+// unless it carries an allocated number, ErrAccountNumberTaken is returned when that number is already in use.
+func (d sqlDs) Insert(user model.Account) (int, error) {
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
 	queryString := fmt.Sprintf("INSERT INTO %s", d.table)
-	log.Print("(user_id, active_services, inactive_services) VALUES(?,?,?)", user.Id, user.ActiveServices, user.InactiveServices)
-	_, err := d.sqlSvc.Exec(queryString+"(user_id, active_services, inactive_services) VALUES(?,?,?)", user.Id, user.ActiveServices, user.InactiveServices)
+	columns := "(user_id, currency, account_type, active_services, inactive_services, status, is_default) VALUES(?,?,?,?,?,?,?)"
+	args := []interface{}{user.Id, user.Currency, user.AccountType, user.ActiveServices, user.InactiveServices, user.Status, user.Default}
+	if user.AccountNumber != 0 {
+		queryString = fmt.Sprintf("INSERT IGNORE INTO %s", d.table)
+		columns = "(account_number, user_id, currency, account_type, active_services, inactive_services, status, is_default) VALUES(?,?,?,?,?,?,?,?)"
+		args = append([]interface{}{user.AccountNumber}, args...)
+	}
+	log.Print(columns, args)
+	result, err := tx.Exec(queryString+columns, args...)
+	if err != nil {
+		return 0, err
+	}
+	accountNumber := int64(user.AccountNumber)
+	if accountNumber != 0 {
+		count, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if count == 0 {
+			return 0, ErrAccountNumberTaken
+		}
+	} else {
+		accountNumber, err = result.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+	}
+	q := fmt.Sprintf("INSERT INTO %s(account_number, user_id, role) VALUES(?,?,?);", d.memberTable)
+	_, err = tx.Exec(q, accountNumber, user.Id, model.RoleOwner)
+	if err != nil {
+		return 0, err
+	}
+	err = d.recordHistory(tx, "account_number = ?", accountNumber)
+	if err != nil {
+		return 0, err
+	}
+	return int(accountNumber), tx.Commit()
+}
+
+// SetDefaultAccount makes the account the default account of the user, it returns ErrAccountNotFound when the
+// account is not one of theirs and ErrAccountClosed when it is closed.
+func (d sqlDs) SetDefaultAccount(userId string, accountNumber int) error {
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var status string
+	q := fmt.Sprintf("SELECT status FROM %s WHERE user_id = ? AND account_number = ? FOR UPDATE;", d.table)
+	err = tx.QueryRow(q, userId, accountNumber).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAccountNotFound
+		}
+		return err
+	}
+	if status == model.AccountClosed {
+		return ErrAccountClosed
+	}
+	q = fmt.Sprintf("UPDATE %s SET is_default = (account_number = ?) WHERE user_id = ?;", d.table)
+	_, err = tx.Exec(q, accountNumber, userId)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetMemberAccounts returns the accounts the user is a member of with their role on each, or the account only when an
+// account number is given. Default is only kept on the accounts the user owns.
+func (d sqlDs) GetMemberAccounts(userId string, accountNumber int) ([]model.MemberAccount, error) {
+	var accounts []model.MemberAccount
+	q := fmt.Sprintf("SELECT a.user_id, a.account_number, a.income, a.spends, a.currency, a.account_type, a.overdraft_limit, a.held, a.created_on, a.updated_on, a.active_services, a.inactive_services, a.status, a.is_default AND m.role = ?, m.role "+
+		"FROM %s a JOIN %s m ON m.account_number = a.account_number WHERE m.user_id = ?", d.table, d.memberTable)
+	args := []interface{}{model.RoleOwner, userId}
+	if accountNumber != 0 {
+		q += " AND a.account_number = ?"
+		args = append(args, accountNumber)
+	}
+	rows, err := d.sqlSvc.Query(q+" ORDER BY a.account_number;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var acc model.MemberAccount
+		err = rows.Scan(&acc.Id, &acc.AccountNumber, &acc.Income, &acc.Spends, &acc.Currency, &acc.AccountType, &acc.OverdraftLimit, &acc.Held, &acc.CreatedOn, &acc.UpdatedOn, &acc.ActiveServices, &acc.InactiveServices, &acc.Status, &acc.Default, &acc.Role)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, rows.Err()
+}
+
+// GetMembers returns the members of the account, the owner comes first.
+func (d sqlDs) GetMembers(accountNumber int) ([]model.Member, error) {
+	var members []model.Member
+	q := fmt.Sprintf("SELECT account_number, user_id, role, created_on FROM %s WHERE account_number = ? ORDER BY role = ? DESC, created_on, user_id;", d.memberTable)
+	rows, err := d.sqlSvc.Query(q, accountNumber, model.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var member model.Member
+		err = rows.Scan(&member.AccountNumber, &member.UserId, &member.Role, &member.CreatedOn)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// InsertMember shares the account with the user, it returns ErrMemberExists when the user is a member already.
+func (d sqlDs) InsertMember(member model.Member) error {
+	q := fmt.Sprintf("INSERT IGNORE INTO %s(account_number, user_id, role) VALUES(?,?,?);", d.memberTable)
+	result, err := d.sqlSvc.Exec(q, member.AccountNumber, member.UserId, member.Role)
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrMemberExists
+	}
+	return nil
+}
+
+// DeleteMember stops sharing the account with the user, it returns ErrMemberNotFound when the user is not a member
+// other than the owner, who cannot be removed.
+func (d sqlDs) DeleteMember(accountNumber int, userId string) error {
+	q := fmt.Sprintf("DELETE FROM %s WHERE account_number = ? AND user_id = ? AND role != ?;", d.memberTable)
+	result, err := d.sqlSvc.Exec(q, accountNumber, userId, model.RoleOwner)
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if count == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// DeleteUserMembers removes the memberships of a deleted user. The user keeps only the ownership of the accounts which
+// are not closed yet, so those stay reachable until they are settled and closed.
+func (d sqlDs) DeleteUserMembers(userId string) error {
+	q := fmt.Sprintf("DELETE m FROM %s m JOIN %s a ON a.account_number = m.account_number WHERE m.user_id = ? AND (m.role != ? OR a.status = ?);", d.memberTable, d.table)
+	_, err := d.sqlSvc.Exec(q, userId, model.RoleOwner, model.AccountClosed)
+	return err
+}
+
+const historyColumns = "account_number, income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status"
+
+// recordHistory copies the account rows matching the condition into the history as they are now. It follows every
+// change to an account row within the same database transaction, so the history holds each state the row went through.
+func (d sqlDs) recordHistory(tx *sql.Tx, where string, args ...interface{}) error {
+	q := fmt.Sprintf("INSERT INTO %s(%s) SELECT %s FROM %s WHERE %s;", d.historyTable, historyColumns, historyColumns, d.table, where)
+	_, err := tx.Exec(q, args...)
 	return err
 }
 
+// GetAccountAsOf returns the account as it was at the given time, from the latest copy in its history at or
+// before it, with UpdatedOn set to the time of that copy. It returns ErrNoHistory when the history starts later.
+func (d sqlDs) GetAccountAsOf(accountNumber int, asOf time.Time) (model.Account, error) {
+	account := model.Account{AccountNumber: accountNumber}
+	q := fmt.Sprintf("SELECT income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status, changed_on FROM %s WHERE account_number = ? AND changed_on <= ? ORDER BY changed_on DESC, history_id DESC LIMIT 1;", d.historyTable)
+	err := d.sqlSvc.QueryRow(q, accountNumber, asOf).Scan(&account.Income, &account.Spends, &account.Currency, &account.AccountType, &account.OverdraftLimit, &account.Held, &account.ActiveServices, &account.InactiveServices, &account.Status, &account.UpdatedOn)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return account, ErrNoHistory
+		}
+		return account, err
+	}
+	return account, nil
+}
+
 func (d sqlDs) Update(filterSet map[string]interface{}, filterWhere map[string]interface{}) error {
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 	queryString := fmt.Sprintf("UPDATE %s ", d.table)
 
 	setQuery := queryFromMap(filterSet, " , ")
@@ -96,12 +305,1123 @@ func (d sqlDs) Update(filterSet map[string]interface{}, filterWhere map[string]i
 	whereQuery := queryFromMap(filterWhere, " AND ")
 	if whereQuery != "" {
 		queryString += " WHERE " + whereQuery
+	} else {
+		whereQuery = "TRUE"
 	}
 	queryString += " ;"
 	log.Print(queryString)
-	_, err := d.sqlSvc.Exec(queryString)
+	_, err = tx.Exec(queryString)
+	if err != nil {
+		return err
+	}
+	err = d.recordHistory(tx, whereQuery)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// InsertTransaction records the transaction in the ledger and applies it to the account totals
+// within a single database transaction, so the ledger and the account row never drift apart.
+func (d sqlDs) InsertTransaction(transaction model.Transaction) (int64, error) {
+	ids, err := d.InsertTransactions(transaction)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// InsertTransactions records all the transactions atomically, either every one of them is applied or none is.
+// The accounts involved are locked in ascending order so that concurrent calls cannot deadlock each other.
+func (d sqlDs) InsertTransactions(transactions ...model.Transaction) ([]int64, error) {
+	return d.insertTransactions(transactions, false, nil)
+}
+
+// InsertTransactionWithFees records the transaction and the fees charged on it atomically, the fee entries are
+// linked to the transaction through fee_of. The fees are debited from what is left once the transaction is applied.
+func (d sqlDs) InsertTransactionWithFees(transaction model.Transaction, fees ...model.Transaction) ([]int64, error) {
+	return d.insertTransactions(append([]model.Transaction{transaction}, fees...), true, nil)
+}
+
+// InsertDebit records the debit and the fees charged on it like InsertTransactionWithFees, once the debit is checked
+// against the spend limits. The daily limits count the debits posted since the given time, fees and reversals aside,
+// and are checked while the account is locked so concurrent debits cannot exceed them together.
+func (d sqlDs) InsertDebit(limits model.SpendLimits, since time.Time, transaction model.Transaction, fees ...model.Transaction) ([]int64, error) {
+	if transaction.TransactionType != "debit" {
+		return nil, fmt.Errorf("incorrect transaction type %s", transaction.TransactionType)
+	}
+	if limits.MaxDebit > 0 && transaction.Amount > limits.MaxDebit {
+		return nil, ErrDebitLimit
+	}
+	return d.insertTransactions(append([]model.Transaction{transaction}, fees...), true, func(tx *sql.Tx) error {
+		return d.checkDailyLimits(tx, transaction, limits, since)
+	})
+}
+
+// InsertTransfer records the debit and the credit of a transfer atomically like InsertTransactions, once the debit
+// is checked against the spend limits of its account the same way InsertDebit checks them.
+func (d sqlDs) InsertTransfer(limits model.SpendLimits, since time.Time, debit model.Transaction, credit model.Transaction) ([]int64, error) {
+	if debit.TransactionType != "debit" {
+		return nil, fmt.Errorf("incorrect transaction type %s", debit.TransactionType)
+	}
+	return d.insertTransactions([]model.Transaction{debit, credit}, false, func(tx *sql.Tx) error {
+		return d.checkSpendLimits(tx, debit, limits, since)
+	})
+}
+
+// checkSpendLimits checks the debit against the single debit limit and the daily limits, the account must be locked.
+func (d sqlDs) checkSpendLimits(tx *sql.Tx, debit model.Transaction, limits model.SpendLimits, since time.Time) error {
+	if limits.MaxDebit > 0 && debit.Amount > limits.MaxDebit {
+		return ErrDebitLimit
+	}
+	return d.checkDailyLimits(tx, debit, limits, since)
+}
+
+func (d sqlDs) checkDailyLimits(tx *sql.Tx, debit model.Transaction, limits model.SpendLimits, since time.Time) error {
+	if limits.MaxDailyDebit == 0 && limits.MaxDailyCount == 0 {
+		return nil
+	}
+	var total model.Money
+	var count int
+	q := fmt.Sprintf("SELECT COALESCE(SUM(amount), 0), COUNT(*) FROM %s WHERE account_number = ? AND transaction_type = 'debit' AND reversal_of = 0 AND fee_of = 0 AND created_on >= ?;", d.transactionTable)
+	err := tx.QueryRow(q, debit.AccountNumber, since).Scan(&total, &count)
 	if err != nil {
 		return err
 	}
+	if limits.MaxDailyDebit > 0 && total+debit.Amount > limits.MaxDailyDebit {
+		return ErrDailyDebitLimit
+	}
+	if limits.MaxDailyCount > 0 && count >= limits.MaxDailyCount {
+		return ErrDailyDebitCount
+	}
 	return nil
 }
+
+// insertTransactions posts the transactions in order, with feesOfFirst every entry after the first one is a fee on it.
+// The check runs once the accounts are locked, before anything is posted.
+func (d sqlDs) insertTransactions(transactions []model.Transaction, feesOfFirst bool, check func(tx *sql.Tx) error) ([]int64, error) {
+	var accounts []int
+	for _, transaction := range transactions {
+		if transaction.TransactionType != "debit" && transaction.TransactionType != "credit" {
+			return nil, fmt.Errorf("incorrect transaction type %s", transaction.TransactionType)
+		}
+		accounts = append(accounts, transaction.AccountNumber)
+	}
+	sort.Ints(accounts)
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	locked := make(map[int]model.Account)
+	for _, accountNumber := range accounts {
+		if _, ok := locked[accountNumber]; ok {
+			continue
+		}
+		locked[accountNumber], err = d.lockAccount(tx, accountNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if check != nil {
+		err = check(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ids, err := d.postTransactions(tx, locked, transactions, feesOfFirst)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// postTransactions posts the transactions on the locked accounts in order, once each is checked against the status
+// and the available balance the account is left with by the ones before it.
+func (d sqlDs) postTransactions(tx *sql.Tx, locked map[int]model.Account, transactions []model.Transaction, feesOfFirst bool) ([]int64, error) {
+	var ids []int64
+	for _, transaction := range transactions {
+		account := locked[transaction.AccountNumber]
+		if transaction.Currency == "" {
+			transaction.Currency = account.Currency
+		}
+		if transaction.Currency != account.Currency {
+			return nil, ErrCurrencyMismatch
+		}
+		err := checkStatus(account, transaction.TransactionType)
+		if err != nil {
+			return nil, err
+		}
+		// the row is locked, so the balance cannot change between this check and the commit
+		if transaction.TransactionType == "debit" {
+			if transaction.Amount > account.AvailableBalance() {
+				return nil, ErrInsufficientFunds
+			}
+			account.Spends += transaction.Amount
+		} else {
+			account.Income += transaction.Amount
+		}
+		locked[transaction.AccountNumber] = account
+		if feesOfFirst && len(ids) > 0 {
+			transaction.FeeOf = ids[0]
+		}
+		id, err := d.postTransaction(tx, transaction)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// UpdateServices applies the change of the services of the account and debits the fees charged for it within a
+// single database transaction, so the services never change without the fees being paid and the other way round.
+func (d sqlDs) UpdateServices(accountNumber int, filterSet map[string]interface{}, fees ...model.Transaction) ([]int64, error) {
+	for _, fee := range fees {
+		if fee.AccountNumber != accountNumber || fee.TransactionType != "debit" {
+			return nil, fmt.Errorf("incorrect %s of %d on account %d charged for account %d", fee.TransactionType, fee.Amount, fee.AccountNumber, accountNumber)
+		}
+	}
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	account, err := d.lockAccount(tx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := d.postTransactions(tx, map[int]model.Account{accountNumber: account}, fees, false)
+	if err != nil {
+		return nil, err
+	}
+	q := fmt.Sprintf("UPDATE %s SET %s WHERE account_number = ?;", d.table, queryFromMap(filterSet, " , "))
+	_, err = tx.Exec(q, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	err = d.recordHistory(tx, "account_number = ?", accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// lockAccount locks the account row for the rest of the database transaction and returns its currency, totals and status.
+func (d sqlDs) lockAccount(tx *sql.Tx, accountNumber int) (model.Account, error) {
+	account := model.Account{AccountNumber: accountNumber}
+	q := fmt.Sprintf("SELECT currency, income, spends, overdraft_limit, held, status FROM %s WHERE account_number = ? FOR UPDATE;", d.table)
+	err := tx.QueryRow(q, accountNumber).Scan(&account.Currency, &account.Income, &account.Spends, &account.OverdraftLimit, &account.Held, &account.Status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return account, ErrAccountNotFound
+		}
+		return account, err
+	}
+	return account, nil
+}
+
+// checkStatus returns why the locked account does not take a posting of the transaction type, if it does not.
+func checkStatus(account model.Account, transactionType string) error {
+	if account.Accepts(transactionType) {
+		return nil
+	}
+	switch account.Status {
+	case model.AccountFrozen:
+		return ErrAccountFrozen
+	case model.AccountClosed:
+		return ErrAccountClosed
+	}
+	return ErrAccountNotActive
+}
+
+// SetAccountStatus moves the account to the status and returns the status it left, it returns
+// ErrInvalidTransition when the lifecycle does not allow the move. An account is only closed once
+// its balance is zero and nothing is held on it.
+func (d sqlDs) SetAccountStatus(accountNumber int, status string) (string, error) {
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	account, err := d.lockAccount(tx, accountNumber)
+	if err != nil {
+		return "", err
+	}
+	if !model.CanTransition(account.Status, status) {
+		return account.Status, ErrInvalidTransition
+	}
+	if status == model.AccountClosed {
+		if account.Held != 0 {
+			return account.Status, ErrHoldsNotSettled
+		}
+		if account.Balance() != 0 {
+			return account.Status, ErrBalanceNotSettled
+		}
+	}
+	q := fmt.Sprintf("UPDATE %s SET status = ? WHERE account_number = ?;", d.table)
+	_, err = tx.Exec(q, status, accountNumber)
+	if err != nil {
+		return account.Status, err
+	}
+	err = d.recordHistory(tx, "account_number = ?", accountNumber)
+	if err != nil {
+		return account.Status, err
+	}
+	return account.Status, tx.Commit()
+}
+
+// postTransaction applies the transaction to an account already locked by the database transaction.
+// A reversal carries the opposite type of the original entry and takes its amount back off the column the original added to.
+func (d sqlDs) postTransaction(tx *sql.Tx, transaction model.Transaction) (int64, error) {
+	column := "income"
+	if transaction.TransactionType == "debit" {
+		column = "spends"
+	}
+	operator := "+"
+	if transaction.ReversalOf != 0 {
+		operator = "-"
+		if column == "income" {
+			column = "spends"
+		} else {
+			column = "income"
+		}
+	}
+	q := fmt.Sprintf("UPDATE %s SET %s = %s %s CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;", d.table, column, column, operator)
+	_, err := tx.Exec(q, transaction.Amount, transaction.AccountNumber)
+	if err != nil {
+		return 0, err
+	}
+	q = fmt.Sprintf("INSERT INTO %s(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)", d.transactionTable)
+	result, err := tx.Exec(q, transaction.AccountNumber, transaction.Amount, transaction.Currency, transaction.OriginalAmount, transaction.OriginalCurrency, transaction.TransactionType, transaction.Reference, transaction.Category, transaction.Merchant, transaction.ReversalOf, transaction.FeeOf)
+	if err != nil {
+		return 0, err
+	}
+	transaction.Id, err = result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	var args []interface{}
+	for _, leg := range transaction.Legs() {
+		args = append(args, leg.TransactionId, leg.AccountNumber, leg.InternalAccount, leg.Direction, leg.Amount, leg.Currency)
+	}
+	q = fmt.Sprintf("INSERT INTO %s(transaction_id, account_number, internal_account, direction, amount, currency) VALUES(?,?,?,?,?,?),(?,?,?,?,?,?)", d.journalTable)
+	_, err = tx.Exec(q, args...)
+	if err != nil {
+		return 0, err
+	}
+	err = d.recordHistory(tx, "account_number = ?", transaction.AccountNumber)
+	if err != nil {
+		return 0, err
+	}
+	return transaction.Id, nil
+}
+
+// contraOf returns the internal account the transaction was balanced against, entries posted before the journal
+// existed cleared through the suspense account.
+func (d sqlDs) contraOf(tx *sql.Tx, transactionId int64) (string, error) {
+	var contra string
+	q := fmt.Sprintf("SELECT internal_account FROM %s WHERE transaction_id = ? AND internal_account <> '' LIMIT 1;", d.journalTable)
+	err := tx.QueryRow(q, transactionId).Scan(&contra)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.LedgerSuspense, nil
+	}
+	return contra, err
+}
+
+// ReverseTransaction records a compensating entry linked to the original transaction and returns its id.
+// A zero amount reverses whatever is left of the original.
+func (d sqlDs) ReverseTransaction(transactionId int64, amount model.Money, reference string) (int64, error) {
+	var original model.Transaction
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	q := fmt.Sprintf("SELECT account_number, amount, currency, transaction_type, category, merchant, reversal_of, reversed_amount FROM %s WHERE transaction_id = ? FOR UPDATE;", d.transactionTable)
+	err = tx.QueryRow(q, transactionId).Scan(&original.AccountNumber, &original.Amount, &original.Currency, &original.TransactionType, &original.Category, &original.Merchant, &original.ReversalOf, &original.ReversedAmount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrTransactionNotFound
+		}
+		return 0, err
+	}
+	if original.ReversalOf != 0 {
+		return 0, ErrReversalOfReversal
+	}
+	remaining := original.Amount - original.ReversedAmount
+	if remaining <= 0 {
+		return 0, ErrAlreadyReversed
+	}
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount > remaining {
+		return 0, ErrReversalExceedsAmount
+	}
+	account, err := d.lockAccount(tx, original.AccountNumber)
+	if err != nil {
+		return 0, err
+	}
+	// the reversal keeps the category of the original so refunds are taken off the spends of that category
+	reversal := model.Transaction{
+		AccountNumber:   original.AccountNumber,
+		Amount:          amount,
+		Currency:        original.Currency,
+		TransactionType: "debit",
+		Reference:       reference,
+		Category:        original.Category,
+		Merchant:        original.Merchant,
+		ReversalOf:      transactionId,
+	}
+	if original.TransactionType == "debit" {
+		reversal.TransactionType = "credit"
+	}
+	err = checkStatus(account, reversal.TransactionType)
+	if err != nil {
+		return 0, err
+	}
+	// a refunded credit is debited like any other debit, the row is locked so the balance cannot change until the commit
+	if reversal.TransactionType == "debit" && amount > account.AvailableBalance() {
+		return 0, ErrInsufficientFunds
+	}
+	// the reversal unwinds the legs of the original, so it is balanced against the same internal account
+	reversal.Contra, err = d.contraOf(tx, transactionId)
+	if err != nil {
+		return 0, err
+	}
+	id, err := d.postTransaction(tx, reversal)
+	if err != nil {
+		return 0, err
+	}
+	q = fmt.Sprintf("UPDATE %s SET reversed_amount = reversed_amount + CAST(? AS DECIMAL(18,2)) WHERE transaction_id = ?;", d.transactionTable)
+	_, err = tx.Exec(q, amount, transactionId)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetTransactions returns the ledger entries of an account matching the filter, newest first.
+func (d sqlDs) GetTransactions(filter model.TransactionFilter) ([]model.Transaction, error) {
+	var transaction model.Transaction
+	var transactions []model.Transaction
+	q := fmt.Sprintf("SELECT transaction_id, account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of, reversed_amount, created_on FROM %s WHERE account_number = ?", d.transactionTable)
+	args := []interface{}{filter.AccountNumber}
+	if filter.Cursor > 0 {
+		q += " AND transaction_id < ?"
+		args = append(args, filter.Cursor)
+	}
+	if !filter.From.IsZero() {
+		q += " AND created_on >= ?"
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		q += " AND created_on <= ?"
+		args = append(args, filter.To)
+	}
+	if filter.TransactionType != "" {
+		q += " AND transaction_type = ?"
+		args = append(args, filter.TransactionType)
+	}
+	if filter.MinAmount > 0 {
+		q += " AND amount >= ?"
+		args = append(args, filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		q += " AND amount <= ?"
+		args = append(args, filter.MaxAmount)
+	}
+	q += " ORDER BY transaction_id DESC"
+	if filter.Limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+	q += ";"
+	rows, err := d.sqlSvc.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = rows.Scan(&transaction.Id, &transaction.AccountNumber, &transaction.Amount, &transaction.Currency, &transaction.OriginalAmount, &transaction.OriginalCurrency, &transaction.TransactionType, &transaction.Reference, &transaction.Category, &transaction.Merchant, &transaction.ReversalOf, &transaction.FeeOf, &transaction.ReversedAmount, &transaction.CreatedOn)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, rows.Err()
+}
+
+// GetBalance sums the ledger entries of the account posted before the given time.
+func (d sqlDs) GetBalance(accountNumber int, before time.Time) (model.Money, error) {
+	var balance model.Money
+	q := fmt.Sprintf("SELECT COALESCE(SUM(CASE WHEN transaction_type = 'debit' THEN -amount ELSE amount END), 0) FROM %s WHERE account_number = ? AND created_on < ?;", d.transactionTable)
+	err := d.sqlSvc.QueryRow(q, accountNumber, before).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// GetTransactionTotals groups the transactions created in [from, to) by month and category. A reversal is
+// dated when it was posted, so a refund reduces the spends of the month it came in.
+func (d sqlDs) GetTransactionTotals(accountNumber int, from time.Time, to time.Time) ([]model.TransactionTotals, error) {
+	q := fmt.Sprintf("SELECT DATE_FORMAT(created_on, '%%Y-%%m') AS month, category, "+
+		"COALESCE(SUM(CASE WHEN transaction_type = 'credit' AND reversal_of = 0 THEN amount WHEN transaction_type = 'debit' AND reversal_of <> 0 THEN -amount ELSE 0 END), 0), "+
+		"COALESCE(SUM(CASE WHEN transaction_type = 'debit' AND reversal_of = 0 THEN amount WHEN transaction_type = 'credit' AND reversal_of <> 0 THEN -amount ELSE 0 END), 0) "+
+		"FROM %s WHERE account_number = ? AND created_on >= ? AND created_on < ? GROUP BY month, category ORDER BY month, category;", d.transactionTable)
+	rows, err := d.sqlSvc.Query(q, accountNumber, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var totals []model.TransactionTotals
+	for rows.Next() {
+		var t model.TransactionTotals
+		err = rows.Scan(&t.Month, &t.Category, &t.Income, &t.Spends)
+		if err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+// ledgerTotals sums the income and spends of the ledger entries the way postTransaction applies them to the
+// account row, a reversal takes its amount off the column of the entry it reverses.
+const ledgerTotals = "COALESCE(SUM(CASE WHEN transaction_type = 'credit' AND reversal_of = 0 THEN amount WHEN transaction_type = 'debit' AND reversal_of <> 0 THEN -amount ELSE 0 END), 0) AS income, " +
+	"COALESCE(SUM(CASE WHEN transaction_type = 'debit' AND reversal_of = 0 THEN amount WHEN transaction_type = 'credit' AND reversal_of <> 0 THEN -amount ELSE 0 END), 0) AS spends"
+
+// GetLedgerMismatches returns the accounts, or the given account when accountNumber is not zero, whose income
+// or spends differ from the totals of their ledger. The single statement reads one snapshot, so postings in
+// flight are seen either on both sides or on neither.
+func (d sqlDs) GetLedgerMismatches(accountNumber int) ([]model.ReconciliationLine, error) {
+	q := fmt.Sprintf("SELECT a.account_number, a.income, a.spends, COALESCE(t.income, 0), COALESCE(t.spends, 0) FROM %s a "+
+		"LEFT JOIN (SELECT account_number, %s FROM %s GROUP BY account_number) t ON t.account_number = a.account_number "+
+		"WHERE (? = 0 OR a.account_number = ?) AND (a.income <> COALESCE(t.income, 0) OR a.spends <> COALESCE(t.spends, 0)) ORDER BY a.account_number;", d.table, ledgerTotals, d.transactionTable)
+	rows, err := d.sqlSvc.Query(q, accountNumber, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var lines []model.ReconciliationLine
+	for rows.Next() {
+		line := model.ReconciliationLine{Status: model.ReconcileMismatch}
+		err = rows.Scan(&line.AccountNumber, &line.Income, &line.Spends, &line.LedgerIncome, &line.LedgerSpends)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// RepairTotals sets the income and spends of the account to the totals of its ledger. The totals are summed
+// again under the account row lock, which every posting takes first, so nothing is posted in between. An account
+// without ledger entries is refused with ErrNoLedgerEntries, its totals were never posted and would be set to 0.
+func (d sqlDs) RepairTotals(accountNumber int) (model.ReconciliationLine, error) {
+	line := model.ReconciliationLine{AccountNumber: accountNumber, Status: model.ReconcileRepaired}
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return line, err
+	}
+	defer tx.Rollback()
+	account, err := d.lockAccount(tx, accountNumber)
+	if err != nil {
+		return line, err
+	}
+	line.Income, line.Spends = account.Income, account.Spends
+	var entries int
+	q := fmt.Sprintf("SELECT COUNT(*), %s FROM %s WHERE account_number = ?;", ledgerTotals, d.transactionTable)
+	err = tx.QueryRow(q, accountNumber).Scan(&entries, &line.LedgerIncome, &line.LedgerSpends)
+	if err != nil {
+		return line, err
+	}
+	if entries == 0 {
+		line.Status = model.ReconcileMismatch
+		return line, ErrNoLedgerEntries
+	}
+	q = fmt.Sprintf("UPDATE %s SET income = CAST(? AS DECIMAL(18,2)), spends = CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;", d.table)
+	_, err = tx.Exec(q, line.LedgerIncome, line.LedgerSpends, accountNumber)
+	if err != nil {
+		return line, err
+	}
+	err = d.recordHistory(tx, "account_number = ?", accountNumber)
+	if err != nil {
+		return line, err
+	}
+	return line, tx.Commit()
+}
+
+// GetTrialBalance sums the debit and credit legs of the journal per internal account and currency, the legs on
+// customer accounts are summed together under an empty account.
+func (d sqlDs) GetTrialBalance() ([]model.TrialBalanceLine, error) {
+	q := fmt.Sprintf("SELECT internal_account, currency, "+
+		"COALESCE(SUM(CASE WHEN direction = 'debit' THEN amount ELSE 0 END), 0), "+
+		"COALESCE(SUM(CASE WHEN direction = 'credit' THEN amount ELSE 0 END), 0) "+
+		"FROM %s GROUP BY internal_account, currency ORDER BY currency, internal_account;", d.journalTable)
+	rows, err := d.sqlSvc.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var lines []model.TrialBalanceLine
+	for rows.Next() {
+		var line model.TrialBalanceLine
+		err = rows.Scan(&line.Account, &line.Currency, &line.Debits, &line.Credits)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// GetJournalMismatches returns the currencies in which the income less the spends of the accounts differ from the
+// customer legs of the journal, together with the ledger entries posted before the journal existed, which have no legs.
+func (d sqlDs) GetJournalMismatches() ([]model.JournalLine, error) {
+	q := fmt.Sprintf("SELECT c.currency, COALESCE(a.net, 0), COALESCE(j.net, 0) + COALESCE(l.net, 0) "+
+		"FROM (SELECT currency FROM %s UNION SELECT currency FROM %s WHERE internal_account = '' UNION SELECT currency FROM %s) c "+
+		"LEFT JOIN (SELECT currency, SUM(income - spends) AS net FROM %s GROUP BY currency) a ON a.currency = c.currency "+
+		"LEFT JOIN (SELECT currency, SUM(CASE WHEN direction = 'credit' THEN amount ELSE -amount END) AS net FROM %s WHERE internal_account = '' GROUP BY currency) j ON j.currency = c.currency "+
+		"LEFT JOIN (SELECT t.currency, SUM(CASE WHEN t.transaction_type = 'credit' THEN t.amount ELSE -t.amount END) AS net FROM %s t "+
+		"WHERE NOT EXISTS (SELECT 1 FROM %s g WHERE g.transaction_id = t.transaction_id) GROUP BY t.currency) l ON l.currency = c.currency "+
+		"WHERE COALESCE(a.net, 0) <> COALESCE(j.net, 0) + COALESCE(l.net, 0) ORDER BY c.currency;",
+		d.table, d.journalTable, d.transactionTable, d.table, d.journalTable, d.transactionTable, d.journalTable)
+	rows, err := d.sqlSvc.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var lines []model.JournalLine
+	for rows.Next() {
+		var line model.JournalLine
+		err = rows.Scan(&line.Currency, &line.Accounts, &line.Journal)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+func (d sqlDs) InsertStandingOrder(order model.StandingOrder) (int64, error) {
+	q := fmt.Sprintf("INSERT INTO %s(account_number, amount, currency, transaction_type, reference, schedule, end_date, next_run, status) VALUES(?,?,?,?,?,?,?,?,?)", d.standingOrderTable)
+	result, err := d.sqlSvc.Exec(q, order.AccountNumber, order.Amount, order.Currency, order.TransactionType, order.Reference, order.Schedule, order.EndDate, order.NextRun, order.Status)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const standingOrderColumns = "standing_order_id, account_number, amount, currency, transaction_type, reference, schedule, end_date, next_run, last_run, last_transaction_id, last_error, status, created_on"
+
+// GetStandingOrders returns every standing order of the account whatever its status, newest first.
+func (d sqlDs) GetStandingOrders(accountNumber int) ([]model.StandingOrder, error) {
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE account_number = ? ORDER BY standing_order_id DESC;", standingOrderColumns, d.standingOrderTable)
+	return d.queryStandingOrders(q, accountNumber)
+}
+
+// GetDueStandingOrders returns the active standing orders whose next run is not after now, oldest run first.
+func (d sqlDs) GetDueStandingOrders(now time.Time, limit int) ([]model.StandingOrder, error) {
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE status = ? AND next_run <= ? ORDER BY next_run LIMIT ?;", standingOrderColumns, d.standingOrderTable)
+	return d.queryStandingOrders(q, model.StandingOrderActive, now, limit)
+}
+
+func (d sqlDs) queryStandingOrders(q string, args ...interface{}) ([]model.StandingOrder, error) {
+	var orders []model.StandingOrder
+	rows, err := d.sqlSvc.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var order model.StandingOrder
+		err = rows.Scan(&order.Id, &order.AccountNumber, &order.Amount, &order.Currency, &order.TransactionType, &order.Reference, &order.Schedule, &order.EndDate, &order.NextRun, &order.LastRun, &order.LastTransactionId, &order.LastError, &order.Status, &order.CreatedOn)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+// AdvanceStandingOrder moves the order to its next run before it is posted, it reports false when the order
+// was advanced or cancelled in the meantime so every run is posted at most once even with several instances.
+func (d sqlDs) AdvanceStandingOrder(order model.StandingOrder, nextRun time.Time, status string) (bool, error) {
+	q := fmt.Sprintf("UPDATE %s SET next_run = ?, status = ? WHERE standing_order_id = ? AND status = ? AND next_run = ?;", d.standingOrderTable)
+	result, err := d.sqlSvc.Exec(q, nextRun, status, order.Id, model.StandingOrderActive, order.NextRun)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RecordStandingOrderRun keeps the outcome of the latest run, runErr is empty when the transaction was posted.
+func (d sqlDs) RecordStandingOrderRun(id int64, run time.Time, transactionId int64, runErr string) error {
+	q := fmt.Sprintf("UPDATE %s SET last_run = ?, last_transaction_id = ?, last_error = ? WHERE standing_order_id = ?;", d.standingOrderTable)
+	_, err := d.sqlSvc.Exec(q, run, transactionId, runErr, id)
+	return err
+}
+
+func (d sqlDs) CancelStandingOrder(id int64, accountNumber int) error {
+	q := fmt.Sprintf("UPDATE %s SET status = ? WHERE standing_order_id = ? AND account_number = ? AND status = ?;", d.standingOrderTable)
+	result, err := d.sqlSvc.Exec(q, model.StandingOrderCancelled, id, accountNumber, model.StandingOrderActive)
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrStandingOrderNotFound
+	}
+	return nil
+}
+
+// InsertBudget adds a budget to the account, an account holds at most one budget per category.
+func (d sqlDs) InsertBudget(budget model.Budget) (int64, error) {
+	q := fmt.Sprintf("INSERT IGNORE INTO %s(account_number, category, amount) VALUES(?,?,?)", d.budgetTable)
+	result, err := d.sqlSvc.Exec(q, budget.AccountNumber, budget.Category, budget.Limit)
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, ErrBudgetExists
+	}
+	return result.LastInsertId()
+}
+
+// GetBudgets returns the budgets of the account ordered by category, the overall budget comes first.
+func (d sqlDs) GetBudgets(accountNumber int) ([]model.Budget, error) {
+	var budgets []model.Budget
+	q := fmt.Sprintf("SELECT budget_id, account_number, category, amount, created_on FROM %s WHERE account_number = ? ORDER BY category;", d.budgetTable)
+	rows, err := d.sqlSvc.Query(q, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var budget model.Budget
+		err = rows.Scan(&budget.Id, &budget.AccountNumber, &budget.Category, &budget.Limit, &budget.CreatedOn)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+	return budgets, rows.Err()
+}
+
+func (d sqlDs) UpdateBudget(budget model.Budget) error {
+	q := fmt.Sprintf("UPDATE %s SET amount = ? WHERE budget_id = ? AND account_number = ?;", d.budgetTable)
+	_, err := d.sqlSvc.Exec(q, budget.Limit, budget.Id, budget.AccountNumber)
+	return err
+}
+
+func (d sqlDs) DeleteBudget(id int64, accountNumber int) error {
+	q := fmt.Sprintf("DELETE FROM %s WHERE budget_id = ? AND account_number = ?;", d.budgetTable)
+	result, err := d.sqlSvc.Exec(q, id, accountNumber)
+	if err != nil {
+		return err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// InsertHold reserves the amount on the account, the held total of the account row is raised in the same
+// database transaction so every debit checked against the available balance already sees the hold.
+// The amount is checked against the spend limits like a debit, pending holds do not count towards the daily limits.
+func (d sqlDs) InsertHold(limits model.SpendLimits, since time.Time, hold model.Hold) (int64, error) {
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	account, err := d.lockAccount(tx, hold.AccountNumber)
+	if err != nil {
+		return 0, err
+	}
+	err = d.checkSpendLimits(tx, model.Transaction{AccountNumber: hold.AccountNumber, Amount: hold.Amount}, limits, since)
+	if err != nil {
+		return 0, err
+	}
+	if hold.Currency == "" {
+		hold.Currency = account.Currency
+	}
+	if hold.Currency != account.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+	err = checkStatus(account, "debit")
+	if err != nil {
+		return 0, err
+	}
+	if hold.Amount > account.AvailableBalance() {
+		return 0, ErrInsufficientFunds
+	}
+	q := fmt.Sprintf("UPDATE %s SET held = held + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;", d.table)
+	_, err = tx.Exec(q, hold.Amount, hold.AccountNumber)
+	if err != nil {
+		return 0, err
+	}
+	err = d.recordHistory(tx, "account_number = ?", hold.AccountNumber)
+	if err != nil {
+		return 0, err
+	}
+	q = fmt.Sprintf("INSERT INTO %s(account_number, amount, currency, reference, category, merchant, status, expires_on) VALUES(?,?,?,?,?,?,?,?)", d.holdTable)
+	result, err := tx.Exec(q, hold.AccountNumber, hold.Amount, hold.Currency, hold.Reference, hold.Category, hold.Merchant, model.HoldPending, hold.ExpiresOn)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+const holdColumns = "hold_id, account_number, amount, currency, reference, category, merchant, status, expires_on, captured_amount, transaction_id, created_on"
+
+// GetHolds returns the holds of the account with the given status, newest first. An empty status returns all of them.
+func (d sqlDs) GetHolds(accountNumber int, status string) ([]model.Hold, error) {
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE account_number = ?", holdColumns, d.holdTable)
+	args := []interface{}{accountNumber}
+	if status != "" {
+		q += " AND status = ?"
+		args = append(args, status)
+	}
+	q += " ORDER BY hold_id DESC;"
+	return d.queryHolds(q, args...)
+}
+
+// GetHold returns the hold with the id, whatever its status.
+func (d sqlDs) GetHold(id int64) (model.Hold, error) {
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE hold_id = ?;", holdColumns, d.holdTable)
+	holds, err := d.queryHolds(q, id)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	if len(holds) == 0 {
+		return model.Hold{}, ErrHoldNotFound
+	}
+	return holds[0], nil
+}
+
+// GetExpiredHolds returns the pending holds which expired before now, oldest expiry first.
+func (d sqlDs) GetExpiredHolds(now time.Time, limit int) ([]model.Hold, error) {
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE status = ? AND expires_on <= ? ORDER BY expires_on LIMIT ?;", holdColumns, d.holdTable)
+	return d.queryHolds(q, model.HoldPending, now, limit)
+}
+
+func (d sqlDs) queryHolds(q string, args ...interface{}) ([]model.Hold, error) {
+	var holds []model.Hold
+	rows, err := d.sqlSvc.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var hold model.Hold
+		err = rows.Scan(&hold.Id, &hold.AccountNumber, &hold.Amount, &hold.Currency, &hold.Reference, &hold.Category, &hold.Merchant, &hold.Status, &hold.ExpiresOn, &hold.CapturedAmount, &hold.TransactionId, &hold.CreatedOn)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+	return holds, rows.Err()
+}
+
+// lockHold locks a pending hold and the account it was placed on, in that order, for the rest of the database transaction.
+func (d sqlDs) lockHold(tx *sql.Tx, id int64) (model.Hold, model.Account, error) {
+	var hold model.Hold
+	q := fmt.Sprintf("SELECT hold_id, account_number, amount, currency, reference, category, merchant, status, expires_on FROM %s WHERE hold_id = ? FOR UPDATE;", d.holdTable)
+	err := tx.QueryRow(q, id).Scan(&hold.Id, &hold.AccountNumber, &hold.Amount, &hold.Currency, &hold.Reference, &hold.Category, &hold.Merchant, &hold.Status, &hold.ExpiresOn)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return hold, model.Account{}, ErrHoldNotFound
+		}
+		return hold, model.Account{}, err
+	}
+	if hold.Status != model.HoldPending {
+		return hold, model.Account{}, ErrHoldNotPending
+	}
+	account, err := d.lockAccount(tx, hold.AccountNumber)
+	if err != nil {
+		return hold, account, err
+	}
+	return hold, account, nil
+}
+
+// CaptureHold settles a pending hold into a debit and returns the posted transaction. A zero amount captures
+// the whole hold, a smaller amount settles the debit for less and gives the rest of the hold back to the account.
+// The hold already took the amount off the available balance, so the debit is not checked against it again.
+// It is checked against the spend limits again, other debits may have been posted since the hold was placed.
+func (d sqlDs) CaptureHold(limits model.SpendLimits, since time.Time, id int64, amount model.Money, reference string, now time.Time) (model.Transaction, error) {
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	defer tx.Rollback()
+	hold, account, err := d.lockHold(tx, id)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	if !hold.ExpiresOn.After(now) {
+		return model.Transaction{}, ErrHoldExpired
+	}
+	err = checkStatus(account, "debit")
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	if amount == 0 {
+		amount = hold.Amount
+	}
+	if amount > hold.Amount {
+		return model.Transaction{}, ErrCaptureExceedsHold
+	}
+	err = d.checkSpendLimits(tx, model.Transaction{AccountNumber: hold.AccountNumber, Amount: amount}, limits, since)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	if reference == "" {
+		reference = hold.Reference
+	}
+	q := fmt.Sprintf("UPDATE %s SET held = held - CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;", d.table)
+	_, err = tx.Exec(q, hold.Amount, hold.AccountNumber)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	err = d.recordHistory(tx, "account_number = ?", hold.AccountNumber)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	debit := model.Transaction{
+		AccountNumber:   hold.AccountNumber,
+		Amount:          amount,
+		Currency:        hold.Currency,
+		TransactionType: "debit",
+		Reference:       reference,
+		Category:        hold.Category,
+		Merchant:        hold.Merchant,
+	}
+	debit.Id, err = d.postTransaction(tx, debit)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	q = fmt.Sprintf("UPDATE %s SET status = ?, captured_amount = ?, transaction_id = ? WHERE hold_id = ?;", d.holdTable)
+	_, err = tx.Exec(q, model.HoldCaptured, amount, debit.Id, id)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	return debit, nil
+}
+
+// ReleaseHold ends a pending hold without a debit and gives the whole amount back to the account,
+// status is either released or expired.
+func (d sqlDs) ReleaseHold(id int64, status string) error {
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	hold, _, err := d.lockHold(tx, id)
+	if err != nil {
+		return err
+	}
+	q := fmt.Sprintf("UPDATE %s SET held = held - CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;", d.table)
+	_, err = tx.Exec(q, hold.Amount, hold.AccountNumber)
+	if err != nil {
+		return err
+	}
+	err = d.recordHistory(tx, "account_number = ?", hold.AccountNumber)
+	if err != nil {
+		return err
+	}
+	q = fmt.Sprintf("UPDATE %s SET status = ? WHERE hold_id = ?;", d.holdTable)
+	_, err = tx.Exec(q, status, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PostInterest records the interest of the month and credits it to the account within a single database transaction,
+// it returns ErrInterestPosted when the month was already posted for the account so interest is never paid twice.
+func (d sqlDs) PostInterest(accrual model.InterestAccrual) (int64, error) {
+	tx, err := d.sqlSvc.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	q := fmt.Sprintf("INSERT IGNORE INTO %s(account_number, period, currency, rate, day_count, amount) VALUES(?,?,?,?,?,?)", d.interestTable)
+	result, err := tx.Exec(q, accrual.AccountNumber, accrual.Period, accrual.Currency, accrual.Rate, accrual.DayCount, accrual.Amount)
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, ErrInterestPosted
+	}
+	var id int64
+	// a month without interest is still recorded so it is not computed again
+	if accrual.Amount > 0 {
+		account, err := d.lockAccount(tx, accrual.AccountNumber)
+		if err != nil {
+			return 0, err
+		}
+		err = checkStatus(account, "credit")
+		if err != nil {
+			return 0, err
+		}
+		id, err = d.postTransaction(tx, model.Transaction{
+			AccountNumber:   accrual.AccountNumber,
+			Amount:          accrual.Amount,
+			Currency:        accrual.Currency,
+			TransactionType: "credit",
+			Reference:       "interest " + accrual.Period,
+			Category:        "interest",
+			Contra:          model.LedgerInterestExpense,
+		})
+		if err != nil {
+			return 0, err
+		}
+		q = fmt.Sprintf("UPDATE %s SET transaction_id = ? WHERE account_number = ? AND period = ?;", d.interestTable)
+		_, err = tx.Exec(q, id, accrual.AccountNumber, accrual.Period)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetInterestAccruals returns the interest posted for the month (YYYY-MM) ordered by account number.
+func (d sqlDs) GetInterestAccruals(period string) ([]model.InterestAccrual, error) {
+	var accruals []model.InterestAccrual
+	q := fmt.Sprintf("SELECT account_number, period, currency, rate, day_count, amount, transaction_id, created_on FROM %s WHERE period = ? ORDER BY account_number;", d.interestTable)
+	rows, err := d.sqlSvc.Query(q, period)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var accrual model.InterestAccrual
+		err = rows.Scan(&accrual.AccountNumber, &accrual.Period, &accrual.Currency, &accrual.Rate, &accrual.DayCount, &accrual.Amount, &accrual.TransactionId, &accrual.CreatedOn)
+		if err != nil {
+			return nil, err
+		}
+		accruals = append(accruals, accrual)
+	}
+	return accruals, rows.Err()
+}
+
+// GetSpendLimits returns the limits overridden for the account, it returns nil when the account has none.
+func (d sqlDs) GetSpendLimits(accountNumber int) (*model.SpendLimitOverride, error) {
+	override := model.SpendLimitOverride{AccountNumber: accountNumber}
+	q := fmt.Sprintf("SELECT max_debit, max_daily_debit, max_daily_count, updated_on FROM %s WHERE account_number = ?;", d.spendLimitTable)
+	err := d.sqlSvc.QueryRow(q, accountNumber).Scan(&override.MaxDebit, &override.MaxDailyDebit, &override.MaxDailyCount, &override.UpdatedOn)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &override, nil
+}
+
+// UpsertSpendLimits replaces the limits overridden for the account.
+func (d sqlDs) UpsertSpendLimits(override model.SpendLimitOverride) error {
+	q := fmt.Sprintf("INSERT INTO %s(account_number, max_debit, max_daily_debit, max_daily_count) VALUES(?,?,?,?) ON DUPLICATE KEY UPDATE max_debit = VALUES(max_debit), max_daily_debit = VALUES(max_daily_debit), max_daily_count = VALUES(max_daily_count);", d.spendLimitTable)
+	_, err := d.sqlSvc.Exec(q, override.AccountNumber, override.MaxDebit, override.MaxDailyDebit, override.MaxDailyCount)
+	return err
+}
+
+// InsertIdempotencyKey reserves the key for the scope, it reports false when the key was already reserved.
+func (d sqlDs) InsertIdempotencyKey(record model.IdempotencyRecord) (bool, error) {
+	q := fmt.Sprintf("INSERT IGNORE INTO %s(idempotency_key, scope, request_hash, status, response, content_type) VALUES(?,?,?,?,?,?)", d.idempotencyTable)
+	result, err := d.sqlSvc.Exec(q, record.Key, record.Scope, record.RequestHash, record.Status, record.Response, record.ContentType)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+func (d sqlDs) GetIdempotencyKey(key string, scope string) (*model.IdempotencyRecord, error) {
+	var record model.IdempotencyRecord
+	q := fmt.Sprintf("SELECT idempotency_key, scope, request_hash, status, response, content_type, created_on FROM %s WHERE idempotency_key = ? AND scope = ?;", d.idempotencyTable)
+	err := d.sqlSvc.QueryRow(q, key, scope).Scan(&record.Key, &record.Scope, &record.RequestHash, &record.Status, &record.Response, &record.ContentType, &record.CreatedOn)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (d sqlDs) UpdateIdempotencyKey(record model.IdempotencyRecord) error {
+	q := fmt.Sprintf("UPDATE %s SET status = ?, response = ?, content_type = ? WHERE idempotency_key = ? AND scope = ?;", d.idempotencyTable)
+	_, err := d.sqlSvc.Exec(q, record.Status, record.Response, record.ContentType, record.Key, record.Scope)
+	return err
+}
+
+func (d sqlDs) DeleteIdempotencyKey(key string, scope string) error {
+	q := fmt.Sprintf("DELETE FROM %s WHERE idempotency_key = ? AND scope = ?;", d.idempotencyTable)
+	_, err := d.sqlSvc.Exec(q, key, scope)
+	return err
+}
+
+// DeleteExpiredIdempotencyKeys deletes the keys reserved before the given time and returns how many there were.
+func (d sqlDs) DeleteExpiredIdempotencyKeys(before time.Time) (int64, error) {
+	q := fmt.Sprintf("DELETE FROM %s WHERE created_on < ?;", d.idempotencyTable)
+	result, err := d.sqlSvc.Exec(q, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
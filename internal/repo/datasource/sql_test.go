@@ -1,6 +1,7 @@
 package datasource
 
 import (
+	"database/sql"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	_ "github.com/go-sql-driver/mysql"
@@ -13,25 +14,32 @@ import (
 	"time"
 )
 
+var lockColumns = []string{"currency", "income", "spends", "overdraft_limit", "held", "status"}
+
+var journal = regexp.QuoteMeta("INSERT INTO newTempJournal(transaction_id, account_number, internal_account, direction, amount, currency) VALUES(?,?,?,?,?,?),(?,?,?,?,?,?)")
+
+var history = regexp.QuoteMeta("INSERT INTO newTempHistory(account_number, income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status) SELECT account_number, income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status FROM newTemp WHERE account_number = ?;")
+
 func TestSqlDs_HealthCheck(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping testing due to unavailability of testing environment")
 	}
 	//include a failure case
 	dbcfg := svcCfg.DbCfg{
-		Port:      "9085",
-		Host:      "localhost",
-		Driver:    "mysql",
-		User:      "root",
-		Pass:      "pass",
-		DbName:    "useracc",
-		TableName: "newTemp",
+		Port:                 "9085",
+		Host:                 "localhost",
+		Driver:               "mysql",
+		User:                 "root",
+		Pass:                 "pass",
+		DbName:               "useracc",
+		TableName:            "newTemp",
+		TransactionTableName: "newTempTransactions",
 	}
 	dataBase := svcCfg.Connect(dbcfg, dbcfg.TableName)
 	svcConfig := svcCfg.SvcConfig{
 		DbSvc: svcCfg.DbSvc{Db: dataBase},
 	}
-	dB := NewSql(svcConfig.DbSvc, "newTemp")
+	dB := NewSql(svcConfig.DbSvc, dbcfg)
 
 	tests := []struct {
 		name        string
@@ -84,7 +92,7 @@ func TestGet(t *testing.T) {
 					sqlSvc: db,
 					table:  "newTemp",
 				}
-				mock.ExpectQuery("SELECT user_id, account_number, income, spends, created_on, updated_on, active_services, inactive_services FROM newTemp WHERE user_id = '1234' ORDER BY account_number;").WillReturnRows(sqlmock.NewRows([]string{"user_id", "account_number", "income", "spends", "created_on", "updated_on", "active_services", "inactive_services"}).AddRow("1234", 1, 0.00, 0.00, time.Now(), time.Now(), &model.Svc{"1": {}}, &model.Svc{"1": {}}).RowError(1, errors.New("")))
+				mock.ExpectQuery("SELECT user_id, account_number, income, spends, currency, account_type, overdraft_limit, held, created_on, updated_on, active_services, inactive_services, status, is_default FROM newTemp WHERE user_id = '1234' ORDER BY account_number;").WillReturnRows(sqlmock.NewRows([]string{"user_id", "account_number", "income", "spends", "currency", "account_type", "overdraft_limit", "held", "created_on", "updated_on", "active_services", "inactive_services", "status", "is_default"}).AddRow("1234", 1, 0.00, 0.00, "USD", "current", 0.00, 0.00, time.Now(), time.Now(), &model.Svc{"1": {}}, &model.Svc{"1": {}}, "active", true).RowError(1, errors.New("")))
 				return dB
 			},
 			validator: func(rows []model.Account, err error) {
@@ -133,7 +141,7 @@ func TestGet(t *testing.T) {
 					sqlSvc: db,
 					table:  "newTemp",
 				}
-				mock.ExpectQuery("SELECT user_id, account_number, income, spends, created_on, updated_on, active_services, inactive_services FROM newTemp WHERE user_id = '1234' ORDER BY account_number;").WillReturnRows(sqlmock.NewRows([]string{"user_id", "account_number", "income", "spends", "created_on", "updated_on", "active_services", "inactive_services"}).AddRow("1234", 1, 0.00, 0.00, time.Now(), time.Now(), &model.Svc{"1": {}}, &model.Svc{"1": {}}).AddRow("12345", 1, 0.00, 0.00, time.Now(), time.Now(), &model.Svc{"1": {}}, &model.Svc{"1": {}}))
+				mock.ExpectQuery("SELECT user_id, account_number, income, spends, currency, account_type, overdraft_limit, held, created_on, updated_on, active_services, inactive_services, status, is_default FROM newTemp WHERE user_id = '1234' ORDER BY account_number;").WillReturnRows(sqlmock.NewRows([]string{"user_id", "account_number", "income", "spends", "currency", "account_type", "overdraft_limit", "held", "created_on", "updated_on", "active_services", "inactive_services", "status", "is_default"}).AddRow("1234", 1, 0.00, 0.00, "USD", "current", 0.00, 0.00, time.Now(), time.Now(), &model.Svc{"1": {}}, &model.Svc{"1": {}}, "active", true).AddRow("12345", 1, 0.00, 0.00, "USD", "current", 0.00, 0.00, time.Now(), time.Now(), &model.Svc{"1": {}}, &model.Svc{"1": {}}, "active", true))
 				return dB
 			},
 			validator: func(rows []model.Account, err error) {
@@ -179,7 +187,7 @@ func TestGet(t *testing.T) {
 					sqlSvc: db,
 					table:  "newTemp",
 				}
-				mock.ExpectQuery("SELECT user_id, account_number, income, spends, created_on, updated_on, active_services, inactive_services FROM newTemp WHERE user_id = '1234' ORDER BY account_number;").WillReturnRows(sqlmock.NewRows([]string{"user_id", "account_number", "income", "spends", "created_on", "updated_on", "active_services", "inactive_services"}))
+				mock.ExpectQuery("SELECT user_id, account_number, income, spends, currency, account_type, overdraft_limit, held, created_on, updated_on, active_services, inactive_services, status, is_default FROM newTemp WHERE user_id = '1234' ORDER BY account_number;").WillReturnRows(sqlmock.NewRows([]string{"user_id", "account_number", "income", "spends", "currency", "account_type", "overdraft_limit", "held", "created_on", "updated_on", "active_services", "inactive_services", "status", "is_default"}))
 				return dB
 			},
 			validator: func(rows []model.Account, err error) {
@@ -202,7 +210,7 @@ func TestGet(t *testing.T) {
 					sqlSvc: db,
 					table:  "newTemp",
 				}
-				mock.ExpectQuery("SELECT user_id, account_number, income, spends, created_on, updated_on, active_services, inactive_services FROM newTemp WHERE user_id = '12345' ORDER BY account_number;").WillReturnRows(sqlmock.NewRows([]string{"user_id", "account_number", "income", "spends", "created_on", "updated_on", "active_services", "inactive_services"}).AddRow("12345	", 1, 0.00, "abc", time.Now(), time.Now(), &model.Svc{"1": {}}, &model.Svc{"1": {}}))
+				mock.ExpectQuery("SELECT user_id, account_number, income, spends, currency, account_type, overdraft_limit, held, created_on, updated_on, active_services, inactive_services, status, is_default FROM newTemp WHERE user_id = '12345' ORDER BY account_number;").WillReturnRows(sqlmock.NewRows([]string{"user_id", "account_number", "income", "spends", "currency", "account_type", "overdraft_limit", "held", "created_on", "updated_on", "active_services", "inactive_services", "status", "is_default"}).AddRow("12345	", 1, 0.00, "abc", "USD", "current", 0.00, 0.00, time.Now(), time.Now(), &model.Svc{"1": {}}, &model.Svc{"1": {}}, "active", true))
 				return dB
 			},
 			validator: func(rows []model.Account, err error) {
@@ -223,7 +231,7 @@ func TestGet(t *testing.T) {
 					sqlSvc: db,
 					table:  "newTemp",
 				}
-				mock.ExpectQuery("SELECT user_id, account_number, income, spends, created_on, updated_on, active_services, inactive_services FROM newTemp WHERE userid = '1234' ORDER BY account_number;").WillReturnError(errors.New("Unknown column"))
+				mock.ExpectQuery("SELECT user_id, account_number, income, spends, currency, account_type, overdraft_limit, held, created_on, updated_on, active_services, inactive_services, status, is_default FROM newTemp WHERE userid = '1234' ORDER BY account_number;").WillReturnError(errors.New("Unknown column"))
 				return dB
 			},
 			validator: func(rows []model.Account, err error) {
@@ -255,37 +263,48 @@ func TestGet(t *testing.T) {
 	}
 }
 
-//
 func TestInsert(t *testing.T) {
 	// table driven tests
 	tests := []struct {
-		name        string
-		tableName   string
-		data        model.Account
-		setupFunc   func() (sqlDs, sqlmock.Sqlmock)
-		cleanupFunc func()
-		filter      map[string]interface{}
-		validator   func(sqlmock.Sqlmock, error)
+		name          string
+		tableName     string
+		data          model.Account
+		accountNumber int
+		setupFunc     func() (sqlDs, sqlmock.Sqlmock)
+		cleanupFunc   func()
+		filter        map[string]interface{}
+		validator     func(sqlmock.Sqlmock, error)
 	}{
 		{
 			name: "SUCCESS:: Insert Article",
 			data: model.Account{
 				Id:               "1",
+				Currency:         "USD",
+				AccountType:      "savings",
 				ActiveServices:   &model.Svc{"1": {}},
 				InactiveServices: &model.Svc{},
+				Status:           model.AccountPending,
+				Default:          true,
 			},
+			accountNumber: 1,
 			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
 				db, mock, err := sqlmock.New()
 				if err != nil {
 					t.Fail()
 				}
 				dB := sqlDs{
-					sqlSvc: db,
-					table:  "newTemp",
+					sqlSvc:       db,
+					table:        "newTemp",
+					historyTable: "newTempHistory",
+					memberTable:  "newTempMembers",
 				}
-				m := mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTemp(user_id, active_services, inactive_services) VALUES(?,?,?)")).WithArgs("1", &model.Svc{"1": {}}, &model.Svc{})
+				mock.ExpectBegin()
+				m := mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTemp(user_id, currency, account_type, active_services, inactive_services, status, is_default) VALUES(?,?,?,?,?,?,?)")).WithArgs("1", "USD", "savings", &model.Svc{"1": {}}, &model.Svc{}, model.AccountPending, true)
 				m.WillReturnError(nil)
 				m.WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempMembers(account_number, user_id, role) VALUES(?,?,?);")).WithArgs(int64(1), sqlmock.AnyArg(), model.RoleOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 				return dB, mock
 			},
 			validator: func(mock sqlmock.Sqlmock, err error) {
@@ -303,21 +322,29 @@ func TestInsert(t *testing.T) {
 			name: "SUCCESS:: Insert Article:: Insert Article when data already present",
 			data: model.Account{
 				Id:               "2",
+				Currency:         "USD",
 				ActiveServices:   &model.Svc{"1": {}},
 				InactiveServices: &model.Svc{"2": {}},
 			},
+			accountNumber: 2,
 			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
 				db, mock, err := sqlmock.New()
 				if err != nil {
 					t.Fail()
 				}
 				dB := sqlDs{
-					sqlSvc: db,
-					table:  "newTemp",
+					sqlSvc:       db,
+					table:        "newTemp",
+					historyTable: "newTempHistory",
+					memberTable:  "newTempMembers",
 				}
-				m := mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTemp(user_id, active_services, inactive_services) VALUES(?,?,?)")).WithArgs("2", &model.Svc{"1": {}}, &model.Svc{"2": {}})
+				mock.ExpectBegin()
+				m := mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTemp(user_id, currency, account_type, active_services, inactive_services, status, is_default) VALUES(?,?,?,?,?,?,?)")).WithArgs("2", "USD", "", &model.Svc{"1": {}}, &model.Svc{"2": {}}, "", false)
 				m.WillReturnError(nil)
 				m.WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempMembers(account_number, user_id, role) VALUES(?,?,?);")).WithArgs(int64(2), sqlmock.AnyArg(), model.RoleOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 				return dB, mock
 			},
 			validator: func(mock sqlmock.Sqlmock, err error) {
@@ -335,6 +362,7 @@ func TestInsert(t *testing.T) {
 			name: "FAILURE:: insert :: sql error",
 			data: model.Account{
 				Id:               "2",
+				Currency:         "USD",
 				ActiveServices:   &model.Svc{"1": {}},
 				InactiveServices: nil,
 			},
@@ -344,12 +372,16 @@ func TestInsert(t *testing.T) {
 					t.Fail()
 				}
 				dB := sqlDs{
-					sqlSvc: db,
-					table:  "newTemp",
+					sqlSvc:       db,
+					table:        "newTemp",
+					historyTable: "newTempHistory",
+					memberTable:  "newTempMembers",
 				}
-				m := mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTemp(user_id, active_services, inactive_services) VALUES(?,?,?)")).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg())
+				mock.ExpectBegin()
+				m := mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTemp(user_id, currency, account_type, active_services, inactive_services, status, is_default) VALUES(?,?,?,?,?,?,?)")).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg())
 				m.WillReturnError(errors.New("sql error"))
 				m.WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 				return dB, mock
 			},
 			validator: func(mock sqlmock.Sqlmock, err error) {
@@ -359,17 +391,124 @@ func TestInsert(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "SUCCESS:: Insert Article:: allocated account number",
+			data: model.Account{
+				AccountNumber: 123456782,
+				Id:            "4",
+				Currency:      "USD",
+				Status:        model.AccountPending,
+			},
+			accountNumber: 123456782,
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:       db,
+					table:        "newTemp",
+					historyTable: "newTempHistory",
+					memberTable:  "newTempMembers",
+				}
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO newTemp(account_number, user_id, currency, account_type, active_services, inactive_services, status, is_default) VALUES(?,?,?,?,?,?,?,?)")).WithArgs(123456782, "4", "USD", "", sqlmock.AnyArg(), sqlmock.AnyArg(), model.AccountPending, false).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempMembers(account_number, user_id, role) VALUES(?,?,?);")).WithArgs(int64(123456782), sqlmock.AnyArg(), model.RoleOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(int64(123456782)).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return dB, mock
+			},
+			validator: func(mock sqlmock.Sqlmock, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+					return
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: insert :: allocated account number taken",
+			data: model.Account{
+				AccountNumber: 123456782,
+				Id:            "4",
+				Currency:      "USD",
+			},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:       db,
+					table:        "newTemp",
+					historyTable: "newTempHistory",
+					memberTable:  "newTempMembers",
+				}
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO newTemp(account_number, user_id, currency, account_type, active_services, inactive_services, status, is_default) VALUES(?,?,?,?,?,?,?,?)")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(mock sqlmock.Sqlmock, err error) {
+				if !errors.Is(err, ErrAccountNumberTaken) {
+					t.Errorf("Want: %v, Got: %v", ErrAccountNumberTaken, err)
+					return
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: insert :: history error rolls the account back",
+			data: model.Account{
+				Id:       "3",
+				Currency: "USD",
+			},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:       db,
+					table:        "newTemp",
+					historyTable: "newTempHistory",
+					memberTable:  "newTempMembers",
+				}
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTemp(user_id, currency, account_type, active_services, inactive_services, status, is_default) VALUES(?,?,?,?,?,?,?)")).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempMembers(account_number, user_id, role) VALUES(?,?,?);")).WithArgs(int64(3), sqlmock.AnyArg(), model.RoleOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(int64(3)).WillReturnError(errors.New("sql error"))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(mock sqlmock.Sqlmock, err error) {
+				if err == nil || err.Error() != "sql error" {
+					t.Errorf("Want: %v, Got: %v", "sql error", err)
+					return
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
 	}
 	// to execute the tests in the table
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			db, mock := tt.setupFunc()
 			// STEP 2: call the test function
-			err := db.Insert(tt.data)
+			accountNumber, err := db.Insert(tt.data)
 			// STEP 3: validation of output
 			if tt.validator != nil {
 				tt.validator(mock, err)
 			}
+			if accountNumber != tt.accountNumber {
+				t.Errorf("Want: %v, Got: %v", tt.accountNumber, accountNumber)
+			}
 			// STEP 4: clean up/remove up all instances for the specific test case
 			if tt.cleanupFunc != nil {
 				tt.cleanupFunc()
@@ -399,10 +538,14 @@ func TestUpdate(t *testing.T) {
 					t.Fail()
 				}
 				dB := sqlDs{
-					sqlSvc: db,
-					table:  "newTemp",
+					sqlSvc:       db,
+					table:        "newTemp",
+					historyTable: "newTempHistory",
 				}
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp  SET income = income+100 WHERE user_id = '100' ;")).WillReturnError(nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempHistory(account_number, income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status) SELECT account_number, income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status FROM newTemp WHERE user_id = '100';")).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 				return dB, mock
 			},
 			validator: func(err error, mock sqlmock.Sqlmock) {
@@ -426,10 +569,14 @@ func TestUpdate(t *testing.T) {
 					t.Fail()
 				}
 				dB := sqlDs{
-					sqlSvc: db,
-					table:  "newTemp",
+					sqlSvc:       db,
+					table:        "newTemp",
+					historyTable: "newTempHistory",
 				}
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET active_services = JSON_INSERT(active_services, '$.\"1\"', JSON_OBJECT()) , inactive_services = JSON_REMOVE(inactive_services, '$.\"1\"') WHERE user_id = '1233' ;")).WillReturnError(nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempHistory(account_number, income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status) SELECT account_number, income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status FROM newTemp WHERE user_id = '1233';")).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 				return dB, mock
 			},
 			validator: func(err error, mock sqlmock.Sqlmock) {
@@ -453,10 +600,13 @@ func TestUpdate(t *testing.T) {
 					t.Fail()
 				}
 				dB := sqlDs{
-					sqlSvc: db,
-					table:  "newTemp",
+					sqlSvc:       db,
+					table:        "newTemp",
+					historyTable: "newTempHistory",
 				}
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp  SET active_services = JSON_INSERT(active_services, '$.\"1\"', JSON_OBJECT()) WHERE abc = '1233' ;")).WillReturnError(errors.New("unknown column abc")).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectRollback()
 				return dB, mock
 			},
 			validator: func(err error, mock sqlmock.Sqlmock) {
@@ -490,3 +640,3176 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestInsertTransaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        model.Transaction
+		setupFunc   func() (sqlDs, sqlmock.Sqlmock)
+		cleanupFunc func()
+		validator   func(int64, error, sqlmock.Sqlmock)
+	}{
+		{
+			name: "SUCCESS:: InsertTransaction:: debit",
+			data: model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit", Reference: "ref"},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(10000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(10000), "USD", model.Money(0), "", "debit", "ref", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return dB, mock
+			},
+			validator: func(id int64, err error, mock sqlmock.Sqlmock) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+					return
+				}
+				if id != 7 {
+					t.Errorf("Want: %v, Got: %v", 7, id)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: InsertTransaction:: credit",
+			data: model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "credit"},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = income + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(10000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(10000), "USD", model.Money(0), "", "credit", "", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(8, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return dB, mock
+			},
+			validator: func(id int64, err error, mock sqlmock.Sqlmock) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+					return
+				}
+				if id != 8 {
+					t.Errorf("Want: %v, Got: %v", 8, id)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransaction:: account not found",
+			data: model.Transaction{AccountNumber: 2, Amount: 10000, TransactionType: "credit"},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"account_number"}))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(id int64, err error, mock sqlmock.Sqlmock) {
+				if !errors.Is(err, ErrAccountNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrAccountNotFound, err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransaction:: incorrect transaction type",
+			data: model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "abc"},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(id int64, err error, mock sqlmock.Sqlmock) {
+				if err == nil || !strings.Contains(err.Error(), "incorrect transaction type") {
+					t.Errorf("Want: %v, Got: %v", "incorrect transaction type", err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransaction:: begin error",
+			data: model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "credit"},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin().WillReturnError(errors.New("begin error"))
+				return dB, mock
+			},
+			validator: func(id int64, err error, mock sqlmock.Sqlmock) {
+				if err == nil || err.Error() != "begin error" {
+					t.Errorf("Want: %v, Got: %v", "begin error", err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransaction:: ledger insert error rolls back",
+			data: model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "credit"},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = income + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WillReturnError(errors.New("insert error"))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(id int64, err error, mock sqlmock.Sqlmock) {
+				if err == nil || err.Error() != "insert error" {
+					t.Errorf("Want: %v, Got: %v", "insert error", err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := tt.setupFunc()
+			// STEP 2: call the test function
+			id, err := db.InsertTransaction(tt.data)
+
+			// STEP 3: validation of output
+			if tt.validator != nil {
+				tt.validator(id, err, mock)
+			}
+
+			// STEP 4: clean up/remove up all instances for the specific test case
+			if tt.cleanupFunc != nil {
+				tt.cleanupFunc()
+			}
+		})
+	}
+}
+
+func TestInsertTransactions(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []model.Transaction
+		setupFunc   func() (sqlDs, sqlmock.Sqlmock)
+		cleanupFunc func()
+		validator   func([]int64, error, sqlmock.Sqlmock)
+	}{
+		{
+			name: "SUCCESS:: InsertTransactions:: accounts locked in ascending order",
+			data: []model.Transaction{
+				{AccountNumber: 2, Amount: 5000, TransactionType: "debit", Reference: "rent"},
+				{AccountNumber: 1, Amount: 5000, TransactionType: "credit", Reference: "rent"},
+			},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(2).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(5000), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(2, model.Money(5000), "USD", model.Money(0), "", "debit", "rent", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = income + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(5000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(5000), "USD", model.Money(0), "", "credit", "rent", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(4, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+					return
+				}
+				if !reflect.DeepEqual(ids, []int64{3, 4}) {
+					t.Errorf("Want: %v, Got: %v", []int64{3, 4}, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransactions:: destination account not found",
+			data: []model.Transaction{
+				{AccountNumber: 1, Amount: 5000, TransactionType: "debit"},
+				{AccountNumber: 2, Amount: 5000, TransactionType: "credit"},
+			},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(2).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if !errors.Is(err, ErrAccountNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrAccountNotFound, err)
+				}
+				if ids != nil {
+					t.Errorf("Want: %v, Got: %v", nil, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransactions:: currency does not match the account",
+			data: []model.Transaction{
+				{AccountNumber: 1, Amount: 5000, Currency: "EUR", TransactionType: "debit"},
+			},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if !errors.Is(err, ErrCurrencyMismatch) {
+					t.Errorf("Want: %v, Got: %v", ErrCurrencyMismatch, err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: InsertTransactions:: debit covered by overdraft",
+			data: []model.Transaction{
+				{AccountNumber: 1, Amount: 15000, TransactionType: "debit"},
+			},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "50.00", "100.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(15000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(15000), "USD", model.Money(0), "", "debit", "", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+				if !reflect.DeepEqual(ids, []int64{5}) {
+					t.Errorf("Want: %v, Got: %v", []int64{5}, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransactions:: debits beyond the overdraft",
+			data: []model.Transaction{
+				{AccountNumber: 1, Amount: 10000, TransactionType: "debit"},
+				{AccountNumber: 1, Amount: 5001, TransactionType: "debit"},
+			},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "50.00", "100.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(10000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(10000), "USD", model.Money(0), "", "debit", "", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if !errors.Is(err, ErrInsufficientFunds) {
+					t.Errorf("Want: %v, Got: %v", ErrInsufficientFunds, err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransactions:: credit leg fails rolls back debit",
+			data: []model.Transaction{
+				{AccountNumber: 1, Amount: 5000, TransactionType: "debit"},
+				{AccountNumber: 2, Amount: 5000, TransactionType: "credit"},
+			},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(2).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(5000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(5000), "USD", model.Money(0), "", "debit", "", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = income + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(5000), 2).WillReturnError(errors.New("update error"))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if err == nil || err.Error() != "update error" {
+					t.Errorf("Want: %v, Got: %v", "update error", err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := tt.setupFunc()
+			// STEP 2: call the test function
+			ids, err := db.InsertTransactions(tt.data...)
+
+			// STEP 3: validation of output
+			if tt.validator != nil {
+				tt.validator(ids, err, mock)
+			}
+
+			// STEP 4: clean up/remove up all instances for the specific test case
+			if tt.cleanupFunc != nil {
+				tt.cleanupFunc()
+			}
+		})
+	}
+}
+
+func TestInsertTransactionWithFees(t *testing.T) {
+	tests := []struct {
+		name        string
+		transaction model.Transaction
+		fees        []model.Transaction
+		setupFunc   func() (sqlDs, sqlmock.Sqlmock)
+		cleanupFunc func()
+		validator   func([]int64, error, sqlmock.Sqlmock)
+	}{
+		{
+			name:        "SUCCESS:: InsertTransactionWithFees:: fees linked to the transaction",
+			transaction: model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit", Reference: "rent"},
+			fees: []model.Transaction{
+				{AccountNumber: 1, Amount: 150, Currency: "USD", TransactionType: "debit", Reference: "card fee", Category: "fees"},
+				{AccountNumber: 1, Amount: 5, Currency: "USD", TransactionType: "debit", Reference: "sms alert", Category: "fees"},
+			},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(10000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(10000), "USD", model.Money(0), "", "debit", "rent", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(150), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(150), "USD", model.Money(0), "", "debit", "card fee", "fees", "", int64(0), int64(7)).WillReturnResult(sqlmock.NewResult(8, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(5), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(5), "USD", model.Money(0), "", "debit", "sms alert", "fees", "", int64(0), int64(7)).WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+					return
+				}
+				if !reflect.DeepEqual(ids, []int64{7, 8, 9}) {
+					t.Errorf("Want: %v, Got: %v", []int64{7, 8, 9}, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name:        "FAILURE:: InsertTransactionWithFees:: fee exceeds the funds left",
+			transaction: model.Transaction{AccountNumber: 1, Amount: 100000, TransactionType: "debit"},
+			fees:        []model.Transaction{{AccountNumber: 1, Amount: 150, Currency: "USD", TransactionType: "debit", Reference: "card fee", Category: "fees"}},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(100000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(100000), "USD", model.Money(0), "", "debit", "", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if !errors.Is(err, ErrInsufficientFunds) {
+					t.Errorf("Want: %v, Got: %v", ErrInsufficientFunds, err)
+				}
+				if ids != nil {
+					t.Errorf("Want: %v, Got: %v", nil, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := tt.setupFunc()
+			// STEP 2: call the test function
+			ids, err := db.InsertTransactionWithFees(tt.transaction, tt.fees...)
+
+			// STEP 3: validation of output
+			if tt.validator != nil {
+				tt.validator(ids, err, mock)
+			}
+
+			// STEP 4: clean up/remove up all instances for the specific test case
+			if tt.cleanupFunc != nil {
+				tt.cleanupFunc()
+			}
+		})
+	}
+}
+
+func TestUpdateServices(t *testing.T) {
+	lock := regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")
+	update := regexp.QuoteMeta("UPDATE newTemp SET active_services = JSON_INSERT(active_services, '$.\"sms\"', JSON_OBJECT()) WHERE account_number = ?;")
+	set := map[string]interface{}{"active_services": model.ColumnUpdate{UpdateSet: "JSON_INSERT(active_services, '$.\"sms\"', JSON_OBJECT())"}}
+	fee := model.Transaction{AccountNumber: 1, Amount: 500, TransactionType: "debit", Reference: "sms activation fee", Category: "fee"}
+	tests := []struct {
+		name        string
+		fees        []model.Transaction
+		setupFunc   func() (sqlDs, sqlmock.Sqlmock)
+		cleanupFunc func()
+		validator   func([]int64, error, sqlmock.Sqlmock)
+	}{
+		{
+			name: "SUCCESS:: UpdateServices:: fee posted with the change",
+			fees: []model.Transaction{fee},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(500), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(500), "USD", model.Money(0), "", "debit", "sms activation fee", "fee", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(update).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+					return
+				}
+				if !reflect.DeepEqual(ids, []int64{9}) {
+					t.Errorf("Want: %v, Got: %v", []int64{9}, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: UpdateServices:: no fee",
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(update).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+					return
+				}
+				if !reflect.DeepEqual(ids, []int64(nil)) {
+					t.Errorf("Want: %v, Got: %v", nil, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "FAILURE:: UpdateServices:: fee exceeds the funds",
+			fees: []model.Transaction{fee},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if !errors.Is(err, ErrInsufficientFunds) {
+					t.Errorf("Want: %v, Got: %v", ErrInsufficientFunds, err)
+				}
+				if !reflect.DeepEqual(ids, []int64(nil)) {
+					t.Errorf("Want: %v, Got: %v", nil, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "FAILURE:: UpdateServices:: fee rolled back when the change fails",
+			fees: []model.Transaction{fee},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(500), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(500), "USD", model.Money(0), "", "debit", "sms activation fee", "fee", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(update).WithArgs(1).WillReturnError(errors.New("update error"))
+				mock.ExpectRollback()
+				return dB, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if err == nil || err.Error() != "update error" {
+					t.Errorf("Want: %v, Got: %v", "update error", err)
+				}
+				if !reflect.DeepEqual(ids, []int64(nil)) {
+					t.Errorf("Want: %v, Got: %v", nil, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+		{
+			name: "FAILURE:: UpdateServices:: fee on another account",
+			fees: []model.Transaction{{AccountNumber: 2, Amount: 500, TransactionType: "debit"}},
+			setupFunc: func() (sqlDs, sqlmock.Sqlmock) {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				return sqlDs{sqlSvc: db, table: "newTemp"}, mock
+			},
+			validator: func(ids []int64, err error, mock sqlmock.Sqlmock) {
+				if err == nil {
+					t.Errorf("Want: %v, Got: %v", "error", nil)
+				}
+				if !reflect.DeepEqual(ids, []int64(nil)) {
+					t.Errorf("Want: %v, Got: %v", nil, ids)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := tt.setupFunc()
+			// STEP 2: call the test function
+			ids, err := db.UpdateServices(1, set, tt.fees...)
+
+			// STEP 3: validation of output
+			if tt.validator != nil {
+				tt.validator(ids, err, mock)
+			}
+
+			// STEP 4: clean up/remove up all instances for the specific test case
+			if tt.cleanupFunc != nil {
+				tt.cleanupFunc()
+			}
+		})
+	}
+}
+
+func TestGetTransactions(t *testing.T) {
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		filter      model.TransactionFilter
+		setupFunc   func() sqlDs
+		cleanupFunc func()
+		validator   func([]model.Transaction, error)
+	}{
+		{
+			name:   "SUCCESS:: GetTransactions:: all filters",
+			filter: model.TransactionFilter{AccountNumber: 1, Cursor: 10, Limit: 2, From: from, To: to, TransactionType: "debit", MinAmount: 100, MaxAmount: 10000},
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT transaction_id, account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of, reversed_amount, created_on FROM newTempTransactions WHERE account_number = ? AND transaction_id < ? AND created_on >= ? AND created_on <= ? AND transaction_type = ? AND amount >= ? AND amount <= ? ORDER BY transaction_id DESC LIMIT ?;")).
+					WithArgs(1, int64(10), from, to, "debit", model.Money(100), model.Money(10000), 2).
+					WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "account_number", "amount", "currency", "original_amount", "original_currency", "transaction_type", "reference", "category", "merchant", "reversal_of", "fee_of", "reversed_amount", "created_on"}).AddRow(9, 1, 10.5, "USD", 0, "", "debit", "ref", "groceries", "acme", 0, 0, 0, from).AddRow(8, 1, 20, "USD", 0, "", "debit", "", "", "", 0, 0, 0, from))
+				return dB
+			},
+			validator: func(rows []model.Transaction, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+					return
+				}
+				temp := []model.Transaction{
+					{Id: 9, AccountNumber: 1, Amount: 1050, Currency: "USD", TransactionType: "debit", Reference: "ref", Category: "groceries", Merchant: "acme", CreatedOn: from},
+					{Id: 8, AccountNumber: 1, Amount: 2000, Currency: "USD", TransactionType: "debit", CreatedOn: from},
+				}
+				if !reflect.DeepEqual(rows, temp) {
+					t.Errorf("Want: %v, Got: %v", temp, rows)
+				}
+			},
+		},
+		{
+			name:   "SUCCESS:: GetTransactions:: no filters",
+			filter: model.TransactionFilter{AccountNumber: 1},
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT transaction_id, account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of, reversed_amount, created_on FROM newTempTransactions WHERE account_number = ? ORDER BY transaction_id DESC;")).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "account_number", "amount", "currency", "original_amount", "original_currency", "transaction_type", "reference", "category", "merchant", "reversal_of", "fee_of", "reversed_amount", "created_on"}))
+				return dB
+			},
+			validator: func(rows []model.Transaction, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				if len(rows) != 0 {
+					t.Errorf("Want: %v, Got: %v", 0, len(rows))
+				}
+			},
+		},
+		{
+			name:   "FAILURE:: GetTransactions:: scan error",
+			filter: model.TransactionFilter{AccountNumber: 1},
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT transaction_id, account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of, reversed_amount, created_on FROM newTempTransactions WHERE account_number = ? ORDER BY transaction_id DESC;")).
+					WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "account_number", "amount", "currency", "original_amount", "original_currency", "transaction_type", "reference", "category", "merchant", "reversal_of", "fee_of", "reversed_amount", "created_on"}).AddRow(1, 1, "abc", "USD", 0, "", "debit", "", "", "", 0, 0, 0, from))
+				return dB
+			},
+			validator: func(rows []model.Transaction, err error) {
+				if err == nil || !strings.Contains(err.Error(), "sql: Scan error on column") {
+					t.Errorf("Want: %v, Got: %v", "sql: Scan error on column", err)
+				}
+			},
+		},
+		{
+			name:   "FAILURE:: GetTransactions:: query error",
+			filter: model.TransactionFilter{AccountNumber: 1},
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				dB := sqlDs{
+					sqlSvc:           db,
+					table:            "newTemp",
+					transactionTable: "newTempTransactions",
+					journalTable:     "newTempJournal",
+					historyTable:     "newTempHistory",
+				}
+				mock.ExpectQuery("SELECT").WillReturnError(errors.New("query error"))
+				return dB
+			},
+			validator: func(rows []model.Transaction, err error) {
+				if err == nil || err.Error() != "query error" {
+					t.Errorf("Want: %v, Got: %v", "query error", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.setupFunc()
+			// STEP 2: call the test function
+			rows, err := db.GetTransactions(tt.filter)
+
+			// STEP 3: validation of output
+			if tt.validator != nil {
+				tt.validator(rows, err)
+			}
+
+			// STEP 4: clean up/remove up all instances for the specific test case
+			if tt.cleanupFunc != nil {
+				tt.cleanupFunc()
+			}
+		})
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	before := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("SELECT COALESCE(SUM(CASE WHEN transaction_type = 'debit' THEN -amount ELSE amount END), 0) FROM newTempTransactions WHERE account_number = ? AND created_on < ?;")
+	tests := []struct {
+		name      string
+		setupFunc func() sqlDs
+		validator func(model.Money, error)
+	}{
+		{
+			name: "SUCCESS:: GetBalance",
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				mock.ExpectQuery(query).WithArgs(1, before).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("-12.50"))
+				return sqlDs{sqlSvc: db, table: "newTemp", transactionTable: "newTempTransactions"}
+			},
+			validator: func(balance model.Money, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				if balance != -1250 {
+					t.Errorf("Want: %v, Got: %v", model.Money(-1250), balance)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetBalance:: no entries",
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				mock.ExpectQuery(query).WithArgs(1, before).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(int64(0)))
+				return sqlDs{sqlSvc: db, table: "newTemp", transactionTable: "newTempTransactions"}
+			},
+			validator: func(balance model.Money, err error) {
+				if err != nil || balance != 0 {
+					t.Errorf("Want: %v, Got: %v, %v", 0, balance, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetBalance:: query error",
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				mock.ExpectQuery(query).WillReturnError(errors.New("query error"))
+				return sqlDs{sqlSvc: db, table: "newTemp", transactionTable: "newTempTransactions"}
+			},
+			validator: func(balance model.Money, err error) {
+				if err == nil || err.Error() != "query error" {
+					t.Errorf("Want: %v, Got: %v", "query error", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.setupFunc()
+			balance, err := db.GetBalance(1, before)
+			tt.validator(balance, err)
+		})
+	}
+}
+
+func TestGetTransactionTotals(t *testing.T) {
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("SELECT DATE_FORMAT(created_on, '%Y-%m') AS month, category, " +
+		"COALESCE(SUM(CASE WHEN transaction_type = 'credit' AND reversal_of = 0 THEN amount WHEN transaction_type = 'debit' AND reversal_of <> 0 THEN -amount ELSE 0 END), 0), " +
+		"COALESCE(SUM(CASE WHEN transaction_type = 'debit' AND reversal_of = 0 THEN amount WHEN transaction_type = 'credit' AND reversal_of <> 0 THEN -amount ELSE 0 END), 0) " +
+		"FROM newTempTransactions WHERE account_number = ? AND created_on >= ? AND created_on < ? GROUP BY month, category ORDER BY month, category;")
+	columns := []string{"month", "category", "income", "spends"}
+	tests := []struct {
+		name      string
+		setupFunc func() sqlDs
+		validator func([]model.TransactionTotals, error)
+	}{
+		{
+			name: "SUCCESS:: GetTransactionTotals",
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				mock.ExpectQuery(query).WithArgs(1, from, to).WillReturnRows(sqlmock.NewRows(columns).AddRow("2022-01", "", "1000.00", "0.00").AddRow("2022-02", "groceries", "0.00", "42.10"))
+				return sqlDs{sqlSvc: db, table: "newTemp", transactionTable: "newTempTransactions"}
+			},
+			validator: func(totals []model.TransactionTotals, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				want := []model.TransactionTotals{
+					{Month: "2022-01", Income: 100000},
+					{Month: "2022-02", Category: "groceries", Spends: 4210},
+				}
+				if !reflect.DeepEqual(totals, want) {
+					t.Errorf("Want: %v, Got: %v", want, totals)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetTransactionTotals:: query error",
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				mock.ExpectQuery(query).WillReturnError(errors.New("query error"))
+				return sqlDs{sqlSvc: db, table: "newTemp", transactionTable: "newTempTransactions"}
+			},
+			validator: func(totals []model.TransactionTotals, err error) {
+				if err == nil || err.Error() != "query error" {
+					t.Errorf("Want: %v, Got: %v", "query error", err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetTransactionTotals:: scan error",
+			setupFunc: func() sqlDs {
+				db, mock, err := sqlmock.New()
+				if err != nil {
+					t.Fail()
+				}
+				mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns).AddRow("2022-01", "", "abc", "0.00"))
+				return sqlDs{sqlSvc: db, table: "newTemp", transactionTable: "newTempTransactions"}
+			},
+			validator: func(totals []model.TransactionTotals, err error) {
+				if err == nil || !strings.Contains(err.Error(), "sql: Scan error on column") {
+					t.Errorf("Want: %v, Got: %v", "sql: Scan error on column", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.setupFunc()
+			totals, err := db.GetTransactionTotals(1, from, to)
+			tt.validator(totals, err)
+		})
+	}
+}
+
+func TestReverseTransaction(t *testing.T) {
+	selectOriginal := regexp.QuoteMeta("SELECT account_number, amount, currency, transaction_type, category, merchant, reversal_of, reversed_amount FROM newTempTransactions WHERE transaction_id = ? FOR UPDATE;")
+	originalColumns := []string{"account_number", "amount", "currency", "transaction_type", "category", "merchant", "reversal_of", "reversed_amount"}
+	tests := []struct {
+		name      string
+		id        int64
+		amount    model.Money
+		setupFunc func(sqlmock.Sqlmock)
+		validator func(int64, error)
+	}{
+		{
+			name: "SUCCESS:: ReverseTransaction:: full reversal of debit",
+			id:   5,
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectOriginal).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(originalColumns).AddRow(1, 100.10, "USD", "debit", "groceries", "acme", 0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT internal_account FROM newTempJournal WHERE transaction_id = ? AND internal_account <> '' LIMIT 1;")).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows([]string{"internal_account"}).AddRow("fees"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends - CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(10010), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(10010), "USD", model.Money(0), "", "credit", "refund", "groceries", "acme", int64(5), int64(0)).WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectExec(journal).WithArgs(int64(9), 1, "", "credit", model.Money(10010), "USD", int64(9), 0, "fees", "debit", model.Money(10010), "USD").WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempTransactions SET reversed_amount = reversed_amount + CAST(? AS DECIMAL(18,2)) WHERE transaction_id = ?;")).WithArgs(model.Money(10010), int64(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			validator: func(id int64, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+				if id != 9 {
+					t.Errorf("Want: %v, Got: %v", 9, id)
+				}
+			},
+		},
+		{
+			name:   "SUCCESS:: ReverseTransaction:: partial reversal of credit",
+			id:     5,
+			amount: 2000,
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectOriginal).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(originalColumns).AddRow(1, 100, "USD", "credit", "", "", 0, 50))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT internal_account FROM newTempJournal WHERE transaction_id = ? AND internal_account <> '' LIMIT 1;")).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows([]string{"internal_account"}))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = income - CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(2000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions(account_number, amount, currency, original_amount, original_currency, transaction_type, reference, category, merchant, reversal_of, fee_of) VALUES(?,?,?,?,?,?,?,?,?,?,?)")).WithArgs(1, model.Money(2000), "USD", model.Money(0), "", "debit", "refund", "", "", int64(5), int64(0)).WillReturnResult(sqlmock.NewResult(10, 1))
+				mock.ExpectExec(journal).WithArgs(int64(10), 1, "", "debit", model.Money(2000), "USD", int64(10), 0, "suspense", "credit", model.Money(2000), "USD").WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempTransactions SET reversed_amount = reversed_amount + CAST(? AS DECIMAL(18,2)) WHERE transaction_id = ?;")).WithArgs(model.Money(2000), int64(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			validator: func(id int64, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err.Error())
+				}
+				if id != 10 {
+					t.Errorf("Want: %v, Got: %v", 10, id)
+				}
+			},
+		},
+		{
+			name:   "FAILURE:: ReverseTransaction:: reversal of credit exceeds the available balance",
+			id:     5,
+			amount: 2000,
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectOriginal).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(originalColumns).AddRow(1, 100, "USD", "credit", "", "", 0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "70.00", "5.00", "20.00", "active"))
+				mock.ExpectRollback()
+			},
+			validator: func(id int64, err error) {
+				if !errors.Is(err, ErrInsufficientFunds) {
+					t.Errorf("Want: %v, Got: %v", ErrInsufficientFunds, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: ReverseTransaction:: transaction not found",
+			id:   5,
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectOriginal).WithArgs(int64(5)).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			validator: func(id int64, err error) {
+				if !errors.Is(err, ErrTransactionNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrTransactionNotFound, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: ReverseTransaction:: already fully reversed",
+			id:   5,
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectOriginal).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(originalColumns).AddRow(1, 100, "USD", "debit", "", "", 0, 100))
+				mock.ExpectRollback()
+			},
+			validator: func(id int64, err error) {
+				if !errors.Is(err, ErrAlreadyReversed) {
+					t.Errorf("Want: %v, Got: %v", ErrAlreadyReversed, err)
+				}
+			},
+		},
+		{
+			name:   "FAILURE:: ReverseTransaction:: amount exceeds remaining",
+			id:     5,
+			amount: 6001,
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectOriginal).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(originalColumns).AddRow(1, 100, "USD", "debit", "", "", 0, 40))
+				mock.ExpectRollback()
+			},
+			validator: func(id int64, err error) {
+				if !errors.Is(err, ErrReversalExceedsAmount) {
+					t.Errorf("Want: %v, Got: %v", ErrReversalExceedsAmount, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: ReverseTransaction:: reversal of a reversal",
+			id:   9,
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectOriginal).WithArgs(int64(9)).WillReturnRows(sqlmock.NewRows(originalColumns).AddRow(1, 100, "USD", "credit", "", "", 5, 0))
+				mock.ExpectRollback()
+			},
+			validator: func(id int64, err error) {
+				if !errors.Is(err, ErrReversalOfReversal) {
+					t.Errorf("Want: %v, Got: %v", ErrReversalOfReversal, err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.setupFunc(mock)
+			dB := sqlDs{
+				sqlSvc:           db,
+				table:            "newTemp",
+				transactionTable: "newTempTransactions",
+				journalTable:     "newTempJournal",
+				historyTable:     "newTempHistory",
+			}
+
+			id, err := dB.ReverseTransaction(tt.id, tt.amount, "refund")
+
+			tt.validator(id, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err.Error())
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	createdOn := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := model.IdempotencyRecord{Key: "key", Scope: "PUT /update/transaction", RequestHash: "hash"}
+	tests := []struct {
+		name        string
+		setupFunc   func(sqlmock.Sqlmock)
+		testFunc    func(sqlDs) (interface{}, error)
+		cleanupFunc func()
+		validator   func(interface{}, error)
+	}{
+		{
+			name: "SUCCESS:: InsertIdempotencyKey:: reserved",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO newTempIdempotency(idempotency_key, scope, request_hash, status, response, content_type) VALUES(?,?,?,?,?,?)")).WithArgs("key", "PUT /update/transaction", "hash", 0, "", "").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertIdempotencyKey(record)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != true {
+					t.Errorf("Want: %v, Got: %v, %v", true, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: InsertIdempotencyKey:: already reserved",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT IGNORE INTO newTempIdempotency").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertIdempotencyKey(record)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != false {
+					t.Errorf("Want: %v, Got: %v, %v", false, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertIdempotencyKey:: exec error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT IGNORE INTO newTempIdempotency").WillReturnError(errors.New("exec error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertIdempotencyKey(record)
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || err.Error() != "exec error" {
+					t.Errorf("Want: %v, Got: %v", "exec error", err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetIdempotencyKey",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT idempotency_key, scope, request_hash, status, response, content_type, created_on FROM newTempIdempotency WHERE idempotency_key = ? AND scope = ?;")).WithArgs("key", "PUT /update/transaction").
+					WillReturnRows(sqlmock.NewRows([]string{"idempotency_key", "scope", "request_hash", "status", "response", "content_type", "created_on"}).AddRow("key", "PUT /update/transaction", "hash", 202, "{}", "application/json", createdOn))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetIdempotencyKey("key", "PUT /update/transaction")
+			},
+			validator: func(res interface{}, err error) {
+				temp := &model.IdempotencyRecord{Key: "key", Scope: "PUT /update/transaction", RequestHash: "hash", Status: 202, Response: "{}", ContentType: "application/json", CreatedOn: createdOn}
+				if err != nil || !reflect.DeepEqual(res, temp) {
+					t.Errorf("Want: %v, Got: %v, %v", temp, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetIdempotencyKey:: not found",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT idempotency_key").WillReturnRows(sqlmock.NewRows([]string{"idempotency_key"}))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetIdempotencyKey("key", "PUT /update/transaction")
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res.(*model.IdempotencyRecord) != nil {
+					t.Errorf("Want: %v, Got: %v, %v", nil, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetIdempotencyKey:: query error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT idempotency_key").WillReturnError(errors.New("query error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetIdempotencyKey("key", "PUT /update/transaction")
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || err.Error() != "query error" {
+					t.Errorf("Want: %v, Got: %v", "query error", err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: UpdateIdempotencyKey",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempIdempotency SET status = ?, response = ?, content_type = ? WHERE idempotency_key = ? AND scope = ?;")).WithArgs(202, "{}", "application/json", "key", "PUT /update/transaction").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.UpdateIdempotencyKey(model.IdempotencyRecord{Key: "key", Scope: "PUT /update/transaction", Status: 202, Response: "{}", ContentType: "application/json"})
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: DeleteIdempotencyKey",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM newTempIdempotency WHERE idempotency_key = ? AND scope = ?;")).WithArgs("key", "PUT /update/transaction").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.DeleteIdempotencyKey("key", "PUT /update/transaction")
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: DeleteExpiredIdempotencyKeys",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM newTempIdempotency WHERE created_on < ?;")).WithArgs(createdOn).WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.DeleteExpiredIdempotencyKeys(createdOn)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != int64(3) {
+					t.Errorf("Want: %v, Got: %v, %v", 3, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: DeleteExpiredIdempotencyKeys:: exec error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM newTempIdempotency").WillReturnError(errors.New("exec error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.DeleteExpiredIdempotencyKeys(createdOn)
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || err.Error() != "exec error" {
+					t.Errorf("Want: %v, Got: %v", "exec error", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:           db,
+				table:            "newTemp",
+				transactionTable: "newTempTransactions",
+				journalTable:     "newTempJournal",
+				historyTable:     "newTempHistory",
+				idempotencyTable: "newTempIdempotency",
+			}
+			tt.setupFunc(mock)
+			// STEP 2: call the test function
+			res, err := tt.testFunc(dB)
+
+			// STEP 3: validation of output
+			if tt.validator != nil {
+				tt.validator(res, err)
+			}
+
+			// STEP 4: clean up/remove up all instances for the specific test case
+			if tt.cleanupFunc != nil {
+				tt.cleanupFunc()
+			}
+		})
+	}
+}
+
+func TestStandingOrders(t *testing.T) {
+	now := time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC)
+	order := model.StandingOrder{Id: 5, AccountNumber: 1, Amount: 2500, TransactionType: "debit", Reference: "rent", Schedule: "0 9 1 * *", EndDate: &end, NextRun: now, Status: model.StandingOrderActive}
+	columns := []string{"standing_order_id", "account_number", "amount", "currency", "transaction_type", "reference", "schedule", "end_date", "next_run", "last_run", "last_transaction_id", "last_error", "status", "created_on"}
+	tests := []struct {
+		name        string
+		setupFunc   func(sqlmock.Sqlmock)
+		testFunc    func(sqlDs) (interface{}, error)
+		cleanupFunc func()
+		validator   func(interface{}, error)
+	}{
+		{
+			name: "SUCCESS:: InsertStandingOrder",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempStandingOrders(account_number, amount, currency, transaction_type, reference, schedule, end_date, next_run, status) VALUES(?,?,?,?,?,?,?,?,?)")).
+					WithArgs(1, model.Money(2500), "", "debit", "rent", "0 9 1 * *", &end, now, "active").WillReturnResult(sqlmock.NewResult(5, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertStandingOrder(order)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != int64(5) {
+					t.Errorf("Want: %v, Got: %v, %v", 5, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetStandingOrders",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT standing_order_id, account_number, amount, currency, transaction_type, reference, schedule, end_date, next_run, last_run, last_transaction_id, last_error, status, created_on FROM newTempStandingOrders WHERE account_number = ? ORDER BY standing_order_id DESC;")).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(5, 1, "25.00", "", "debit", "rent", "0 9 1 * *", end, now, nil, 0, "", "active", now))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetStandingOrders(1)
+			},
+			validator: func(res interface{}, err error) {
+				want := order
+				want.CreatedOn = now
+				if err != nil || !reflect.DeepEqual(res, []model.StandingOrder{want}) {
+					t.Errorf("Want: %v, Got: %v, %v", []model.StandingOrder{want}, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetDueStandingOrders",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT standing_order_id, account_number, amount, currency, transaction_type, reference, schedule, end_date, next_run, last_run, last_transaction_id, last_error, status, created_on FROM newTempStandingOrders WHERE status = ? AND next_run <= ? ORDER BY next_run LIMIT ?;")).WithArgs("active", now, 10).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(5, 1, "25.00", "EUR", "credit", "", "@daily", nil, now, now, 7, "", "active", now))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetDueStandingOrders(now, 10)
+			},
+			validator: func(res interface{}, err error) {
+				want := []model.StandingOrder{{Id: 5, AccountNumber: 1, Amount: 2500, Currency: "EUR", TransactionType: "credit", Schedule: "@daily", NextRun: now, LastRun: &now, LastTransactionId: 7, Status: "active", CreatedOn: now}}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetDueStandingOrders:: query error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT").WillReturnError(errors.New("query error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetDueStandingOrders(now, 10)
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || err.Error() != "query error" {
+					t.Errorf("Want: %v, Got: %v", "query error", err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: AdvanceStandingOrder",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempStandingOrders SET next_run = ?, status = ? WHERE standing_order_id = ? AND status = ? AND next_run = ?;")).
+					WithArgs(now.AddDate(0, 1, 0), "active", int64(5), "active", now).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.AdvanceStandingOrder(order, now.AddDate(0, 1, 0), model.StandingOrderActive)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != true {
+					t.Errorf("Want: %v, Got: %v, %v", true, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: AdvanceStandingOrder:: already advanced",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE newTempStandingOrders").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.AdvanceStandingOrder(order, now.AddDate(0, 1, 0), model.StandingOrderActive)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != false {
+					t.Errorf("Want: %v, Got: %v, %v", false, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: RecordStandingOrderRun",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempStandingOrders SET last_run = ?, last_transaction_id = ?, last_error = ? WHERE standing_order_id = ?;")).
+					WithArgs(now, int64(0), "insufficient funds", int64(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.RecordStandingOrderRun(5, now, 0, "insufficient funds")
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: CancelStandingOrder",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempStandingOrders SET status = ? WHERE standing_order_id = ? AND account_number = ? AND status = ?;")).
+					WithArgs("cancelled", int64(5), 1, "active").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.CancelStandingOrder(5, 1)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: CancelStandingOrder:: not active or on another account",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE newTempStandingOrders").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.CancelStandingOrder(5, 1)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrStandingOrderNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrStandingOrderNotFound, err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:             db,
+				table:              "newTemp",
+				transactionTable:   "newTempTransactions",
+				standingOrderTable: "newTempStandingOrders",
+			}
+			tt.setupFunc(mock)
+			// STEP 2: call the test function
+			res, err := tt.testFunc(dB)
+
+			// STEP 3: validation of output
+			if tt.validator != nil {
+				tt.validator(res, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+
+			// STEP 4: clean up/remove up all instances for the specific test case
+			if tt.cleanupFunc != nil {
+				tt.cleanupFunc()
+			}
+		})
+	}
+}
+
+func TestBudgets(t *testing.T) {
+	now := time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		setupFunc func(sqlmock.Sqlmock)
+		testFunc  func(sqlDs) (interface{}, error)
+		validator func(interface{}, error)
+	}{
+		{
+			name: "SUCCESS:: InsertBudget",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO newTempBudgets(account_number, category, amount) VALUES(?,?,?)")).
+					WithArgs(1, "groceries", model.Money(40000)).WillReturnResult(sqlmock.NewResult(3, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertBudget(model.Budget{AccountNumber: 1, Category: "groceries", Limit: 40000})
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != int64(3) {
+					t.Errorf("Want: %v, Got: %v, %v", 3, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertBudget:: category already budgeted",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT IGNORE INTO newTempBudgets").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertBudget(model.Budget{AccountNumber: 1, Category: "groceries", Limit: 40000})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrBudgetExists) {
+					t.Errorf("Want: %v, Got: %v", ErrBudgetExists, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertBudget:: exec error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT IGNORE INTO newTempBudgets").WillReturnError(errors.New("exec error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertBudget(model.Budget{AccountNumber: 1, Limit: 40000})
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || err.Error() != "exec error" {
+					t.Errorf("Want: %v, Got: %v", "exec error", err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetBudgets",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT budget_id, account_number, category, amount, created_on FROM newTempBudgets WHERE account_number = ? ORDER BY category;")).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"budget_id", "account_number", "category", "amount", "created_on"}).AddRow(2, 1, "", "1000.00", now).AddRow(3, 1, "groceries", "400.00", now))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetBudgets(1)
+			},
+			validator: func(res interface{}, err error) {
+				want := []model.Budget{{Id: 2, AccountNumber: 1, Limit: 100000, CreatedOn: now}, {Id: 3, AccountNumber: 1, Category: "groceries", Limit: 40000, CreatedOn: now}}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetBudgets:: scan error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"budget_id", "account_number", "category", "amount", "created_on"}).AddRow(2, 1, "", "abc", now))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetBudgets(1)
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || !strings.Contains(err.Error(), "sql: Scan error on column") {
+					t.Errorf("Want: %v, Got: %v", "sql: Scan error on column", err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: UpdateBudget",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempBudgets SET amount = ? WHERE budget_id = ? AND account_number = ?;")).
+					WithArgs(model.Money(50000), int64(3), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.UpdateBudget(model.Budget{Id: 3, AccountNumber: 1, Limit: 50000})
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: DeleteBudget",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM newTempBudgets WHERE budget_id = ? AND account_number = ?;")).
+					WithArgs(int64(3), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.DeleteBudget(3, 1)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: DeleteBudget:: not found",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM newTempBudgets").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.DeleteBudget(3, 1)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrBudgetNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrBudgetNotFound, err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:      db,
+				table:       "newTemp",
+				budgetTable: "newTempBudgets",
+			}
+			tt.setupFunc(mock)
+			res, err := tt.testFunc(dB)
+			tt.validator(res, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+		})
+	}
+}
+
+func TestHolds(t *testing.T) {
+	now := time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC)
+	expires := now.Add(24 * time.Hour)
+	holdLockColumns := []string{"hold_id", "account_number", "amount", "currency", "reference", "category", "merchant", "status", "expires_on"}
+	lockHold := regexp.QuoteMeta("SELECT hold_id, account_number, amount, currency, reference, category, merchant, status, expires_on FROM newTempHolds WHERE hold_id = ? FOR UPDATE;")
+	lockAccount := regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")
+	tests := []struct {
+		name      string
+		setupFunc func(sqlmock.Sqlmock)
+		testFunc  func(sqlDs) (interface{}, error)
+		validator func(interface{}, error)
+	}{
+		{
+			name: "SUCCESS:: InsertHold",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockAccount).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "0.00", "0.00", "40.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET held = held + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(6000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempHolds(account_number, amount, currency, reference, category, merchant, status, expires_on) VALUES(?,?,?,?,?,?,?,?)")).
+					WithArgs(1, model.Money(6000), "USD", "card auth", "travel", "hotel", "pending", expires).WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertHold(model.SpendLimits{}, now, model.Hold{AccountNumber: 1, Amount: 6000, Reference: "card auth", Category: "travel", Merchant: "hotel", ExpiresOn: expires})
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != int64(5) {
+					t.Errorf("Want: %v, Got: %v, %v", 5, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertHold:: existing holds leave too little",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockAccount).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "0.00", "0.00", "40.00", "active"))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertHold(model.SpendLimits{}, now, model.Hold{AccountNumber: 1, Amount: 6001, ExpiresOn: expires})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrInsufficientFunds) {
+					t.Errorf("Want: %v, Got: %v", ErrInsufficientFunds, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertHold:: currency mismatch",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockAccount).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertHold(model.SpendLimits{}, now, model.Hold{AccountNumber: 1, Amount: 100, Currency: "EUR", ExpiresOn: expires})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrCurrencyMismatch) {
+					t.Errorf("Want: %v, Got: %v", ErrCurrencyMismatch, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetHolds",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT hold_id, account_number, amount, currency, reference, category, merchant, status, expires_on, captured_amount, transaction_id, created_on FROM newTempHolds WHERE account_number = ? AND status = ? ORDER BY hold_id DESC;")).WithArgs(1, "pending").
+					WillReturnRows(sqlmock.NewRows([]string{"hold_id", "account_number", "amount", "currency", "reference", "category", "merchant", "status", "expires_on", "captured_amount", "transaction_id", "created_on"}).AddRow(5, 1, "60.00", "USD", "card auth", "", "", "pending", expires, "0.00", 0, now))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetHolds(1, model.HoldPending)
+			},
+			validator: func(res interface{}, err error) {
+				want := []model.Hold{{Id: 5, AccountNumber: 1, Amount: 6000, Currency: "USD", Reference: "card auth", Status: "pending", ExpiresOn: expires, CreatedOn: now}}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetHold",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT hold_id, account_number, amount, currency, reference, category, merchant, status, expires_on, captured_amount, transaction_id, created_on FROM newTempHolds WHERE hold_id = ?;")).WithArgs(int64(5)).
+					WillReturnRows(sqlmock.NewRows([]string{"hold_id", "account_number", "amount", "currency", "reference", "category", "merchant", "status", "expires_on", "captured_amount", "transaction_id", "created_on"}).AddRow(5, 1, "60.00", "USD", "card auth", "", "", "pending", expires, "0.00", 0, now))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetHold(5)
+			},
+			validator: func(res interface{}, err error) {
+				want := model.Hold{Id: 5, AccountNumber: 1, Amount: 6000, Currency: "USD", Reference: "card auth", Status: "pending", ExpiresOn: expires, CreatedOn: now}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetHold:: not found",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM newTempHolds WHERE hold_id = ?;")).WithArgs(int64(5)).
+					WillReturnRows(sqlmock.NewRows([]string{"hold_id", "account_number", "amount", "currency", "reference", "category", "merchant", "status", "expires_on", "captured_amount", "transaction_id", "created_on"}))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetHold(5)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrHoldNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrHoldNotFound, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetExpiredHolds",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM newTempHolds WHERE status = ? AND expires_on <= ? ORDER BY expires_on LIMIT ?;")).WithArgs("pending", now, 10).
+					WillReturnRows(sqlmock.NewRows([]string{"hold_id", "account_number", "amount", "currency", "reference", "category", "merchant", "status", "expires_on", "captured_amount", "transaction_id", "created_on"}))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetExpiredHolds(now, 10)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || len(res.([]model.Hold)) != 0 {
+					t.Errorf("Want: %v, Got: %v, %v", 0, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: CaptureHold:: partial capture gives the rest back",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockHold).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(holdLockColumns).AddRow(5, 1, "60.00", "USD", "card auth", "travel", "hotel", "pending", expires))
+				mock.ExpectQuery(lockAccount).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "60.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET held = held - CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(6000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(4500), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions")).
+					WithArgs(1, model.Money(4500), "USD", model.Money(0), "", "debit", "card auth", "travel", "hotel", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempHolds SET status = ?, captured_amount = ?, transaction_id = ? WHERE hold_id = ?;")).WithArgs("captured", model.Money(4500), int64(9), int64(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.CaptureHold(model.SpendLimits{}, now, 5, 4500, "", now)
+			},
+			validator: func(res interface{}, err error) {
+				want := model.Transaction{Id: 9, AccountNumber: 1, Amount: 4500, Currency: "USD", TransactionType: "debit", Reference: "card auth", Category: "travel", Merchant: "hotel"}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: CaptureHold:: more than held",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockHold).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(holdLockColumns).AddRow(5, 1, "60.00", "USD", "", "", "", "pending", expires))
+				mock.ExpectQuery(lockAccount).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "60.00", "active"))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.CaptureHold(model.SpendLimits{}, now, 5, 6001, "", now)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrCaptureExceedsHold) {
+					t.Errorf("Want: %v, Got: %v", ErrCaptureExceedsHold, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: CaptureHold:: expired",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockHold).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(holdLockColumns).AddRow(5, 1, "60.00", "USD", "", "", "", "pending", now))
+				mock.ExpectQuery(lockAccount).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "60.00", "active"))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.CaptureHold(model.SpendLimits{}, now, 5, 0, "", now)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrHoldExpired) {
+					t.Errorf("Want: %v, Got: %v", ErrHoldExpired, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: CaptureHold:: not found",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockHold).WithArgs(int64(5)).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.CaptureHold(model.SpendLimits{}, now, 5, 0, "", now)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrHoldNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrHoldNotFound, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: ReleaseHold",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockHold).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(holdLockColumns).AddRow(5, 1, "60.00", "USD", "", "", "", "pending", now))
+				mock.ExpectQuery(lockAccount).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "60.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET held = held - CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(6000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempHolds SET status = ? WHERE hold_id = ?;")).WithArgs("expired", int64(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.ReleaseHold(5, model.HoldExpired)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: ReleaseHold:: already captured",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockHold).WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows(holdLockColumns).AddRow(5, 1, "60.00", "USD", "", "", "", "captured", expires))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.ReleaseHold(5, model.HoldReleased)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrHoldNotPending) {
+					t.Errorf("Want: %v, Got: %v", ErrHoldNotPending, err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:           db,
+				table:            "newTemp",
+				transactionTable: "newTempTransactions",
+				journalTable:     "newTempJournal",
+				historyTable:     "newTempHistory",
+				holdTable:        "newTempHolds",
+			}
+			tt.setupFunc(mock)
+			res, err := tt.testFunc(dB)
+			tt.validator(res, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+		})
+	}
+}
+
+func TestInterestAccruals(t *testing.T) {
+	now := time.Date(2022, 2, 1, 1, 0, 0, 0, time.UTC)
+	accrual := model.InterestAccrual{AccountNumber: 1, Period: "2022-01", Currency: "USD", Rate: "2.5", DayCount: "act/365", Amount: 212}
+	insert := regexp.QuoteMeta("INSERT IGNORE INTO newTempInterest(account_number, period, currency, rate, day_count, amount) VALUES(?,?,?,?,?,?)")
+	tests := []struct {
+		name      string
+		setupFunc func(sqlmock.Sqlmock)
+		testFunc  func(sqlDs) (interface{}, error)
+		validator func(interface{}, error)
+	}{
+		{
+			name: "SUCCESS:: PostInterest",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(insert).WithArgs(1, "2022-01", "USD", "2.5", "act/365", model.Money(212)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "1000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = income + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(212), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions")).
+					WithArgs(1, model.Money(212), "USD", model.Money(0), "", "credit", "interest 2022-01", "interest", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectExec(journal).WithArgs(int64(9), 1, "", "credit", model.Money(212), "USD", int64(9), 0, "interest_expense", "debit", model.Money(212), "USD").WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTempInterest SET transaction_id = ? WHERE account_number = ? AND period = ?;")).WithArgs(int64(9), 1, "2022-01").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.PostInterest(accrual)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != int64(9) {
+					t.Errorf("Want: %v, Got: %v, %v", 9, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: PostInterest:: nothing earned is only recorded",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(insert).WithArgs(1, "2022-01", "USD", "2.5", "act/365", model.Money(0)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				zero := accrual
+				zero.Amount = 0
+				return d.PostInterest(zero)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != int64(0) {
+					t.Errorf("Want: %v, Got: %v, %v", 0, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: PostInterest:: already posted",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.PostInterest(accrual)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrInterestPosted) {
+					t.Errorf("Want: %v, Got: %v", ErrInterestPosted, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: PostInterest:: account not found",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT currency").WithArgs(1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.PostInterest(accrual)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrAccountNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrAccountNotFound, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetInterestAccruals",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT account_number, period, currency, rate, day_count, amount, transaction_id, created_on FROM newTempInterest WHERE period = ? ORDER BY account_number;")).WithArgs("2022-01").
+					WillReturnRows(sqlmock.NewRows([]string{"account_number", "period", "currency", "rate", "day_count", "amount", "transaction_id", "created_on"}).AddRow(1, "2022-01", "USD", "2.5", "act/365", "2.12", 9, now))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetInterestAccruals("2022-01")
+			},
+			validator: func(res interface{}, err error) {
+				want := []model.InterestAccrual{{AccountNumber: 1, Period: "2022-01", Currency: "USD", Rate: "2.5", DayCount: "act/365", Amount: 212, TransactionId: 9, CreatedOn: now}}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetInterestAccruals:: query error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT account_number").WillReturnError(errors.New("query error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetInterestAccruals("2022-01")
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || err.Error() != "query error" {
+					t.Errorf("Want: %v, Got: %v", "query error", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:           db,
+				table:            "newTemp",
+				transactionTable: "newTempTransactions",
+				journalTable:     "newTempJournal",
+				historyTable:     "newTempHistory",
+				interestTable:    "newTempInterest",
+			}
+			tt.setupFunc(mock)
+			res, err := tt.testFunc(dB)
+			tt.validator(res, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+		})
+	}
+}
+
+func TestSpendLimits(t *testing.T) {
+	since := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	updatedOn := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	debit := model.Transaction{AccountNumber: 1, Amount: 10000, TransactionType: "debit"}
+	limits := model.SpendLimits{MaxDebit: 50000, MaxDailyDebit: 100000, MaxDailyCount: 3}
+	daily := regexp.QuoteMeta("SELECT COALESCE(SUM(amount), 0), COUNT(*) FROM newTempTransactions WHERE account_number = ? AND transaction_type = 'debit' AND reversal_of = 0 AND fee_of = 0 AND created_on >= ?;")
+	lock := regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")
+	maxDebit, count := model.Money(20000), 5
+	tests := []struct {
+		name      string
+		setupFunc func(sqlmock.Sqlmock)
+		testFunc  func(sqlDs) (interface{}, error)
+		validator func(interface{}, error)
+	}{
+		{
+			name: "SUCCESS:: InsertDebit:: within the limits",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "5000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(daily).WithArgs(1, since).WillReturnRows(sqlmock.NewRows([]string{"total", "count"}).AddRow("900.00", 2))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(10000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions")).
+					WithArgs(1, model.Money(10000), "USD", model.Money(0), "", "debit", "", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertDebit(limits, since, debit)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				if !reflect.DeepEqual(res, []int64{7}) {
+					t.Errorf("Want: %v, Got: %v", []int64{7}, res)
+				}
+			},
+		},
+		{
+			name:      "FAILURE:: InsertDebit:: single debit limit",
+			setupFunc: func(mock sqlmock.Sqlmock) {},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertDebit(model.SpendLimits{MaxDebit: 5000}, since, debit)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrDebitLimit) {
+					t.Errorf("Want: %v, Got: %v", ErrDebitLimit, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertDebit:: daily debit limit",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "5000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(daily).WithArgs(1, since).WillReturnRows(sqlmock.NewRows([]string{"total", "count"}).AddRow("950.00", 2))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertDebit(limits, since, debit)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrDailyDebitLimit) {
+					t.Errorf("Want: %v, Got: %v", ErrDailyDebitLimit, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertDebit:: daily debit count",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "5000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(daily).WithArgs(1, since).WillReturnRows(sqlmock.NewRows([]string{"total", "count"}).AddRow("100.00", 3))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertDebit(limits, since, debit)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrDailyDebitCount) {
+					t.Errorf("Want: %v, Got: %v", ErrDailyDebitCount, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: InsertTransfer:: within the limits",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "5000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(lock).WithArgs(2).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(daily).WithArgs(1, since).WillReturnRows(sqlmock.NewRows([]string{"total", "count"}).AddRow("900.00", 2))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET spends = spends + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(10000), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions")).
+					WithArgs(1, model.Money(10000), "USD", model.Money(0), "", "debit", "", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = income + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(10000), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions")).
+					WithArgs(2, model.Money(10000), "USD", model.Money(0), "", "credit", "", "", "", int64(0), int64(0)).WillReturnResult(sqlmock.NewResult(8, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertTransfer(limits, since, debit, model.Transaction{AccountNumber: 2, Amount: 10000, TransactionType: "credit"})
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || !reflect.DeepEqual(res, []int64{7, 8}) {
+					t.Errorf("Want: %v, Got: %v, %v", []int64{7, 8}, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransfer:: single debit limit",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "5000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(lock).WithArgs(2).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertTransfer(model.SpendLimits{MaxDebit: 5000}, since, debit, model.Transaction{AccountNumber: 2, Amount: 10000, TransactionType: "credit"})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrDebitLimit) {
+					t.Errorf("Want: %v, Got: %v", ErrDebitLimit, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransfer:: daily debit limit",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "5000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(lock).WithArgs(2).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(daily).WithArgs(1, since).WillReturnRows(sqlmock.NewRows([]string{"total", "count"}).AddRow("950.00", 2))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertTransfer(limits, since, debit, model.Transaction{AccountNumber: 2, Amount: 10000, TransactionType: "credit"})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrDailyDebitLimit) {
+					t.Errorf("Want: %v, Got: %v", ErrDailyDebitLimit, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertHold:: single debit limit",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "5000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertHold(model.SpendLimits{MaxDebit: 5000}, since, model.Hold{AccountNumber: 1, Amount: 10000})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrDebitLimit) {
+					t.Errorf("Want: %v, Got: %v", ErrDebitLimit, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertHold:: daily debit count",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "5000.00", "0.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(daily).WithArgs(1, since).WillReturnRows(sqlmock.NewRows([]string{"total", "count"}).AddRow("100.00", 3))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertHold(limits, since, model.Hold{AccountNumber: 1, Amount: 10000})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrDailyDebitCount) {
+					t.Errorf("Want: %v, Got: %v", ErrDailyDebitCount, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: CaptureHold:: daily debit limit",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("FROM newTempHolds WHERE hold_id = ? FOR UPDATE;")).WithArgs(int64(5)).
+					WillReturnRows(sqlmock.NewRows([]string{"hold_id", "account_number", "amount", "currency", "reference", "category", "merchant", "status", "expires_on"}).AddRow(5, 1, "100.00", "USD", "", "", "", "pending", updatedOn))
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "5000.00", "0.00", "0.00", "100.00", "active"))
+				mock.ExpectQuery(daily).WithArgs(1, since).WillReturnRows(sqlmock.NewRows([]string{"total", "count"}).AddRow("950.00", 2))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.CaptureHold(limits, since, 5, 0, "", since)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrDailyDebitLimit) {
+					t.Errorf("Want: %v, Got: %v", ErrDailyDebitLimit, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetSpendLimits:: override found",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT max_debit, max_daily_debit, max_daily_count, updated_on FROM newTempLimits WHERE account_number = ?;")).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"max_debit", "max_daily_debit", "max_daily_count", "updated_on"}).AddRow("200.00", nil, 5, updatedOn))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetSpendLimits(1)
+			},
+			validator: func(res interface{}, err error) {
+				want := &model.SpendLimitOverride{AccountNumber: 1, MaxDebit: &maxDebit, MaxDailyCount: &count, UpdatedOn: updatedOn}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetSpendLimits:: no override",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT max_debit, max_daily_debit, max_daily_count, updated_on FROM newTempLimits WHERE account_number = ?;")).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"max_debit", "max_daily_debit", "max_daily_count", "updated_on"}))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetSpendLimits(1)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res.(*model.SpendLimitOverride) != nil {
+					t.Errorf("Want: %v, Got: %v, %v", nil, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: UpsertSpendLimits",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempLimits(account_number, max_debit, max_daily_debit, max_daily_count) VALUES(?,?,?,?) ON DUPLICATE KEY UPDATE")).
+					WithArgs(1, "200.00", nil, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.UpsertSpendLimits(model.SpendLimitOverride{AccountNumber: 1, MaxDebit: &maxDebit, MaxDailyCount: &count})
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:           db,
+				table:            "newTemp",
+				transactionTable: "newTempTransactions",
+				journalTable:     "newTempJournal",
+				historyTable:     "newTempHistory",
+				spendLimitTable:  "newTempLimits",
+				holdTable:        "newTempHolds",
+			}
+			tt.setupFunc(mock)
+			res, err := tt.testFunc(dB)
+			tt.validator(res, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+		})
+	}
+}
+
+func TestReconciliation(t *testing.T) {
+	mismatches := regexp.QuoteMeta("SELECT a.account_number, a.income, a.spends, COALESCE(t.income, 0), COALESCE(t.spends, 0) FROM newTemp a LEFT JOIN (SELECT account_number, COALESCE(SUM(")
+	columns := []string{"account_number", "income", "spends", "ledger_income", "ledger_spends"}
+	lock := regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")
+	totals := regexp.QuoteMeta("SELECT COUNT(*), COALESCE(SUM(CASE WHEN transaction_type = 'credit' AND reversal_of = 0 THEN amount WHEN transaction_type = 'debit' AND reversal_of <> 0 THEN -amount ELSE 0 END), 0) AS income, ")
+	tests := []struct {
+		name      string
+		setupFunc func(sqlmock.Sqlmock)
+		testFunc  func(sqlDs) (interface{}, error)
+		validator func(interface{}, error)
+	}{
+		{
+			name: "SUCCESS:: GetLedgerMismatches",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(mismatches).WithArgs(0, 0).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "100.00", "5.00", "100.00", "7.00").AddRow(2, "10.00", "0.00", "0.00", "0.00"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetLedgerMismatches(0)
+			},
+			validator: func(res interface{}, err error) {
+				want := []model.ReconciliationLine{
+					{AccountNumber: 1, Status: model.ReconcileMismatch, Income: 10000, Spends: 500, LedgerIncome: 10000, LedgerSpends: 700},
+					{AccountNumber: 2, Status: model.ReconcileMismatch, Income: 1000},
+				}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetLedgerMismatches",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(mismatches).WithArgs(1, 1).WillReturnError(errors.New("db error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetLedgerMismatches(1)
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil {
+					t.Errorf("Want: %v, Got: %v", "db error", err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: RepairTotals",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "5.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(totals).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"entries", "income", "spends"}).AddRow(2, "100.00", "7.00"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = CAST(? AS DECIMAL(18,2)), spends = CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(10000), model.Money(700), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.RepairTotals(1)
+			},
+			validator: func(res interface{}, err error) {
+				want := model.ReconciliationLine{AccountNumber: 1, Status: model.ReconcileRepaired, Income: 10000, Spends: 500, LedgerIncome: 10000, LedgerSpends: 700}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetTrialBalance",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT internal_account, currency, COALESCE(SUM(CASE WHEN direction = 'debit' THEN amount ELSE 0 END), 0), COALESCE(SUM(CASE WHEN direction = 'credit' THEN amount ELSE 0 END), 0) FROM newTempJournal GROUP BY internal_account, currency ORDER BY currency, internal_account;")).
+					WillReturnRows(sqlmock.NewRows([]string{"internal_account", "currency", "debits", "credits"}).AddRow("", "USD", "7.00", "100.00").AddRow("fees", "USD", "0.00", "2.00").AddRow("suspense", "USD", "100.00", "5.00"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetTrialBalance()
+			},
+			validator: func(res interface{}, err error) {
+				want := []model.TrialBalanceLine{
+					{Currency: "USD", Debits: 700, Credits: 10000},
+					{Account: "fees", Currency: "USD", Credits: 200},
+					{Account: "suspense", Currency: "USD", Debits: 10000, Credits: 500},
+				}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetJournalMismatches",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT c.currency, COALESCE(a.net, 0), COALESCE(j.net, 0) + COALESCE(l.net, 0) " +
+					"FROM (SELECT currency FROM newTemp UNION SELECT currency FROM newTempJournal WHERE internal_account = '' UNION SELECT currency FROM newTempTransactions) c " +
+					"LEFT JOIN (SELECT currency, SUM(income - spends) AS net FROM newTemp GROUP BY currency) a ON a.currency = c.currency " +
+					"LEFT JOIN (SELECT currency, SUM(CASE WHEN direction = 'credit' THEN amount ELSE -amount END) AS net FROM newTempJournal WHERE internal_account = '' GROUP BY currency) j ON j.currency = c.currency " +
+					"LEFT JOIN (SELECT t.currency, SUM(CASE WHEN t.transaction_type = 'credit' THEN t.amount ELSE -t.amount END) AS net FROM newTempTransactions t " +
+					"WHERE NOT EXISTS (SELECT 1 FROM newTempJournal g WHERE g.transaction_id = t.transaction_id) GROUP BY t.currency) l ON l.currency = c.currency " +
+					"WHERE COALESCE(a.net, 0) <> COALESCE(j.net, 0) + COALESCE(l.net, 0) ORDER BY c.currency;")).
+					WillReturnRows(sqlmock.NewRows([]string{"currency", "accounts", "journal"}).AddRow("USD", "95.00", "93.00"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetJournalMismatches()
+			},
+			validator: func(res interface{}, err error) {
+				want := []model.JournalLine{{Currency: "USD", Accounts: 9500, Journal: 9300}}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetJournalMismatches:: query error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT c.currency").WillReturnError(errors.New("query error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetJournalMismatches()
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || err.Error() != "query error" {
+					t.Errorf("Want: %v, Got: %v", "query error", err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: RepairTotals:: account opened before the ledger",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "5.00", "0.00", "0.00", "active"))
+				mock.ExpectQuery(totals).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"entries", "income", "spends"}).AddRow(0, "0.00", "0.00"))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.RepairTotals(1)
+			},
+			validator: func(res interface{}, err error) {
+				want := model.ReconciliationLine{AccountNumber: 1, Status: model.ReconcileMismatch, Income: 10000, Spends: 500}
+				if !errors.Is(err, ErrNoLedgerEntries) || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, %v, Got: %v, %v", want, ErrNoLedgerEntries, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: RepairTotals:: account not found",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.RepairTotals(1)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrAccountNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrAccountNotFound, err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:           db,
+				table:            "newTemp",
+				transactionTable: "newTempTransactions",
+				journalTable:     "newTempJournal",
+				historyTable:     "newTempHistory",
+			}
+			tt.setupFunc(mock)
+			res, err := tt.testFunc(dB)
+			tt.validator(res, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+		})
+	}
+}
+
+func TestGetAccountAsOf(t *testing.T) {
+	asOf := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	changedOn := time.Date(2022, 2, 27, 9, 30, 0, 0, time.UTC)
+	q := regexp.QuoteMeta("SELECT income, spends, currency, account_type, overdraft_limit, held, active_services, inactive_services, status, changed_on FROM newTempHistory WHERE account_number = ? AND changed_on <= ? ORDER BY changed_on DESC, history_id DESC LIMIT 1;")
+	columns := []string{"income", "spends", "currency", "account_type", "overdraft_limit", "held", "active_services", "inactive_services", "status", "changed_on"}
+	tests := []struct {
+		name      string
+		setupFunc func(sqlmock.Sqlmock)
+		validator func(model.Account, error)
+	}{
+		{
+			name: "SUCCESS:: GetAccountAsOf",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(q).WithArgs(1, asOf).WillReturnRows(sqlmock.NewRows(columns).AddRow("1000.00", "250.50", "USD", "savings", "100.00", "20.00", []byte(`{"1":{}}`), []byte(`{}`), "frozen", changedOn))
+			},
+			validator: func(account model.Account, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+					return
+				}
+				want := model.Account{
+					AccountNumber:    1,
+					Income:           100000,
+					Spends:           25050,
+					Currency:         "USD",
+					AccountType:      "savings",
+					OverdraftLimit:   10000,
+					Held:             2000,
+					UpdatedOn:        changedOn,
+					ActiveServices:   &model.Svc{"1": {}},
+					InactiveServices: &model.Svc{},
+					Status:           model.AccountFrozen,
+				}
+				if !reflect.DeepEqual(account, want) {
+					t.Errorf("Want: %+v, Got: %+v", want, account)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetAccountAsOf:: no history before the time",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(q).WithArgs(1, asOf).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			validator: func(account model.Account, err error) {
+				if !errors.Is(err, ErrNoHistory) {
+					t.Errorf("Want: %v, Got: %v", ErrNoHistory, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetAccountAsOf:: sql error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(q).WithArgs(1, asOf).WillReturnError(errors.New("sql error"))
+			},
+			validator: func(account model.Account, err error) {
+				if err == nil || err.Error() != "sql error" {
+					t.Errorf("Want: %v, Got: %v", "sql error", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:       db,
+				table:        "newTemp",
+				historyTable: "newTempHistory",
+			}
+			tt.setupFunc(mock)
+			account, err := dB.GetAccountAsOf(1, asOf)
+			tt.validator(account, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+		})
+	}
+}
+
+func TestAccountStatus(t *testing.T) {
+	lock := regexp.QuoteMeta("SELECT currency, income, spends, overdraft_limit, held, status FROM newTemp WHERE account_number = ? FOR UPDATE;")
+	setStatus := regexp.QuoteMeta("UPDATE newTemp SET status = ? WHERE account_number = ?;")
+	tests := []struct {
+		name      string
+		setupFunc func(sqlmock.Sqlmock)
+		testFunc  func(sqlDs) (interface{}, error)
+		validator func(interface{}, error)
+	}{
+		{
+			name: "SUCCESS:: SetAccountStatus:: freeze an active account",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "0.00", "0.00", "0.00", model.AccountActive))
+				mock.ExpectExec(setStatus).WithArgs(model.AccountFrozen, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.SetAccountStatus(1, model.AccountFrozen)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				if res != model.AccountActive {
+					t.Errorf("Want: %v, Got: %v", model.AccountActive, res)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: SetAccountStatus:: activate a pending account and record it in the history",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "0.00", model.AccountPending))
+				mock.ExpectExec(setStatus).WithArgs(model.AccountActive, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				// the history is copied by the account number, the row is active by then
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.SetAccountStatus(1, model.AccountActive)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || res != model.AccountPending {
+					t.Errorf("Want: %v, Got: %v, %v", model.AccountPending, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: SetAccountStatus:: closed is final",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "0.00", model.AccountClosed))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.SetAccountStatus(1, model.AccountActive)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrInvalidTransition) {
+					t.Errorf("Want: %v, Got: %v", ErrInvalidTransition, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: SetAccountStatus:: close a settled account",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "100.00", "0.00", "0.00", model.AccountActive))
+				mock.ExpectExec(setStatus).WithArgs(model.AccountClosed, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.SetAccountStatus(1, model.AccountClosed)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				if res != model.AccountActive {
+					t.Errorf("Want: %v, Got: %v", model.AccountActive, res)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: SetAccountStatus:: close with balance left",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "40.00", "0.00", "0.00", model.AccountActive))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.SetAccountStatus(1, model.AccountClosed)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrBalanceNotSettled) {
+					t.Errorf("Want: %v, Got: %v", ErrBalanceNotSettled, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: SetAccountStatus:: close with pending holds",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "100.00", "0.00", "25.00", model.AccountFrozen))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.SetAccountStatus(1, model.AccountClosed)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrHoldsNotSettled) {
+					t.Errorf("Want: %v, Got: %v", ErrHoldsNotSettled, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: SetAccountStatus:: account not found",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.SetAccountStatus(1, model.AccountActive)
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrAccountNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrAccountNotFound, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: InsertTransaction:: frozen account takes credits",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "0.00", "0.00", "0.00", model.AccountFrozen))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = income + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(500), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions")).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 500, TransactionType: "credit"})
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				if res != int64(3) {
+					t.Errorf("Want: %v, Got: %v", 3, res)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransaction:: frozen account rejects debits",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "0.00", "0.00", "0.00", model.AccountFrozen))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 500, TransactionType: "debit"})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrAccountFrozen) {
+					t.Errorf("Want: %v, Got: %v", ErrAccountFrozen, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertTransaction:: closed account rejects credits",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "0.00", model.AccountClosed))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 500, TransactionType: "credit"})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrAccountClosed) {
+					t.Errorf("Want: %v, Got: %v", ErrAccountClosed, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: InsertTransaction:: pending account takes credits",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "0.00", "0.00", "0.00", "0.00", model.AccountPending))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE newTemp SET income = income + CAST(? AS DECIMAL(18,2)) WHERE account_number = ?;")).WithArgs(model.Money(500), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO newTempTransactions")).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(journal).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(history).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertTransaction(model.Transaction{AccountNumber: 1, Amount: 500, TransactionType: "credit"})
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+				if res != int64(3) {
+					t.Errorf("Want: %v, Got: %v", 3, res)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertHold:: pending account",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs(1).WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("USD", "100.00", "0.00", "0.00", "0.00", model.AccountPending))
+				mock.ExpectRollback()
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.InsertHold(model.SpendLimits{}, time.Time{}, model.Hold{AccountNumber: 1, Amount: 500})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrAccountNotActive) {
+					t.Errorf("Want: %v, Got: %v", ErrAccountNotActive, err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:           db,
+				table:            "newTemp",
+				transactionTable: "newTempTransactions",
+				journalTable:     "newTempJournal",
+				historyTable:     "newTempHistory",
+				holdTable:        "newTempHolds",
+			}
+			tt.setupFunc(mock)
+			res, err := tt.testFunc(dB)
+			tt.validator(res, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+		})
+	}
+}
+
+func TestSetDefaultAccount(t *testing.T) {
+	errSql := errors.New("sql error")
+	lock := regexp.QuoteMeta("SELECT status FROM newTemp WHERE user_id = ? AND account_number = ? FOR UPDATE;")
+	setDefault := regexp.QuoteMeta("UPDATE newTemp SET is_default = (account_number = ?) WHERE user_id = ?;")
+	tests := []struct {
+		name      string
+		setupFunc func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "SUCCESS:: SetDefaultAccount",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs("123", 2).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.AccountActive))
+				mock.ExpectExec(setDefault).WithArgs(2, "123").WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "FAILURE:: SetDefaultAccount:: account of another user",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs("123", 2).WillReturnRows(sqlmock.NewRows([]string{"status"}))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrAccountNotFound,
+		},
+		{
+			name: "FAILURE:: SetDefaultAccount:: closed account",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs("123", 2).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.AccountClosed))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrAccountClosed,
+		},
+		{
+			name: "FAILURE:: SetDefaultAccount:: sql error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(lock).WithArgs("123", 2).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.AccountActive))
+				mock.ExpectExec(setDefault).WithArgs(2, "123").WillReturnError(errSql)
+				mock.ExpectRollback()
+			},
+			wantErr: errSql,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc: db,
+				table:  "newTemp",
+			}
+			tt.setupFunc(mock)
+			err = dB.SetDefaultAccount("123", 2)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+		})
+	}
+}
+
+func TestMembers(t *testing.T) {
+	now := time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC)
+	accountColumns := []string{"user_id", "account_number", "income", "spends", "currency", "account_type", "overdraft_limit", "held", "created_on", "updated_on", "active_services", "inactive_services", "status", "is_default", "role"}
+	memberAccounts := regexp.QuoteMeta("SELECT a.user_id, a.account_number, a.income, a.spends, a.currency, a.account_type, a.overdraft_limit, a.held, a.created_on, a.updated_on, a.active_services, a.inactive_services, a.status, a.is_default AND m.role = ?, m.role " +
+		"FROM newTemp a JOIN newTempMembers m ON m.account_number = a.account_number WHERE m.user_id = ?")
+	tests := []struct {
+		name      string
+		setupFunc func(sqlmock.Sqlmock)
+		testFunc  func(sqlDs) (interface{}, error)
+		validator func(interface{}, error)
+	}{
+		{
+			name: "SUCCESS:: GetMemberAccounts",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(memberAccounts+regexp.QuoteMeta(" ORDER BY a.account_number;")).WithArgs(model.RoleOwner, "123").
+					WillReturnRows(sqlmock.NewRows(accountColumns).
+						AddRow("123", 1, "10.00", "0.00", "USD", "current", "0.00", "0.00", now, now, []byte("{}"), []byte("{}"), model.AccountActive, true, model.RoleOwner).
+						AddRow("456", 2, "5.00", "0.00", "USD", "savings", "0.00", "0.00", now, now, []byte("{}"), []byte("{}"), model.AccountActive, false, model.RoleViewer))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetMemberAccounts("123", 0)
+			},
+			validator: func(res interface{}, err error) {
+				want := []model.MemberAccount{
+					{Account: model.Account{Id: "123", AccountNumber: 1, Income: 1000, Currency: "USD", AccountType: "current", CreatedOn: now, UpdatedOn: now, ActiveServices: &model.Svc{}, InactiveServices: &model.Svc{}, Status: model.AccountActive, Default: true}, Role: model.RoleOwner},
+					{Account: model.Account{Id: "456", AccountNumber: 2, Income: 500, Currency: "USD", AccountType: "savings", CreatedOn: now, UpdatedOn: now, ActiveServices: &model.Svc{}, InactiveServices: &model.Svc{}, Status: model.AccountActive}, Role: model.RoleViewer},
+				}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetMemberAccounts:: account number given",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(memberAccounts+regexp.QuoteMeta(" AND a.account_number = ? ORDER BY a.account_number;")).WithArgs(model.RoleOwner, "123", 2).
+					WillReturnRows(sqlmock.NewRows(accountColumns))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetMemberAccounts("123", 2)
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil || len(res.([]model.MemberAccount)) != 0 {
+					t.Errorf("Want: %v, Got: %v, %v", nil, res, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: GetMemberAccounts:: query error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT").WillReturnError(errors.New("query error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetMemberAccounts("123", 0)
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || err.Error() != "query error" {
+					t.Errorf("Want: %v, Got: %v", "query error", err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: GetMembers",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT account_number, user_id, role, created_on FROM newTempMembers WHERE account_number = ? ORDER BY role = ? DESC, created_on, user_id;")).WithArgs(1, model.RoleOwner).
+					WillReturnRows(sqlmock.NewRows([]string{"account_number", "user_id", "role", "created_on"}).AddRow(1, "123", model.RoleOwner, now).AddRow(1, "456", model.RoleViewer, now))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return d.GetMembers(1)
+			},
+			validator: func(res interface{}, err error) {
+				want := []model.Member{{AccountNumber: 1, UserId: "123", Role: model.RoleOwner, CreatedOn: now}, {AccountNumber: 1, UserId: "456", Role: model.RoleViewer, CreatedOn: now}}
+				if err != nil || !reflect.DeepEqual(res, want) {
+					t.Errorf("Want: %v, Got: %v, %v", want, res, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: InsertMember",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO newTempMembers(account_number, user_id, role) VALUES(?,?,?);")).
+					WithArgs(1, "456", model.RoleCoOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.InsertMember(model.Member{AccountNumber: 1, UserId: "456", Role: model.RoleCoOwner})
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: InsertMember:: member already",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT IGNORE INTO newTempMembers").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.InsertMember(model.Member{AccountNumber: 1, UserId: "456", Role: model.RoleCoOwner})
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrMemberExists) {
+					t.Errorf("Want: %v, Got: %v", ErrMemberExists, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: DeleteMember",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM newTempMembers WHERE account_number = ? AND user_id = ? AND role != ?;")).
+					WithArgs(1, "456", model.RoleOwner).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.DeleteMember(1, "456")
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: DeleteMember:: not a member or the owner",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM newTempMembers").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.DeleteMember(1, "123")
+			},
+			validator: func(res interface{}, err error) {
+				if !errors.Is(err, ErrMemberNotFound) {
+					t.Errorf("Want: %v, Got: %v", ErrMemberNotFound, err)
+				}
+			},
+		},
+		{
+			name: "SUCCESS:: DeleteUserMembers",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("DELETE m FROM newTempMembers m JOIN newTemp a ON a.account_number = m.account_number WHERE m.user_id = ? AND (m.role != ? OR a.status = ?);")).
+					WithArgs("123", model.RoleOwner, model.AccountClosed).WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.DeleteUserMembers("123")
+			},
+			validator: func(res interface{}, err error) {
+				if err != nil {
+					t.Errorf("Want: %v, Got: %v", nil, err)
+				}
+			},
+		},
+		{
+			name: "FAILURE:: DeleteUserMembers:: exec error",
+			setupFunc: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE m FROM newTempMembers").WillReturnError(errors.New("exec error"))
+			},
+			testFunc: func(d sqlDs) (interface{}, error) {
+				return nil, d.DeleteUserMembers("123")
+			},
+			validator: func(res interface{}, err error) {
+				if err == nil || err.Error() != "exec error" {
+					t.Errorf("Want: %v, Got: %v", "exec error", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fail()
+			}
+			dB := sqlDs{
+				sqlSvc:      db,
+				table:       "newTemp",
+				memberTable: "newTempMembers",
+			}
+			tt.setupFunc(mock)
+			res, err := tt.testFunc(dB)
+			tt.validator(res, err)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/PereRohit/util/constant"
 	"github.com/PereRohit/util/middleware"
@@ -9,8 +10,10 @@ import (
 
 	"github.com/vatsal278/AccountManagmentSvc/internal/config"
 	"github.com/vatsal278/AccountManagmentSvc/internal/handler"
+	"github.com/vatsal278/AccountManagmentSvc/internal/logic"
 	middleware2 "github.com/vatsal278/AccountManagmentSvc/internal/middleware"
 	"github.com/vatsal278/AccountManagmentSvc/internal/repo/datasource"
+	"github.com/vatsal278/AccountManagmentSvc/internal/scheduler"
 )
 
 func Register(svcCfg *config.SvcConfig) *mux.Router {
@@ -37,17 +40,41 @@ func Register(svcCfg *config.SvcConfig) *mux.Router {
 }
 
 func attachAccountManagmentSvcRoutes(m *mux.Router, svcCfg *config.SvcConfig) *mux.Router {
-	dataSource := datasource.NewSql(svcCfg.DbSvc, svcCfg.Cfg.DataBase.TableName)
-	svc := handler.NewAccountManagmentSvc(dataSource, svcCfg.JwtSvc.JwtSvc, svcCfg.MsgBrokerSvc, svcCfg.Cfg.Cookie)
+	dataSource := datasource.NewSql(svcCfg.DbSvc, svcCfg.Cfg.DataBase)
+	svc := handler.NewAccountManagmentSvc(dataSource, svcCfg.JwtSvc.JwtSvc, svcCfg.MsgBrokerSvc, *svcCfg.Cfg)
 	middleware := middleware2.NewAccMgmtMiddleware(svcCfg)
 
 	route1 := m.PathPrefix("").Subrouter()
 	route1.HandleFunc("", svc.CreateAccount).Methods(http.MethodPost)
+	route1.HandleFunc("/close", svc.CloseAccount).Methods(http.MethodPost)
 	route1.Use(middleware.ScreenRequest)
 
 	route2 := m.PathPrefix("").Subrouter()
 	route2.HandleFunc("/update/service", svc.UpdateService).Methods(http.MethodPut)
+	route2.HandleFunc("/update/default", svc.SetDefaultAccount).Methods(http.MethodPut)
+	route2.HandleFunc("/open", svc.OpenAccount).Methods(http.MethodPost)
 	route2.Use(middleware.ExtractUser)
+	route2.Use(middleware.Idempotency)
+
+	route5 := m.PathPrefix("").Subrouter()
+	route5.HandleFunc("/transactions", svc.TransactionHistory).Methods(http.MethodGet)
+	route5.HandleFunc("/statement", svc.Statement).Methods(http.MethodGet)
+	route5.HandleFunc("/analytics", svc.Analytics).Methods(http.MethodGet)
+	route5.HandleFunc("/budgets", svc.Budgets).Methods(http.MethodGet)
+	route5.HandleFunc("/members", svc.Members).Methods(http.MethodGet)
+	route5.HandleFunc("/standing-orders", svc.StandingOrders).Methods(http.MethodGet)
+	route5.Use(middleware.ExtractUser)
+
+	route7 := m.PathPrefix("").Subrouter()
+	route7.HandleFunc("/budgets", svc.CreateBudget).Methods(http.MethodPost)
+	route7.HandleFunc("/budgets/{id}", svc.UpdateBudget).Methods(http.MethodPut)
+	route7.HandleFunc("/budgets/{id}", svc.DeleteBudget).Methods(http.MethodDelete)
+	route7.HandleFunc("/members", svc.InviteMember).Methods(http.MethodPost)
+	route7.HandleFunc("/members/{user_id}", svc.RemoveMember).Methods(http.MethodDelete)
+	route7.HandleFunc("/standing-orders", svc.CreateStandingOrder).Methods(http.MethodPost)
+	route7.HandleFunc("/standing-orders/{id}", svc.CancelStandingOrder).Methods(http.MethodDelete)
+	route7.Use(middleware.ExtractUser)
+	route7.Use(middleware.Idempotency)
 
 	route4 := m.PathPrefix("").Subrouter()
 	route4.HandleFunc("", svc.AccountSummary).Methods(http.MethodGet)
@@ -56,6 +83,39 @@ func attachAccountManagmentSvcRoutes(m *mux.Router, svcCfg *config.SvcConfig) *m
 
 	route3 := m.PathPrefix("").Subrouter()
 	route3.HandleFunc("/update/transaction", svc.UpdateTransaction).Methods(http.MethodPut)
+	route3.HandleFunc("/update/transfer", svc.Transfer).Methods(http.MethodPut)
+	route3.HandleFunc("/update/reversal", svc.ReverseTransaction).Methods(http.MethodPut)
+	route3.HandleFunc("/update/hold", svc.PlaceHold).Methods(http.MethodPut)
+	route3.HandleFunc("/update/hold/capture", svc.CaptureHold).Methods(http.MethodPut)
+	route3.HandleFunc("/update/hold/release", svc.ReleaseHold).Methods(http.MethodPut)
+	route3.Use(middleware.Idempotency)
+
+	route8 := m.PathPrefix("").Subrouter()
+	route8.HandleFunc("/update/overdraft", svc.UpdateOverdraftLimit).Methods(http.MethodPut)
+	route8.HandleFunc("/update/limits", svc.UpdateSpendLimits).Methods(http.MethodPut)
+	route8.HandleFunc("/update/status", svc.UpdateAccountStatus).Methods(http.MethodPut)
+	route8.HandleFunc("/transactions/import", svc.ImportTransactions).Methods(http.MethodPost)
+	route8.Use(middleware.ExtractUser)
+	route8.Use(middleware.Admin)
+	route8.Use(middleware.Idempotency)
 
 	return m
 }
+
+// Jobs registers the background jobs of the service, the caller is in charge of starting and stopping them.
+func Jobs(svcCfg *config.SvcConfig) *scheduler.Scheduler {
+	dataSource := datasource.NewSql(svcCfg.DbSvc, svcCfg.Cfg.DataBase)
+	svc := logic.NewAccountManagmentSvcLogic(dataSource, svcCfg.JwtSvc.JwtSvc, svcCfg.MsgBrokerSvc, *svcCfg.Cfg)
+
+	jobs := scheduler.New()
+	jobs.Every(svcCfg.Cfg.Scheduler.Time, "standing orders", svc.RunStandingOrders)
+	jobs.Every(svcCfg.Cfg.Scheduler.Time, "hold expiry", svc.ExpireHolds)
+	jobs.Every(svcCfg.Cfg.Interest.Time, "interest accrual", svc.AccrueInterest)
+	jobs.Every(svcCfg.Cfg.Idempotency.PurgeTime, "idempotency key purge", func(now time.Time) {
+		svc.PurgeIdempotencyKeys(now.Add(-svcCfg.Cfg.Idempotency.Time))
+	})
+	jobs.Every(svcCfg.Cfg.Reconciliation.Time, "reconciliation", func(time.Time) {
+		svc.Reconcile(0, svcCfg.Cfg.Reconciliation.Repair)
+	})
+	return jobs
+}
@@ -0,0 +1,69 @@
+// Package scheduler runs the background jobs of the service inside the service process.
+package scheduler
+
+import (
+	"fmt"
+	"github.com/PereRohit/util/log"
+	"sync"
+	"time"
+)
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(now time.Time)
+}
+
+// Scheduler runs every registered job on its own interval until it is stopped, a job never overlaps
+// with itself since the next tick only starts once the previous run returned.
+type Scheduler struct {
+	jobs []job
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func New() *Scheduler {
+	return &Scheduler{stop: make(chan struct{})}
+}
+
+// Every registers a job, it has to be called before Start.
+func (s *Scheduler) Every(interval time.Duration, name string, run func(now time.Time)) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+func (s *Scheduler) Start() {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+}
+
+// Stop waits for the jobs currently running to return.
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(j job) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			runJob(j, now)
+		}
+	}
+}
+
+// runJob keeps a panicking job from taking the whole service down, the job is tried again on the next tick.
+func runJob(j job, now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error(fmt.Sprintf("job %s panicked: %v", j.name, r))
+		}
+	}()
+	j.run(now)
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    string
+		wantErr error
+	}{
+		{name: "Success :: every minute", give: "* * * * *"},
+		{name: "Success :: lists, ranges and steps", give: "0,30 9-17/2 1-15 */3 1-5"},
+		{name: "Success :: sunday as 7", give: "0 0 * * 7"},
+		{name: "Success :: descriptor", give: "@monthly"},
+		{name: "Failure :: missing field", give: "0 0 * *", wantErr: ErrInvalidSchedule},
+		{name: "Failure :: out of range", give: "60 * * * *", wantErr: ErrInvalidSchedule},
+		{name: "Failure :: reversed range", give: "0 0 10-1 * *", wantErr: ErrInvalidSchedule},
+		{name: "Failure :: zero step", give: "*/0 * * * *", wantErr: ErrInvalidSchedule},
+		{name: "Failure :: not a number", give: "0 0 * JAN *", wantErr: ErrInvalidSchedule},
+		{name: "Failure :: unknown descriptor", give: "@sometimes", wantErr: ErrInvalidSchedule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchedule(tt.give)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// 2022-01-31 is a Monday
+	after := time.Date(2022, 1, 31, 10, 30, 45, 0, time.UTC)
+	tests := []struct {
+		name string
+		give string
+		want time.Time
+	}{
+		{
+			name: "every minute",
+			give: "* * * * *",
+			want: time.Date(2022, 1, 31, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name: "later the same day",
+			give: "0 12 * * *",
+			want: time.Date(2022, 1, 31, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "first of the next month",
+			give: "@monthly",
+			want: time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "skips months without the day",
+			give: "0 0 31 * *",
+			want: time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of week",
+			give: "0 9 * * 5",
+			want: time.Date(2022, 2, 4, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "either day field when both are restricted",
+			give: "0 0 15 * 3",
+			want: time.Date(2022, 2, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "end of the year",
+			give: "0 0 1 1 *",
+			want: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "never",
+			give: "0 0 30 2 *",
+			want: time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSchedule(tt.give)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Next(after); !got.Equal(tt.want) {
+				t.Errorf("Want: %v, Got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	var runs int32
+	done := make(chan struct{})
+	s := New()
+	s.Every(time.Millisecond, "panics", func(time.Time) {
+		panic("job failed")
+	})
+	s.Every(time.Millisecond, "counts", func(time.Time) {
+		if atomic.AddInt32(&runs, 1) == 3 {
+			close(done)
+		}
+	})
+	s.Start()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("Want: %v, Got: %v", 3, atomic.LoadInt32(&runs))
+	}
+	s.Stop()
+	stopped := atomic.LoadInt32(&runs)
+	time.Sleep(5 * time.Millisecond)
+	if got := atomic.LoadInt32(&runs); got != stopped {
+		t.Errorf("Want: %v, Got: %v", stopped, got)
+	}
+}
@@ -0,0 +1,53 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+	"strconv"
+)
+
+var csvHeader = []string{"date", "description", "transaction_id", "transaction_type", "amount", "currency", "balance"}
+
+// CSV renders the statement as one row per transaction framed by the opening and closing balance rows,
+// debits are written as negative amounts.
+func CSV(s model.Statement) ([]byte, error) {
+	lines, err := rows(s)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := [][]string{
+		csvHeader,
+		{s.From.Format(dateLayout), "Opening balance", "", "", "", s.Currency, s.OpeningBalance.String()},
+	}
+	for _, l := range lines {
+		records = append(records, []string{
+			l.CreatedOn.Format(dateTimeLayout),
+			sanitizeCell(description(l.Transaction)),
+			strconv.FormatInt(l.Id, 10),
+			l.TransactionType,
+			l.SignedAmount().String(),
+			l.Currency,
+			l.Balance.String(),
+		})
+	}
+	records = append(records, []string{lastDay(s), "Closing balance", "", "", "", s.Currency, s.ClosingBalance.String()})
+	if err = w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sanitizeCell stops spreadsheet applications from evaluating references supplied by clients as formulas.
+func sanitizeCell(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
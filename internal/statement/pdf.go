@@ -0,0 +1,124 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+	"strings"
+	"unicode/utf8"
+)
+
+// page layout in points, the statement is printed on A4 with the built-in Courier font so columns line up
+// without embedding any font metrics.
+const (
+	pageWidth     = 595
+	pageHeight    = 842
+	margin        = 50
+	fontSize      = 9
+	leading       = 12
+	linesPerPage  = (pageHeight - 2*margin) / leading
+	maxLineLength = 90
+)
+
+const tableFormat = "%-19s  %-8s  %-6s  %14s  %14s  %s"
+
+// PDF renders the statement as a text only PDF document.
+func PDF(s model.Statement) ([]byte, error) {
+	lines, err := rows(s)
+	if err != nil {
+		return nil, err
+	}
+	text := []string{
+		"Account statement",
+		"",
+		fmt.Sprintf("Account number:  %d", s.AccountNumber),
+		fmt.Sprintf("Currency:        %s", s.Currency),
+		fmt.Sprintf("Period:          %s to %s", s.From.Format(dateLayout), lastDay(s)),
+		fmt.Sprintf("Opening balance: %s", s.OpeningBalance),
+		fmt.Sprintf("Closing balance: %s", s.ClosingBalance),
+		"",
+		fmt.Sprintf(tableFormat, "Date", "Id", "Type", "Amount", "Balance", "Description"),
+	}
+	for _, l := range lines {
+		text = append(text, fmt.Sprintf(tableFormat, l.CreatedOn.Format(dateTimeLayout), fmt.Sprint(l.Id), l.TransactionType, l.SignedAmount(), l.Balance, description(l.Transaction)))
+	}
+	if len(lines) == 0 {
+		text = append(text, "No transactions in this period.")
+	}
+	return renderPDF(text), nil
+}
+
+// renderPDF lays the lines out over as many pages as needed and writes the objects, the cross-reference
+// table and the trailer of a PDF 1.4 file.
+func renderPDF(lines []string) []byte {
+	var pages [][]string
+	for len(lines) > linesPerPage {
+		pages = append(pages, lines[:linesPerPage])
+		lines = lines[linesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	buf.WriteString("%PDF-1.4\n")
+
+	// objects 1 to 3 are shared, every page then takes a page object followed by its content stream
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 5+2*i))
+		content := pageContent(page, fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+func pageContent(lines []string, footer string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, leading, margin, pageHeight-margin)
+	for _, line := range lines {
+		fmt.Fprintf(&b, "(%s) Tj T*\n", escape(line))
+	}
+	b.WriteString("ET\n")
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d %d Td\n(%s) Tj\nET", fontSize, margin, margin/2, escape(footer))
+	return b.String()
+}
+
+// escape turns the line into the body of a PDF string literal, lines too wide for the page are cut short
+// and characters WinAnsiEncoding cannot show are replaced.
+func escape(line string) string {
+	if utf8.RuneCountInString(line) > maxLineLength {
+		line = string([]rune(line)[:maxLineLength-3]) + "..."
+	}
+	var b strings.Builder
+	for _, r := range line {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
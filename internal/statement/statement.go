@@ -0,0 +1,48 @@
+// Package statement renders account statements, everything is written with the standard library so
+// statements can be generated without any external tool or network access.
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+	"strconv"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04:05"
+)
+
+var ErrInvalidPeriod = errors.New("statement period must end after it starts")
+
+// row is a transaction of the statement together with the balance right after it was posted.
+type row struct {
+	model.Transaction
+	Balance model.Money
+}
+
+func rows(s model.Statement) ([]row, error) {
+	if !s.To.After(s.From) {
+		return nil, fmt.Errorf("%w: %s - %s", ErrInvalidPeriod, s.From, s.To)
+	}
+	balance := s.OpeningBalance
+	result := make([]row, 0, len(s.Transactions))
+	for _, t := range s.Transactions {
+		balance += t.SignedAmount()
+		result = append(result, row{Transaction: t, Balance: balance})
+	}
+	return result, nil
+}
+
+// lastDay is the last day covered by the statement, To itself is excluded from the period.
+func lastDay(s model.Statement) string {
+	return s.To.AddDate(0, 0, -1).Format(dateLayout)
+}
+
+func description(t model.Transaction) string {
+	if t.Reference == "" && t.ReversalOf != 0 {
+		return "reversal of " + strconv.FormatInt(t.ReversalOf, 10)
+	}
+	return t.Reference
+}
@@ -0,0 +1,166 @@
+package statement
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/vatsal278/AccountManagmentSvc/internal/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testFrom = time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	testTo   = time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func testStatement(transactions ...model.Transaction) model.Statement {
+	s := model.Statement{AccountNumber: 7, Currency: "USD", From: testFrom, To: testTo, OpeningBalance: 10000, Transactions: transactions}
+	s.ClosingBalance = s.OpeningBalance
+	for _, t := range transactions {
+		s.ClosingBalance += t.SignedAmount()
+	}
+	return s
+}
+
+func TestCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		give    model.Statement
+		want    string
+		wantErr error
+	}{
+		{
+			name: "Success :: running balance",
+			give: testStatement(
+				model.Transaction{Id: 1, Amount: 5000, Currency: "USD", TransactionType: "credit", Reference: "salary, september", CreatedOn: testFrom.Add(time.Hour)},
+				model.Transaction{Id: 2, Amount: 20000, Currency: "USD", TransactionType: "debit", Reference: "=HYPERLINK()", CreatedOn: testFrom.Add(2 * time.Hour)},
+				model.Transaction{Id: 3, Amount: 500, Currency: "USD", TransactionType: "credit", ReversalOf: 2, CreatedOn: testFrom.Add(3 * time.Hour)},
+			),
+			want: "date,description,transaction_id,transaction_type,amount,currency,balance\n" +
+				"2022-09-01,Opening balance,,,,USD,100.00\n" +
+				"2022-09-01 01:00:00,\"salary, september\",1,credit,50.00,USD,150.00\n" +
+				"2022-09-01 02:00:00,'=HYPERLINK(),2,debit,-200.00,USD,-50.00\n" +
+				"2022-09-01 03:00:00,reversal of 2,3,credit,5.00,USD,-45.00\n" +
+				"2022-09-30,Closing balance,,,,USD,-45.00\n",
+		},
+		{
+			name: "Success :: no transactions",
+			give: testStatement(),
+			want: "date,description,transaction_id,transaction_type,amount,currency,balance\n" +
+				"2022-09-01,Opening balance,,,,USD,100.00\n" +
+				"2022-09-30,Closing balance,,,,USD,100.00\n",
+		},
+		{
+			name:    "Failure :: invalid period",
+			give:    model.Statement{From: testTo, To: testFrom},
+			wantErr: ErrInvalidPeriod,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CSV(tt.give)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Want: %v, Got: %v", tt.want, string(got))
+			}
+		})
+	}
+}
+
+func TestPDF(t *testing.T) {
+	many := make([]model.Transaction, 2*linesPerPage)
+	for i := range many {
+		many[i] = model.Transaction{Id: int64(i + 1), Amount: 100, Currency: "USD", TransactionType: "credit", CreatedOn: testFrom}
+	}
+	tests := []struct {
+		name      string
+		give      model.Statement
+		wantErr   error
+		wantPages int
+		wantText  []string
+	}{
+		{
+			name: "Success :: single page",
+			give: testStatement(
+				model.Transaction{Id: 1, Amount: 5000, Currency: "USD", TransactionType: "debit", Reference: "café (lunch)", CreatedOn: testFrom.Add(time.Hour)},
+			),
+			wantPages: 1,
+			wantText: []string{
+				"(Account number:  7) Tj",
+				"(Period:          2022-09-01 to 2022-09-30) Tj",
+				"(Opening balance: 100.00) Tj",
+				"(Closing balance: 50.00) Tj",
+				`caf\351 \(lunch\)) Tj`,
+				"(Page 1 of 1) Tj",
+			},
+		},
+		{
+			name:      "Success :: no transactions",
+			give:      testStatement(),
+			wantPages: 1,
+			wantText:  []string{"(No transactions in this period.) Tj"},
+		},
+		{
+			name:      "Success :: spans pages",
+			give:      testStatement(many...),
+			wantPages: 3,
+			wantText:  []string{"(Page 3 of 3) Tj"},
+		},
+		{
+			name:    "Failure :: invalid period",
+			give:    model.Statement{From: testFrom, To: testFrom},
+			wantErr: ErrInvalidPeriod,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PDF(tt.give)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Want: %v, Got: %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if err = validatePDF(got); err != nil {
+				t.Errorf("Want: %v, Got: %v", nil, err)
+			}
+			if count := bytes.Count(got, []byte("/Type /Page /Parent")); count != tt.wantPages {
+				t.Errorf("Want: %v, Got: %v", tt.wantPages, count)
+			}
+			for _, text := range tt.wantText {
+				if !bytes.Contains(got, []byte(text)) {
+					t.Errorf("Want: %v, Got: %v", text, string(got))
+				}
+			}
+		})
+	}
+}
+
+// validatePDF checks the header, the trailer and that every cross-reference entry points at its object.
+func validatePDF(b []byte) error {
+	if !bytes.HasPrefix(b, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(b, []byte("%%EOF\n")) {
+		return errors.New("missing header or trailer")
+	}
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(b)
+	if match == nil {
+		return errors.New("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(b[xref:], []byte("xref\n")) {
+		return fmt.Errorf("startxref %d does not point at the xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(b[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if !strings.HasPrefix(string(b[offset:]), fmt.Sprintf("%d 0 obj\n", i+1)) {
+			return fmt.Errorf("xref entry %d does not point at its object", i+1)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package mock
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/vatsal278/AccountManagmentSvc/internal/model"
@@ -34,6 +35,121 @@ func (m *MockDataSourceI) EXPECT() *MockDataSourceIMockRecorder {
 	return m.recorder
 }
 
+// AdvanceStandingOrder mocks base method.
+func (m *MockDataSourceI) AdvanceStandingOrder(arg0 model.StandingOrder, arg1 time.Time, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceStandingOrder", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceStandingOrder indicates an expected call of AdvanceStandingOrder.
+func (mr *MockDataSourceIMockRecorder) AdvanceStandingOrder(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceStandingOrder", reflect.TypeOf((*MockDataSourceI)(nil).AdvanceStandingOrder), arg0, arg1, arg2)
+}
+
+// CancelStandingOrder mocks base method.
+func (m *MockDataSourceI) CancelStandingOrder(arg0 int64, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelStandingOrder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelStandingOrder indicates an expected call of CancelStandingOrder.
+func (mr *MockDataSourceIMockRecorder) CancelStandingOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelStandingOrder", reflect.TypeOf((*MockDataSourceI)(nil).CancelStandingOrder), arg0, arg1)
+}
+
+// CaptureHold mocks base method.
+func (m *MockDataSourceI) CaptureHold(arg0 model.SpendLimits, arg1 time.Time, arg2 int64, arg3 model.Money, arg4 string, arg5 time.Time) (model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockDataSourceIMockRecorder) CaptureHold(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockDataSourceI)(nil).CaptureHold), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// DeleteBudget mocks base method.
+func (m *MockDataSourceI) DeleteBudget(arg0 int64, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBudget", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBudget indicates an expected call of DeleteBudget.
+func (mr *MockDataSourceIMockRecorder) DeleteBudget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBudget", reflect.TypeOf((*MockDataSourceI)(nil).DeleteBudget), arg0, arg1)
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockDataSourceI) DeleteExpiredIdempotencyKeys(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockDataSourceIMockRecorder) DeleteExpiredIdempotencyKeys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockDataSourceI)(nil).DeleteExpiredIdempotencyKeys), arg0)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockDataSourceI) DeleteIdempotencyKey(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockDataSourceIMockRecorder) DeleteIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockDataSourceI)(nil).DeleteIdempotencyKey), arg0, arg1)
+}
+
+// DeleteMember mocks base method.
+func (m *MockDataSourceI) DeleteMember(arg0 int, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMember indicates an expected call of DeleteMember.
+func (mr *MockDataSourceIMockRecorder) DeleteMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMember", reflect.TypeOf((*MockDataSourceI)(nil).DeleteMember), arg0, arg1)
+}
+
+// DeleteUserMembers mocks base method.
+func (m *MockDataSourceI) DeleteUserMembers(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserMembers", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserMembers indicates an expected call of DeleteUserMembers.
+func (mr *MockDataSourceIMockRecorder) DeleteUserMembers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserMembers", reflect.TypeOf((*MockDataSourceI)(nil).DeleteUserMembers), arg0)
+}
+
 // Get mocks base method.
 func (m *MockDataSourceI) Get(arg0 map[string]interface{}) ([]model.Account, error) {
 	m.ctrl.T.Helper()
@@ -49,6 +165,276 @@ func (mr *MockDataSourceIMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDataSourceI)(nil).Get), arg0)
 }
 
+// GetAccountAsOf mocks base method.
+func (m *MockDataSourceI) GetAccountAsOf(arg0 int, arg1 time.Time) (model.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountAsOf", arg0, arg1)
+	ret0, _ := ret[0].(model.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountAsOf indicates an expected call of GetAccountAsOf.
+func (mr *MockDataSourceIMockRecorder) GetAccountAsOf(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountAsOf", reflect.TypeOf((*MockDataSourceI)(nil).GetAccountAsOf), arg0, arg1)
+}
+
+// GetBalance mocks base method.
+func (m *MockDataSourceI) GetBalance(arg0 int, arg1 time.Time) (model.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", arg0, arg1)
+	ret0, _ := ret[0].(model.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockDataSourceIMockRecorder) GetBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockDataSourceI)(nil).GetBalance), arg0, arg1)
+}
+
+// GetBudgets mocks base method.
+func (m *MockDataSourceI) GetBudgets(arg0 int) ([]model.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudgets", arg0)
+	ret0, _ := ret[0].([]model.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBudgets indicates an expected call of GetBudgets.
+func (mr *MockDataSourceIMockRecorder) GetBudgets(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudgets", reflect.TypeOf((*MockDataSourceI)(nil).GetBudgets), arg0)
+}
+
+// GetDueStandingOrders mocks base method.
+func (m *MockDataSourceI) GetDueStandingOrders(arg0 time.Time, arg1 int) ([]model.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueStandingOrders", arg0, arg1)
+	ret0, _ := ret[0].([]model.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueStandingOrders indicates an expected call of GetDueStandingOrders.
+func (mr *MockDataSourceIMockRecorder) GetDueStandingOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueStandingOrders", reflect.TypeOf((*MockDataSourceI)(nil).GetDueStandingOrders), arg0, arg1)
+}
+
+// GetExpiredHolds mocks base method.
+func (m *MockDataSourceI) GetExpiredHolds(arg0 time.Time, arg1 int) ([]model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredHolds", arg0, arg1)
+	ret0, _ := ret[0].([]model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredHolds indicates an expected call of GetExpiredHolds.
+func (mr *MockDataSourceIMockRecorder) GetExpiredHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredHolds", reflect.TypeOf((*MockDataSourceI)(nil).GetExpiredHolds), arg0, arg1)
+}
+
+// GetHold mocks base method.
+func (m *MockDataSourceI) GetHold(arg0 int64) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHold", arg0)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockDataSourceIMockRecorder) GetHold(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockDataSourceI)(nil).GetHold), arg0)
+}
+
+// GetHolds mocks base method.
+func (m *MockDataSourceI) GetHolds(arg0 int, arg1 string) ([]model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHolds", arg0, arg1)
+	ret0, _ := ret[0].([]model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHolds indicates an expected call of GetHolds.
+func (mr *MockDataSourceIMockRecorder) GetHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHolds", reflect.TypeOf((*MockDataSourceI)(nil).GetHolds), arg0, arg1)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockDataSourceI) GetIdempotencyKey(arg0, arg1 string) (*model.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(*model.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockDataSourceIMockRecorder) GetIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockDataSourceI)(nil).GetIdempotencyKey), arg0, arg1)
+}
+
+// GetInterestAccruals mocks base method.
+func (m *MockDataSourceI) GetInterestAccruals(arg0 string) ([]model.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInterestAccruals", arg0)
+	ret0, _ := ret[0].([]model.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInterestAccruals indicates an expected call of GetInterestAccruals.
+func (mr *MockDataSourceIMockRecorder) GetInterestAccruals(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterestAccruals", reflect.TypeOf((*MockDataSourceI)(nil).GetInterestAccruals), arg0)
+}
+
+// GetJournalMismatches mocks base method.
+func (m *MockDataSourceI) GetJournalMismatches() ([]model.JournalLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJournalMismatches")
+	ret0, _ := ret[0].([]model.JournalLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJournalMismatches indicates an expected call of GetJournalMismatches.
+func (mr *MockDataSourceIMockRecorder) GetJournalMismatches() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJournalMismatches", reflect.TypeOf((*MockDataSourceI)(nil).GetJournalMismatches))
+}
+
+// GetLedgerMismatches mocks base method.
+func (m *MockDataSourceI) GetLedgerMismatches(arg0 int) ([]model.ReconciliationLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerMismatches", arg0)
+	ret0, _ := ret[0].([]model.ReconciliationLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerMismatches indicates an expected call of GetLedgerMismatches.
+func (mr *MockDataSourceIMockRecorder) GetLedgerMismatches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerMismatches", reflect.TypeOf((*MockDataSourceI)(nil).GetLedgerMismatches), arg0)
+}
+
+// GetMemberAccounts mocks base method.
+func (m *MockDataSourceI) GetMemberAccounts(arg0 string, arg1 int) ([]model.MemberAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]model.MemberAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberAccounts indicates an expected call of GetMemberAccounts.
+func (mr *MockDataSourceIMockRecorder) GetMemberAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberAccounts", reflect.TypeOf((*MockDataSourceI)(nil).GetMemberAccounts), arg0, arg1)
+}
+
+// GetMembers mocks base method.
+func (m *MockDataSourceI) GetMembers(arg0 int) ([]model.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", arg0)
+	ret0, _ := ret[0].([]model.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockDataSourceIMockRecorder) GetMembers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockDataSourceI)(nil).GetMembers), arg0)
+}
+
+// GetSpendLimits mocks base method.
+func (m *MockDataSourceI) GetSpendLimits(arg0 int) (*model.SpendLimitOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpendLimits", arg0)
+	ret0, _ := ret[0].(*model.SpendLimitOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpendLimits indicates an expected call of GetSpendLimits.
+func (mr *MockDataSourceIMockRecorder) GetSpendLimits(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpendLimits", reflect.TypeOf((*MockDataSourceI)(nil).GetSpendLimits), arg0)
+}
+
+// GetStandingOrders mocks base method.
+func (m *MockDataSourceI) GetStandingOrders(arg0 int) ([]model.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStandingOrders", arg0)
+	ret0, _ := ret[0].([]model.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStandingOrders indicates an expected call of GetStandingOrders.
+func (mr *MockDataSourceIMockRecorder) GetStandingOrders(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStandingOrders", reflect.TypeOf((*MockDataSourceI)(nil).GetStandingOrders), arg0)
+}
+
+// GetTransactionTotals mocks base method.
+func (m *MockDataSourceI) GetTransactionTotals(arg0 int, arg1, arg2 time.Time) ([]model.TransactionTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionTotals", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.TransactionTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionTotals indicates an expected call of GetTransactionTotals.
+func (mr *MockDataSourceIMockRecorder) GetTransactionTotals(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionTotals", reflect.TypeOf((*MockDataSourceI)(nil).GetTransactionTotals), arg0, arg1, arg2)
+}
+
+// GetTransactions mocks base method.
+func (m *MockDataSourceI) GetTransactions(arg0 model.TransactionFilter) ([]model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactions", arg0)
+	ret0, _ := ret[0].([]model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactions indicates an expected call of GetTransactions.
+func (mr *MockDataSourceIMockRecorder) GetTransactions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockDataSourceI)(nil).GetTransactions), arg0)
+}
+
+// GetTrialBalance mocks base method.
+func (m *MockDataSourceI) GetTrialBalance() ([]model.TrialBalanceLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrialBalance")
+	ret0, _ := ret[0].([]model.TrialBalanceLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrialBalance indicates an expected call of GetTrialBalance.
+func (mr *MockDataSourceIMockRecorder) GetTrialBalance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrialBalance", reflect.TypeOf((*MockDataSourceI)(nil).GetTrialBalance))
+}
+
 // HealthCheck mocks base method.
 func (m *MockDataSourceI) HealthCheck() bool {
 	m.ctrl.T.Helper()
@@ -64,11 +450,12 @@ func (mr *MockDataSourceIMockRecorder) HealthCheck() *gomock.Call {
 }
 
 // Insert mocks base method.
-func (m *MockDataSourceI) Insert(arg0 model.Account) error {
+func (m *MockDataSourceI) Insert(arg0 model.Account) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Insert", arg0)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Insert indicates an expected call of Insert.
@@ -77,6 +464,271 @@ func (mr *MockDataSourceIMockRecorder) Insert(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockDataSourceI)(nil).Insert), arg0)
 }
 
+// InsertBudget mocks base method.
+func (m *MockDataSourceI) InsertBudget(arg0 model.Budget) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBudget", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertBudget indicates an expected call of InsertBudget.
+func (mr *MockDataSourceIMockRecorder) InsertBudget(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBudget", reflect.TypeOf((*MockDataSourceI)(nil).InsertBudget), arg0)
+}
+
+// InsertDebit mocks base method.
+func (m *MockDataSourceI) InsertDebit(arg0 model.SpendLimits, arg1 time.Time, arg2 model.Transaction, arg3 ...model.Transaction) ([]int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InsertDebit", varargs...)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertDebit indicates an expected call of InsertDebit.
+func (mr *MockDataSourceIMockRecorder) InsertDebit(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDebit", reflect.TypeOf((*MockDataSourceI)(nil).InsertDebit), varargs...)
+}
+
+// InsertHold mocks base method.
+func (m *MockDataSourceI) InsertHold(arg0 model.SpendLimits, arg1 time.Time, arg2 model.Hold) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertHold", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertHold indicates an expected call of InsertHold.
+func (mr *MockDataSourceIMockRecorder) InsertHold(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertHold", reflect.TypeOf((*MockDataSourceI)(nil).InsertHold), arg0, arg1, arg2)
+}
+
+// InsertIdempotencyKey mocks base method.
+func (m *MockDataSourceI) InsertIdempotencyKey(arg0 model.IdempotencyRecord) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertIdempotencyKey", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertIdempotencyKey indicates an expected call of InsertIdempotencyKey.
+func (mr *MockDataSourceIMockRecorder) InsertIdempotencyKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertIdempotencyKey", reflect.TypeOf((*MockDataSourceI)(nil).InsertIdempotencyKey), arg0)
+}
+
+// InsertMember mocks base method.
+func (m *MockDataSourceI) InsertMember(arg0 model.Member) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMember", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertMember indicates an expected call of InsertMember.
+func (mr *MockDataSourceIMockRecorder) InsertMember(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMember", reflect.TypeOf((*MockDataSourceI)(nil).InsertMember), arg0)
+}
+
+// InsertStandingOrder mocks base method.
+func (m *MockDataSourceI) InsertStandingOrder(arg0 model.StandingOrder) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertStandingOrder", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertStandingOrder indicates an expected call of InsertStandingOrder.
+func (mr *MockDataSourceIMockRecorder) InsertStandingOrder(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertStandingOrder", reflect.TypeOf((*MockDataSourceI)(nil).InsertStandingOrder), arg0)
+}
+
+// InsertTransaction mocks base method.
+func (m *MockDataSourceI) InsertTransaction(arg0 model.Transaction) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTransaction", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTransaction indicates an expected call of InsertTransaction.
+func (mr *MockDataSourceIMockRecorder) InsertTransaction(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransaction", reflect.TypeOf((*MockDataSourceI)(nil).InsertTransaction), arg0)
+}
+
+// InsertTransactionWithFees mocks base method.
+func (m *MockDataSourceI) InsertTransactionWithFees(arg0 model.Transaction, arg1 ...model.Transaction) ([]int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InsertTransactionWithFees", varargs...)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTransactionWithFees indicates an expected call of InsertTransactionWithFees.
+func (mr *MockDataSourceIMockRecorder) InsertTransactionWithFees(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransactionWithFees", reflect.TypeOf((*MockDataSourceI)(nil).InsertTransactionWithFees), varargs...)
+}
+
+// InsertTransactions mocks base method.
+func (m *MockDataSourceI) InsertTransactions(arg0 ...model.Transaction) ([]int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InsertTransactions", varargs...)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTransactions indicates an expected call of InsertTransactions.
+func (mr *MockDataSourceIMockRecorder) InsertTransactions(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransactions", reflect.TypeOf((*MockDataSourceI)(nil).InsertTransactions), arg0...)
+}
+
+// InsertTransfer mocks base method.
+func (m *MockDataSourceI) InsertTransfer(arg0 model.SpendLimits, arg1 time.Time, arg2, arg3 model.Transaction) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTransfer", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTransfer indicates an expected call of InsertTransfer.
+func (mr *MockDataSourceIMockRecorder) InsertTransfer(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransfer", reflect.TypeOf((*MockDataSourceI)(nil).InsertTransfer), arg0, arg1, arg2, arg3)
+}
+
+// PostInterest mocks base method.
+func (m *MockDataSourceI) PostInterest(arg0 model.InterestAccrual) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostInterest", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostInterest indicates an expected call of PostInterest.
+func (mr *MockDataSourceIMockRecorder) PostInterest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInterest", reflect.TypeOf((*MockDataSourceI)(nil).PostInterest), arg0)
+}
+
+// RecordStandingOrderRun mocks base method.
+func (m *MockDataSourceI) RecordStandingOrderRun(arg0 int64, arg1 time.Time, arg2 int64, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordStandingOrderRun", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordStandingOrderRun indicates an expected call of RecordStandingOrderRun.
+func (mr *MockDataSourceIMockRecorder) RecordStandingOrderRun(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordStandingOrderRun", reflect.TypeOf((*MockDataSourceI)(nil).RecordStandingOrderRun), arg0, arg1, arg2, arg3)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockDataSourceI) ReleaseHold(arg0 int64, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockDataSourceIMockRecorder) ReleaseHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockDataSourceI)(nil).ReleaseHold), arg0, arg1)
+}
+
+// RepairTotals mocks base method.
+func (m *MockDataSourceI) RepairTotals(arg0 int) (model.ReconciliationLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairTotals", arg0)
+	ret0, _ := ret[0].(model.ReconciliationLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairTotals indicates an expected call of RepairTotals.
+func (mr *MockDataSourceIMockRecorder) RepairTotals(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairTotals", reflect.TypeOf((*MockDataSourceI)(nil).RepairTotals), arg0)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockDataSourceI) ReverseTransaction(arg0 int64, arg1 model.Money, arg2 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockDataSourceIMockRecorder) ReverseTransaction(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockDataSourceI)(nil).ReverseTransaction), arg0, arg1, arg2)
+}
+
+// SetAccountStatus mocks base method.
+func (m *MockDataSourceI) SetAccountStatus(arg0 int, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountStatus", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAccountStatus indicates an expected call of SetAccountStatus.
+func (mr *MockDataSourceIMockRecorder) SetAccountStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountStatus", reflect.TypeOf((*MockDataSourceI)(nil).SetAccountStatus), arg0, arg1)
+}
+
+// SetDefaultAccount mocks base method.
+func (m *MockDataSourceI) SetDefaultAccount(arg0 string, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaultAccount indicates an expected call of SetDefaultAccount.
+func (mr *MockDataSourceIMockRecorder) SetDefaultAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultAccount", reflect.TypeOf((*MockDataSourceI)(nil).SetDefaultAccount), arg0, arg1)
+}
+
 // Update mocks base method.
 func (m *MockDataSourceI) Update(arg0, arg1 map[string]interface{}) error {
 	m.ctrl.T.Helper()
@@ -90,3 +742,65 @@ func (mr *MockDataSourceIMockRecorder) Update(arg0, arg1 interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDataSourceI)(nil).Update), arg0, arg1)
 }
+
+// UpdateBudget mocks base method.
+func (m *MockDataSourceI) UpdateBudget(arg0 model.Budget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBudget", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBudget indicates an expected call of UpdateBudget.
+func (mr *MockDataSourceIMockRecorder) UpdateBudget(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBudget", reflect.TypeOf((*MockDataSourceI)(nil).UpdateBudget), arg0)
+}
+
+// UpdateIdempotencyKey mocks base method.
+func (m *MockDataSourceI) UpdateIdempotencyKey(arg0 model.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdempotencyKey", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIdempotencyKey indicates an expected call of UpdateIdempotencyKey.
+func (mr *MockDataSourceIMockRecorder) UpdateIdempotencyKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdempotencyKey", reflect.TypeOf((*MockDataSourceI)(nil).UpdateIdempotencyKey), arg0)
+}
+
+// UpdateServices mocks base method.
+func (m *MockDataSourceI) UpdateServices(arg0 int, arg1 map[string]interface{}, arg2 ...model.Transaction) ([]int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateServices", varargs...)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateServices indicates an expected call of UpdateServices.
+func (mr *MockDataSourceIMockRecorder) UpdateServices(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServices", reflect.TypeOf((*MockDataSourceI)(nil).UpdateServices), varargs...)
+}
+
+// UpsertSpendLimits mocks base method.
+func (m *MockDataSourceI) UpsertSpendLimits(arg0 model.SpendLimitOverride) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertSpendLimits", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertSpendLimits indicates an expected call of UpsertSpendLimits.
+func (mr *MockDataSourceIMockRecorder) UpsertSpendLimits(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSpendLimits", reflect.TypeOf((*MockDataSourceI)(nil).UpsertSpendLimits), arg0)
+}
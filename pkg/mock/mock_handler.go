@@ -46,6 +46,66 @@ func (mr *MockAccountManagmentSvcHandlerMockRecorder) AccountSummary(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountSummary", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).AccountSummary), arg0, arg1)
 }
 
+// Analytics mocks base method.
+func (m *MockAccountManagmentSvcHandler) Analytics(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Analytics", arg0, arg1)
+}
+
+// Analytics indicates an expected call of Analytics.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) Analytics(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Analytics", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).Analytics), arg0, arg1)
+}
+
+// Budgets mocks base method.
+func (m *MockAccountManagmentSvcHandler) Budgets(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Budgets", arg0, arg1)
+}
+
+// Budgets indicates an expected call of Budgets.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) Budgets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Budgets", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).Budgets), arg0, arg1)
+}
+
+// CancelStandingOrder mocks base method.
+func (m *MockAccountManagmentSvcHandler) CancelStandingOrder(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CancelStandingOrder", arg0, arg1)
+}
+
+// CancelStandingOrder indicates an expected call of CancelStandingOrder.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) CancelStandingOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelStandingOrder", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).CancelStandingOrder), arg0, arg1)
+}
+
+// CaptureHold mocks base method.
+func (m *MockAccountManagmentSvcHandler) CaptureHold(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CaptureHold", arg0, arg1)
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) CaptureHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).CaptureHold), arg0, arg1)
+}
+
+// CloseAccount mocks base method.
+func (m *MockAccountManagmentSvcHandler) CloseAccount(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CloseAccount", arg0, arg1)
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) CloseAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).CloseAccount), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockAccountManagmentSvcHandler) CreateAccount(arg0 http.ResponseWriter, arg1 *http.Request) {
 	m.ctrl.T.Helper()
@@ -58,6 +118,42 @@ func (mr *MockAccountManagmentSvcHandlerMockRecorder) CreateAccount(arg0, arg1 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).CreateAccount), arg0, arg1)
 }
 
+// CreateBudget mocks base method.
+func (m *MockAccountManagmentSvcHandler) CreateBudget(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateBudget", arg0, arg1)
+}
+
+// CreateBudget indicates an expected call of CreateBudget.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) CreateBudget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBudget", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).CreateBudget), arg0, arg1)
+}
+
+// CreateStandingOrder mocks base method.
+func (m *MockAccountManagmentSvcHandler) CreateStandingOrder(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateStandingOrder", arg0, arg1)
+}
+
+// CreateStandingOrder indicates an expected call of CreateStandingOrder.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) CreateStandingOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStandingOrder", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).CreateStandingOrder), arg0, arg1)
+}
+
+// DeleteBudget mocks base method.
+func (m *MockAccountManagmentSvcHandler) DeleteBudget(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteBudget", arg0, arg1)
+}
+
+// DeleteBudget indicates an expected call of DeleteBudget.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) DeleteBudget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBudget", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).DeleteBudget), arg0, arg1)
+}
+
 // HealthCheck mocks base method.
 func (m *MockAccountManagmentSvcHandler) HealthCheck() (string, string, bool) {
 	m.ctrl.T.Helper()
@@ -74,6 +170,198 @@ func (mr *MockAccountManagmentSvcHandlerMockRecorder) HealthCheck() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).HealthCheck))
 }
 
+// ImportTransactions mocks base method.
+func (m *MockAccountManagmentSvcHandler) ImportTransactions(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ImportTransactions", arg0, arg1)
+}
+
+// ImportTransactions indicates an expected call of ImportTransactions.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) ImportTransactions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTransactions", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).ImportTransactions), arg0, arg1)
+}
+
+// InviteMember mocks base method.
+func (m *MockAccountManagmentSvcHandler) InviteMember(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InviteMember", arg0, arg1)
+}
+
+// InviteMember indicates an expected call of InviteMember.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) InviteMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteMember", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).InviteMember), arg0, arg1)
+}
+
+// Members mocks base method.
+func (m *MockAccountManagmentSvcHandler) Members(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Members", arg0, arg1)
+}
+
+// Members indicates an expected call of Members.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) Members(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Members", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).Members), arg0, arg1)
+}
+
+// OpenAccount mocks base method.
+func (m *MockAccountManagmentSvcHandler) OpenAccount(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OpenAccount", arg0, arg1)
+}
+
+// OpenAccount indicates an expected call of OpenAccount.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) OpenAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenAccount", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).OpenAccount), arg0, arg1)
+}
+
+// PlaceHold mocks base method.
+func (m *MockAccountManagmentSvcHandler) PlaceHold(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PlaceHold", arg0, arg1)
+}
+
+// PlaceHold indicates an expected call of PlaceHold.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) PlaceHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceHold", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).PlaceHold), arg0, arg1)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockAccountManagmentSvcHandler) ReleaseHold(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReleaseHold", arg0, arg1)
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) ReleaseHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).ReleaseHold), arg0, arg1)
+}
+
+// RemoveMember mocks base method.
+func (m *MockAccountManagmentSvcHandler) RemoveMember(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveMember", arg0, arg1)
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) RemoveMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).RemoveMember), arg0, arg1)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockAccountManagmentSvcHandler) ReverseTransaction(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReverseTransaction", arg0, arg1)
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) ReverseTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).ReverseTransaction), arg0, arg1)
+}
+
+// SetDefaultAccount mocks base method.
+func (m *MockAccountManagmentSvcHandler) SetDefaultAccount(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDefaultAccount", arg0, arg1)
+}
+
+// SetDefaultAccount indicates an expected call of SetDefaultAccount.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) SetDefaultAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultAccount", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).SetDefaultAccount), arg0, arg1)
+}
+
+// StandingOrders mocks base method.
+func (m *MockAccountManagmentSvcHandler) StandingOrders(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StandingOrders", arg0, arg1)
+}
+
+// StandingOrders indicates an expected call of StandingOrders.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) StandingOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StandingOrders", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).StandingOrders), arg0, arg1)
+}
+
+// Statement mocks base method.
+func (m *MockAccountManagmentSvcHandler) Statement(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Statement", arg0, arg1)
+}
+
+// Statement indicates an expected call of Statement.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) Statement(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statement", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).Statement), arg0, arg1)
+}
+
+// TransactionHistory mocks base method.
+func (m *MockAccountManagmentSvcHandler) TransactionHistory(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TransactionHistory", arg0, arg1)
+}
+
+// TransactionHistory indicates an expected call of TransactionHistory.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) TransactionHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionHistory", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).TransactionHistory), arg0, arg1)
+}
+
+// Transfer mocks base method.
+func (m *MockAccountManagmentSvcHandler) Transfer(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Transfer", arg0, arg1)
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) Transfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).Transfer), arg0, arg1)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockAccountManagmentSvcHandler) UpdateAccountStatus(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateAccountStatus", arg0, arg1)
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) UpdateAccountStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).UpdateAccountStatus), arg0, arg1)
+}
+
+// UpdateBudget mocks base method.
+func (m *MockAccountManagmentSvcHandler) UpdateBudget(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateBudget", arg0, arg1)
+}
+
+// UpdateBudget indicates an expected call of UpdateBudget.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) UpdateBudget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBudget", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).UpdateBudget), arg0, arg1)
+}
+
+// UpdateOverdraftLimit mocks base method.
+func (m *MockAccountManagmentSvcHandler) UpdateOverdraftLimit(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateOverdraftLimit", arg0, arg1)
+}
+
+// UpdateOverdraftLimit indicates an expected call of UpdateOverdraftLimit.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) UpdateOverdraftLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOverdraftLimit", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).UpdateOverdraftLimit), arg0, arg1)
+}
+
 // UpdateService mocks base method.
 func (m *MockAccountManagmentSvcHandler) UpdateService(arg0 http.ResponseWriter, arg1 *http.Request) {
 	m.ctrl.T.Helper()
@@ -86,6 +374,18 @@ func (mr *MockAccountManagmentSvcHandlerMockRecorder) UpdateService(arg0, arg1 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).UpdateService), arg0, arg1)
 }
 
+// UpdateSpendLimits mocks base method.
+func (m *MockAccountManagmentSvcHandler) UpdateSpendLimits(arg0 http.ResponseWriter, arg1 *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateSpendLimits", arg0, arg1)
+}
+
+// UpdateSpendLimits indicates an expected call of UpdateSpendLimits.
+func (mr *MockAccountManagmentSvcHandlerMockRecorder) UpdateSpendLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpendLimits", reflect.TypeOf((*MockAccountManagmentSvcHandler)(nil).UpdateSpendLimits), arg0, arg1)
+}
+
 // UpdateTransaction mocks base method.
 func (m *MockAccountManagmentSvcHandler) UpdateTransaction(arg0 http.ResponseWriter, arg1 *http.Request) {
 	m.ctrl.T.Helper()
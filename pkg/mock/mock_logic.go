@@ -6,6 +6,7 @@ package mock
 
 import (
 	reflect "reflect"
+	time "time"
 
 	model "github.com/PereRohit/util/model"
 	gomock "github.com/golang/mock/gomock"
@@ -36,17 +37,99 @@ func (m *MockAccountManagmentSvcLogicIer) EXPECT() *MockAccountManagmentSvcLogic
 }
 
 // AccountDetails mocks base method.
-func (m *MockAccountManagmentSvcLogicIer) AccountDetails(arg0 string) *model.Response {
+func (m *MockAccountManagmentSvcLogicIer) AccountDetails(arg0 string, arg1 int, arg2 time.Time) *model.Response {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AccountDetails", arg0)
+	ret := m.ctrl.Call(m, "AccountDetails", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*model.Response)
 	return ret0
 }
 
 // AccountDetails indicates an expected call of AccountDetails.
-func (mr *MockAccountManagmentSvcLogicIerMockRecorder) AccountDetails(arg0 interface{}) *gomock.Call {
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) AccountDetails(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountDetails", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).AccountDetails), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountDetails", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).AccountDetails), arg0, arg1, arg2)
+}
+
+// AccrueInterest mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) AccrueInterest(arg0 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AccrueInterest", arg0)
+}
+
+// AccrueInterest indicates an expected call of AccrueInterest.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) AccrueInterest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueInterest", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).AccrueInterest), arg0)
+}
+
+// Analytics mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) Analytics(arg0 string, arg1 int, arg2, arg3 time.Time) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Analytics", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// Analytics indicates an expected call of Analytics.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) Analytics(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Analytics", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).Analytics), arg0, arg1, arg2, arg3)
+}
+
+// Budgets mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) Budgets(arg0 string, arg1 int) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Budgets", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// Budgets indicates an expected call of Budgets.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) Budgets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Budgets", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).Budgets), arg0, arg1)
+}
+
+// CancelStandingOrder mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) CancelStandingOrder(arg0 string, arg1 int, arg2 int64) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelStandingOrder", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// CancelStandingOrder indicates an expected call of CancelStandingOrder.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) CancelStandingOrder(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelStandingOrder", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).CancelStandingOrder), arg0, arg1, arg2)
+}
+
+// CaptureHold mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) CaptureHold(arg0 model0.CaptureHold) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) CaptureHold(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).CaptureHold), arg0)
+}
+
+// CloseAccount mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) CloseAccount(arg0 model0.AccountClosure) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccount", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) CloseAccount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).CloseAccount), arg0)
 }
 
 // CreateAccount mocks base method.
@@ -63,6 +146,60 @@ func (mr *MockAccountManagmentSvcLogicIerMockRecorder) CreateAccount(arg0 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).CreateAccount), arg0)
 }
 
+// CreateBudget mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) CreateBudget(arg0 string, arg1 int, arg2 model0.NewBudget) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBudget", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// CreateBudget indicates an expected call of CreateBudget.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) CreateBudget(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBudget", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).CreateBudget), arg0, arg1, arg2)
+}
+
+// CreateStandingOrder mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) CreateStandingOrder(arg0 string, arg1 model0.NewStandingOrder) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStandingOrder", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// CreateStandingOrder indicates an expected call of CreateStandingOrder.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) CreateStandingOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStandingOrder", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).CreateStandingOrder), arg0, arg1)
+}
+
+// DeleteBudget mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) DeleteBudget(arg0 string, arg1 int, arg2 int64) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBudget", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// DeleteBudget indicates an expected call of DeleteBudget.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) DeleteBudget(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBudget", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).DeleteBudget), arg0, arg1, arg2)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) ExpireHolds(arg0 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ExpireHolds", arg0)
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) ExpireHolds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).ExpireHolds), arg0)
+}
+
 // HealthCheck mocks base method.
 func (m *MockAccountManagmentSvcLogicIer) HealthCheck() bool {
 	m.ctrl.T.Helper()
@@ -77,6 +214,282 @@ func (mr *MockAccountManagmentSvcLogicIerMockRecorder) HealthCheck() *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).HealthCheck))
 }
 
+// ImportTransactions mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) ImportTransactions(arg0 []model0.ImportRow, arg1 bool) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTransactions", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// ImportTransactions indicates an expected call of ImportTransactions.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) ImportTransactions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTransactions", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).ImportTransactions), arg0, arg1)
+}
+
+// InterestReport mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) InterestReport(arg0, arg1 time.Time, arg2 int, arg3 bool) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InterestReport", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// InterestReport indicates an expected call of InterestReport.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) InterestReport(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InterestReport", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).InterestReport), arg0, arg1, arg2, arg3)
+}
+
+// InviteMember mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) InviteMember(arg0 string, arg1 int, arg2 model0.InviteMember) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// InviteMember indicates an expected call of InviteMember.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) InviteMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteMember", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).InviteMember), arg0, arg1, arg2)
+}
+
+// Members mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) Members(arg0 string, arg1 int) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Members", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// Members indicates an expected call of Members.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) Members(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Members", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).Members), arg0, arg1)
+}
+
+// OpenAccount mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) OpenAccount(arg0 string, arg1 model0.OpenAccount) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenAccount", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// OpenAccount indicates an expected call of OpenAccount.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) OpenAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenAccount", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).OpenAccount), arg0, arg1)
+}
+
+// PlaceHold mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) PlaceHold(arg0 model0.NewHold) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceHold", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// PlaceHold indicates an expected call of PlaceHold.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) PlaceHold(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceHold", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).PlaceHold), arg0)
+}
+
+// PurgeIdempotencyKeys mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) PurgeIdempotencyKeys(arg0 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PurgeIdempotencyKeys", arg0)
+}
+
+// PurgeIdempotencyKeys indicates an expected call of PurgeIdempotencyKeys.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) PurgeIdempotencyKeys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeIdempotencyKeys", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).PurgeIdempotencyKeys), arg0)
+}
+
+// Reconcile mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) Reconcile(arg0 int, arg1 bool) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) Reconcile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).Reconcile), arg0, arg1)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) ReleaseHold(arg0 model0.ReleaseHold) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) ReleaseHold(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).ReleaseHold), arg0)
+}
+
+// RemoveMember mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) RemoveMember(arg0 string, arg1 int, arg2 string) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) RemoveMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).RemoveMember), arg0, arg1, arg2)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) ReverseTransaction(arg0 model0.Reversal) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) ReverseTransaction(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).ReverseTransaction), arg0)
+}
+
+// RunStandingOrders mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) RunStandingOrders(arg0 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunStandingOrders", arg0)
+}
+
+// RunStandingOrders indicates an expected call of RunStandingOrders.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) RunStandingOrders(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunStandingOrders", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).RunStandingOrders), arg0)
+}
+
+// SetDefaultAccount mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) SetDefaultAccount(arg0 string, arg1 model0.DefaultAccount) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultAccount", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// SetDefaultAccount indicates an expected call of SetDefaultAccount.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) SetDefaultAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultAccount", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).SetDefaultAccount), arg0, arg1)
+}
+
+// StandingOrders mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) StandingOrders(arg0 string, arg1 int) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StandingOrders", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// StandingOrders indicates an expected call of StandingOrders.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) StandingOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StandingOrders", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).StandingOrders), arg0, arg1)
+}
+
+// Statement mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) Statement(arg0 string, arg1 int, arg2 time.Time, arg3 string) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statement", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// Statement indicates an expected call of Statement.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) Statement(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statement", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).Statement), arg0, arg1, arg2, arg3)
+}
+
+// TransactionHistory mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) TransactionHistory(arg0 string, arg1 model0.TransactionFilter) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionHistory", arg0, arg1)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// TransactionHistory indicates an expected call of TransactionHistory.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) TransactionHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionHistory", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).TransactionHistory), arg0, arg1)
+}
+
+// Transfer mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) Transfer(arg0 model0.Transfer) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) Transfer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).Transfer), arg0)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) UpdateAccountStatus(arg0 model0.UpdateAccountStatus) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) UpdateAccountStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).UpdateAccountStatus), arg0)
+}
+
+// UpdateBudget mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) UpdateBudget(arg0 string, arg1 int, arg2 int64, arg3 model0.UpdateBudget) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBudget", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// UpdateBudget indicates an expected call of UpdateBudget.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) UpdateBudget(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBudget", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).UpdateBudget), arg0, arg1, arg2, arg3)
+}
+
+// UpdateOverdraftLimit mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) UpdateOverdraftLimit(arg0 model0.OverdraftLimit) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOverdraftLimit", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// UpdateOverdraftLimit indicates an expected call of UpdateOverdraftLimit.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) UpdateOverdraftLimit(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOverdraftLimit", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).UpdateOverdraftLimit), arg0)
+}
+
 // UpdateServices mocks base method.
 func (m *MockAccountManagmentSvcLogicIer) UpdateServices(arg0 string, arg1 model0.UpdateServices) *model.Response {
 	m.ctrl.T.Helper()
@@ -91,6 +504,20 @@ func (mr *MockAccountManagmentSvcLogicIerMockRecorder) UpdateServices(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServices", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).UpdateServices), arg0, arg1)
 }
 
+// UpdateSpendLimits mocks base method.
+func (m *MockAccountManagmentSvcLogicIer) UpdateSpendLimits(arg0 model0.UpdateSpendLimits) *model.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSpendLimits", arg0)
+	ret0, _ := ret[0].(*model.Response)
+	return ret0
+}
+
+// UpdateSpendLimits indicates an expected call of UpdateSpendLimits.
+func (mr *MockAccountManagmentSvcLogicIerMockRecorder) UpdateSpendLimits(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSpendLimits", reflect.TypeOf((*MockAccountManagmentSvcLogicIer)(nil).UpdateSpendLimits), arg0)
+}
+
 // UpdateTransaction mocks base method.
 func (m *MockAccountManagmentSvcLogicIer) UpdateTransaction(arg0 model0.UpdateTransaction) *model.Response {
 	m.ctrl.T.Helper()
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/vatsal278/msgbroker/pkg/sdk (interfaces: MsgBrokerSvcI)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	rsa "crypto/rsa"
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMsgBrokerSvcI is a mock of MsgBrokerSvcI interface.
+type MockMsgBrokerSvcI struct {
+	ctrl     *gomock.Controller
+	recorder *MockMsgBrokerSvcIMockRecorder
+}
+
+// MockMsgBrokerSvcIMockRecorder is the mock recorder for MockMsgBrokerSvcI.
+type MockMsgBrokerSvcIMockRecorder struct {
+	mock *MockMsgBrokerSvcI
+}
+
+// NewMockMsgBrokerSvcI creates a new mock instance.
+func NewMockMsgBrokerSvcI(ctrl *gomock.Controller) *MockMsgBrokerSvcI {
+	mock := &MockMsgBrokerSvcI{ctrl: ctrl}
+	mock.recorder = &MockMsgBrokerSvcIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMsgBrokerSvcI) EXPECT() *MockMsgBrokerSvcIMockRecorder {
+	return m.recorder
+}
+
+// ExtractMsg mocks base method.
+func (m *MockMsgBrokerSvcI) ExtractMsg(arg0 *rsa.PrivateKey) func(io.ReadCloser) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtractMsg", arg0)
+	ret0, _ := ret[0].(func(io.ReadCloser) (string, error))
+	return ret0
+}
+
+// ExtractMsg indicates an expected call of ExtractMsg.
+func (mr *MockMsgBrokerSvcIMockRecorder) ExtractMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtractMsg", reflect.TypeOf((*MockMsgBrokerSvcI)(nil).ExtractMsg), arg0)
+}
+
+// PushMsg mocks base method.
+func (m *MockMsgBrokerSvcI) PushMsg(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushMsg", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushMsg indicates an expected call of PushMsg.
+func (mr *MockMsgBrokerSvcIMockRecorder) PushMsg(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushMsg", reflect.TypeOf((*MockMsgBrokerSvcI)(nil).PushMsg), arg0, arg1, arg2)
+}
+
+// RegisterPub mocks base method.
+func (m *MockMsgBrokerSvcI) RegisterPub(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterPub", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterPub indicates an expected call of RegisterPub.
+func (mr *MockMsgBrokerSvcIMockRecorder) RegisterPub(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterPub", reflect.TypeOf((*MockMsgBrokerSvcI)(nil).RegisterPub), arg0)
+}
+
+// RegisterSub mocks base method.
+func (m *MockMsgBrokerSvcI) RegisterSub(arg0, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterSub", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterSub indicates an expected call of RegisterSub.
+func (mr *MockMsgBrokerSvcIMockRecorder) RegisterSub(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterSub", reflect.TypeOf((*MockMsgBrokerSvcI)(nil).RegisterSub), arg0, arg1, arg2, arg3)
+}